@@ -5,6 +5,8 @@
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pollapi "github.com/rancher/octopus/pkg/adaptor/poll/api"
 )
 
 // BluetoothDeviceParameters defines the desired parameters of BluetoothDevice.
@@ -16,6 +18,11 @@ type BluetoothDeviceParameters struct {
 	// Specifies default device connection timeout
 	// +kubebuilder:default:30s
 	Timeout v1.Duration `json:"timeout,omitempty"`
+
+	// Specifies how to synchronize the polled values to limb,
+	// the values are synchronized after each polling if it is not specified.
+	// +optional
+	Sync *pollapi.PollSyncOptions `json:"sync,omitempty"`
 }
 
 func (in *BluetoothDeviceParameters) GetSyncInterval() time.Duration {
@@ -138,6 +145,9 @@ type BluetoothDeviceProperty struct {
 	// Specifies the visitor of property.
 	// +kubebuilder:validation:Required
 	Visitor BluetoothDevicePropertyVisitor `json:"visitor"`
+
+	// Specifies the polling of property.
+	pollapi.PollPropertyOptions `json:",inline"`
 }
 
 // BluetoothDeviceStatusProperty defines the observed property of BluetoothDevice.
@@ -185,6 +195,10 @@ type BluetoothDeviceStatus struct {
 	// Reports the status of the BLE device.
 	// +optional
 	Properties []BluetoothDeviceStatusProperty `json:"properties,omitempty"`
+
+	// Reports the status of device extension.
+	// +optional
+	Extension *BluetoothDeviceExtensionStatus `json:"extension,omitempty"`
 }
 
 // +kubebuilder:object:root=true
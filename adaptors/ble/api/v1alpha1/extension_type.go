@@ -8,3 +8,10 @@ type BluetoothDeviceExtension struct {
 	// +optional
 	MQTT *mqttapi.MQTTOptions `json:"mqtt,omitempty"`
 }
+
+// BluetoothDeviceExtensionStatus defines the observed state of device extension.
+type BluetoothDeviceExtensionStatus struct {
+	// Reports the connection status of MQTT extension.
+	// +optional
+	MQTT *mqttapi.MQTTConnectionStatus `json:"mqtt,omitempty"`
+}
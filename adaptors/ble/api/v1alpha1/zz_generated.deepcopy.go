@@ -20,7 +20,8 @@
 package v1alpha1
 
 import (
-	"github.com/rancher/octopus/pkg/mqtt/api"
+	"github.com/rancher/octopus/pkg/adaptor/poll/api"
+	mqttapi "github.com/rancher/octopus/pkg/mqtt/api"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -91,7 +92,7 @@ func (in *BluetoothDeviceExtension) DeepCopyInto(out *BluetoothDeviceExtension)
 	*out = *in
 	if in.MQTT != nil {
 		in, out := &in.MQTT, &out.MQTT
-		*out = new(api.MQTTOptions)
+		*out = new(mqttapi.MQTTOptions)
 		(*in).DeepCopyInto(*out)
 	}
 }
@@ -106,6 +107,26 @@ func (in *BluetoothDeviceExtension) DeepCopy() *BluetoothDeviceExtension {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BluetoothDeviceExtensionStatus) DeepCopyInto(out *BluetoothDeviceExtensionStatus) {
+	*out = *in
+	if in.MQTT != nil {
+		in, out := &in.MQTT, &out.MQTT
+		*out = new(mqttapi.MQTTConnectionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BluetoothDeviceExtensionStatus.
+func (in *BluetoothDeviceExtensionStatus) DeepCopy() *BluetoothDeviceExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BluetoothDeviceExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BluetoothDeviceList) DeepCopyInto(out *BluetoothDeviceList) {
 	*out = *in
@@ -143,6 +164,11 @@ func (in *BluetoothDeviceParameters) DeepCopyInto(out *BluetoothDeviceParameters
 	*out = *in
 	out.SyncInterval = in.SyncInterval
 	out.Timeout = in.Timeout
+	if in.Sync != nil {
+		in, out := &in.Sync, &out.Sync
+		*out = new(api.PollSyncOptions)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BluetoothDeviceParameters.
@@ -159,6 +185,7 @@ func (in *BluetoothDeviceParameters) DeepCopy() *BluetoothDeviceParameters {
 func (in *BluetoothDeviceProperty) DeepCopyInto(out *BluetoothDeviceProperty) {
 	*out = *in
 	in.Visitor.DeepCopyInto(&out.Visitor)
+	in.PollPropertyOptions.DeepCopyInto(&out.PollPropertyOptions)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BluetoothDeviceProperty.
@@ -228,7 +255,7 @@ func (in *BluetoothDeviceSpec) DeepCopyInto(out *BluetoothDeviceSpec) {
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
 		*out = new(BluetoothDeviceParameters)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	out.Protocol = in.Protocol
 	if in.Properties != nil {
@@ -260,6 +287,11 @@ func (in *BluetoothDeviceStatus) DeepCopyInto(out *BluetoothDeviceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Extension != nil {
+		in, out := &in.Extension, &out.Extension
+		*out = new(BluetoothDeviceExtensionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BluetoothDeviceStatus.
@@ -1,6 +1,9 @@
 package adaptor
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/bettercap/gatt"
 	"github.com/bettercap/gatt/examples/option"
 	jsoniter "github.com/json-iterator/go"
@@ -55,6 +58,8 @@ func (s *Service) toJSON(in metav1.Object) []byte {
 
 func (s *Service) Connect(server api.Connection_ConnectServer) error {
 	var holder physical.Device
+	// it is not safe to call Send on the same stream in different goroutines.
+	var sendLock sync.Mutex
 	defer func() {
 		if holder != nil {
 			holder.Shutdown()
@@ -81,6 +86,22 @@ func (s *Service) Connect(server api.Connection_ConnectServer) error {
 			return status.Errorf(codes.InvalidArgument, "invalid model group: %s", modelGVK.Group)
 		}
 
+		// processes action
+		if action := req.GetAction(); action != nil {
+			var resp = &api.ConnectResponseAction{
+				Id:           action.GetId(),
+				ErrorMessage: fmt.Sprintf("unsupported action %s", action.GetName()),
+			}
+
+			sendLock.Lock()
+			err := server.Send(&api.ConnectResponse{Action: resp})
+			sendLock.Unlock()
+			if err != nil {
+				return status.Errorf(codes.Unknown, "failed to send action result to limb, %v", err)
+			}
+			continue
+		}
+
 		// processes device
 		switch modelGVK.Kind {
 		case "BluetoothDevice":
@@ -112,6 +133,8 @@ func (s *Service) Connect(server api.Connection_ConnectServer) error {
 					var respBytes = s.toJSON(resp)
 
 					// send device to limb
+					sendLock.Lock()
+					defer sendLock.Unlock()
 					if err := server.Send(&api.ConnectResponse{Device: respBytes}); err != nil {
 						return status.Errorf(codes.Unknown, "failed to send device to limb, %v", err)
 					}
@@ -10,6 +10,7 @@
 	"k8s.io/apimachinery/pkg/util/runtime"
 
 	"github.com/rancher/octopus/adaptors/ble/pkg/metadata"
+	"github.com/rancher/octopus/pkg/adaptor/command"
 	"github.com/rancher/octopus/pkg/adaptor/socket/handler"
 	"github.com/rancher/octopus/pkg/mqtt"
 	"github.com/rancher/octopus/pkg/util/object"
@@ -19,6 +20,8 @@
 	"k8s.io/apimachinery/pkg/types"
 
 	api "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/adaptor/poll"
+	pollapi "github.com/rancher/octopus/pkg/adaptor/poll/api"
 
 	"github.com/rancher/octopus/adaptors/ble/api/v1alpha1"
 )
@@ -82,7 +85,10 @@ func (d *bleDevice) Configure(references api.ReferencesHandler, device *v1alpha1
 		}
 
 		if newExtension.MQTT != nil {
-			var cli, err = mqtt.NewClient(*newExtension.MQTT, object.GetControlledOwnerObjectReference(device), references)
+			var clientBuilder = mqtt.NewClientBuilder(*newExtension.MQTT, object.GetControlledOwnerObjectReference(device))
+			clientBuilder.Render(references)
+			clientBuilder.OnStatusChanged(d.receiveMQTTStatus)
+			var cli, err = clientBuilder.Build()
 			if err != nil {
 				return errors.Wrap(err, "failed to create MQTT client")
 			}
@@ -91,6 +97,11 @@ func (d *bleDevice) Configure(references api.ReferencesHandler, device *v1alpha1
 			if err != nil {
 				return errors.Wrap(err, "failed to connect MQTT broker")
 			}
+			// receives the writing commands, e.g. the DCMD of Sparkplug B mode.
+			err = cli.Subscribe(nil, d.receiveCommand)
+			if err != nil {
+				return errors.Wrap(err, "failed to subscribe MQTT commands")
+			}
 			d.mqttClient = cli
 		}
 	}
@@ -98,6 +109,55 @@ func (d *bleDevice) Configure(references api.ReferencesHandler, device *v1alpha1
 	return d.refresh(newSpec)
 }
 
+// receiveCommand writes the values of command to the writable properties, and replies the results.
+// The value is written as the default value of property, which must be one of the keys of `dataWrite`.
+func (d *bleDevice) receiveCommand(msg mqtt.SubscribeMessage) {
+	command.Receive(d.log, metadata.Endpoint, msg, func(values map[string]interface{}) mqtt.CommandReply {
+		d.Lock()
+		defer d.Unlock()
+
+		// copies the properties to avoid changing the configured device.
+		var spec = d.instance.Spec
+		spec.Properties = make([]v1alpha1.BluetoothDeviceProperty, len(d.instance.Spec.Properties))
+		copy(spec.Properties, d.instance.Spec.Properties)
+
+		var reply mqtt.CommandReply
+		var written bool
+		for name, value := range values {
+			var result = mqtt.CommandResult{Name: name}
+			var formatted = command.FormatValue(value)
+			if err := setDefaultValue(spec.Properties, name, formatted); err != nil {
+				d.log.Error(err, "Error executing command", "property", name)
+				result.Error = err.Error()
+			} else {
+				result.Value = formatted
+				written = true
+			}
+			reply.Results = append(reply.Results, result)
+		}
+		if written {
+			// writes the default values during scanning
+			var statusProps, err = d.scanDevice(spec)
+			if err != nil {
+				d.log.Error(err, "Error executing command")
+				for i := range reply.Results {
+					if reply.Results[i].Error == "" {
+						reply.Results[i].Value = nil
+						reply.Results[i].Error = err.Error()
+					}
+				}
+			} else {
+				d.instance.Spec = spec
+				d.instance.Status.Properties = statusProps
+				if err := d.sync(); err != nil {
+					d.log.Error(err, "failed to sync")
+				}
+			}
+		}
+		return reply
+	})
+}
+
 func (d *bleDevice) Shutdown() {
 	d.Lock()
 	defer d.Unlock()
@@ -123,10 +183,12 @@ func (d *bleDevice) refresh(newSpec v1alpha1.BluetoothDeviceSpec) error {
 			return err
 		}
 		status = v1alpha1.BluetoothDeviceStatus{Properties: statusProps}
+	} else if getFetchInterval(staleSpec) != getFetchInterval(newSpec) {
+		d.stopFetch()
 	}
 
 	// fetches in backend
-	d.startFetch(newSpec.Parameters.GetSyncInterval())
+	d.startFetch(getFetchInterval(newSpec))
 
 	// records
 	d.instance.Spec = newSpec
@@ -136,6 +198,8 @@ func (d *bleDevice) refresh(newSpec v1alpha1.BluetoothDeviceSpec) error {
 
 // fetch is blocked, it is used to sync the ble device status periodically,
 // it's worth noting that it just reads the properties from bel device.
+// The properties are polled at their own intervals,
+// and the status is synchronized after polling or when any property changes according to the sync mode.
 func (d *bleDevice) fetch(interval time.Duration, stop <-chan struct{}) {
 	defer runtime.HandleCrash(handler.NewPanicsCleanupSocketHandler(metadata.Endpoint))
 
@@ -147,27 +211,64 @@ func (d *bleDevice) fetch(interval time.Duration, stop <-chan struct{}) {
 	var ticker = time.NewTicker(interval)
 	defer ticker.Stop()
 
+	var schedule = poll.NewSchedule(interval)
+	var filter *poll.Filter
+
 	for {
+		var now time.Time
 		select {
 		case <-stop:
 			return
-		case <-ticker.C:
+		case now = <-ticker.C:
 		}
 
 		d.Lock()
 		func() {
 			defer d.Unlock()
 
-			var props, err = d.scanDevice(d.instance.Spec)
-			if err != nil {
-				// TODO give a way to feedback this to limb.
-				d.log.Error(err, "failed to scan device")
-			} else {
-				d.instance.Status.Properties = props
+			var params = d.instance.Spec.Parameters
+			if filter == nil {
+				var options *pollapi.PollSyncOptions
+				if params != nil {
+					options = params.Sync
+				}
+				filter = poll.NewFilter(options)
+			}
+
+			// scans the properties which are due to poll only.
+			var syncInterval = params.GetSyncInterval()
+			var spec = d.instance.Spec
+			spec.Properties = nil
+			var deadbands = make(map[string]*pollapi.PollDeadband)
+			for _, prop := range d.instance.Spec.Properties {
+				if schedule.Due(prop.Name, prop.GetPollInterval(syncInterval), now) {
+					spec.Properties = append(spec.Properties, prop)
+					deadbands[prop.Name] = prop.Deadband
+				}
+			}
+			if len(spec.Properties) == 0 && !filter.ShouldSync(now) {
+				return
+			}
+
+			if len(spec.Properties) != 0 {
+				var props, err = d.scanDevice(spec)
+				if err != nil {
+					// TODO give a way to feedback this to limb.
+					d.log.Error(err, "failed to scan device")
+				}
+				for _, prop := range props {
+					filter.Observe(prop.Name, prop.Value, deadbands[prop.Name])
+					d.setStatusProperty(prop)
+				}
+			}
+			if !filter.ShouldSync(now) {
+				return
 			}
 			if err := d.sync(); err != nil {
 				d.log.Error(err, "failed to sync")
+				return
 			}
+			filter.Synced(now)
 		}()
 
 		select {
@@ -178,6 +279,17 @@ func() {
 	}
 }
 
+// setStatusProperty replaces the status property which has the same name.
+func (d *bleDevice) setStatusProperty(statusProp v1alpha1.BluetoothDeviceStatusProperty) {
+	for i := range d.instance.Status.Properties {
+		if d.instance.Status.Properties[i].Name == statusProp.Name {
+			d.instance.Status.Properties[i] = statusProp
+			return
+		}
+	}
+	d.instance.Status.Properties = append(d.instance.Status.Properties, statusProp)
+}
+
 func (d *bleDevice) scanDevice(spec v1alpha1.BluetoothDeviceSpec) ([]v1alpha1.BluetoothDeviceStatusProperty, error) {
 	if d.gattDevice == nil {
 		return nil, nil
@@ -227,16 +339,68 @@ func (d *bleDevice) startFetch(interval time.Duration) {
 
 // sync combines all synchronization operations.
 func (d *bleDevice) sync() error {
+	if d.mqttClient != nil {
+		var mqttStatus = d.mqttClient.Status()
+		d.instance.Status.Extension = &v1alpha1.BluetoothDeviceExtensionStatus{MQTT: &mqttStatus}
+	} else {
+		d.instance.Status.Extension = nil
+	}
 	if d.toLimb != nil {
 		if err := d.toLimb(d.instance); err != nil {
 			return err
 		}
 	}
 	if d.mqttClient != nil {
-		if err := d.mqttClient.Publish(mqtt.PublishMessage{Payload: d.instance.Status}); err != nil {
+		// the status of extension is reported to limb only.
+		var status = d.instance.Status
+		status.Extension = nil
+		if err := d.mqttClient.Publish(mqtt.PublishMessage{Payload: status}); err != nil {
 			return err
 		}
 	}
 	d.log.V(1).Info("Synced")
 	return nil
 }
+
+// receiveMQTTStatus reports the changed connection status of MQTT extension to limb.
+func (d *bleDevice) receiveMQTTStatus() {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.mqttClient == nil || d.toLimb == nil {
+		return
+	}
+	var mqttStatus = d.mqttClient.Status()
+	d.instance.Status.Extension = &v1alpha1.BluetoothDeviceExtensionStatus{MQTT: &mqttStatus}
+	if err := d.toLimb(d.instance); err != nil {
+		d.log.Error(err, "failed to report MQTT connection status")
+	}
+}
+
+// getFetchInterval returns the interval of ticking which can serve the polling intervals of all properties.
+func getFetchInterval(spec v1alpha1.BluetoothDeviceSpec) time.Duration {
+	var syncInterval = spec.Parameters.GetSyncInterval()
+	var intervals = []time.Duration{syncInterval}
+	for _, prop := range spec.Properties {
+		intervals = append(intervals, prop.GetPollInterval(syncInterval))
+	}
+	return poll.TickInterval(intervals...)
+}
+
+// setDefaultValue sets the default value of the writable property.
+func setDefaultValue(props []v1alpha1.BluetoothDeviceProperty, name, value string) error {
+	for i := range props {
+		if props[i].Name != name {
+			continue
+		}
+		if props[i].AccessMode != v1alpha1.BluetoothDevicePropertyReadWrite {
+			return errors.Errorf("property %s is not writable", name)
+		}
+		if _, exist := props[i].Visitor.DataWrite[value]; !exist {
+			return errors.Errorf("cannot find the data of value %s to write property %s", value, name)
+		}
+		props[i].Visitor.DefaultValue = value
+		return nil
+	}
+	return errors.Errorf("cannot find property %s", name)
+}
@@ -109,6 +109,10 @@ type DummyProtocolDeviceStatus struct {
 	// Reports the properties of device.
 	// +optional
 	Properties map[string]DummyProtocolDeviceStatusProperty `json:"properties,omitempty"`
+
+	// Reports the status of device extension.
+	// +optional
+	Extension *DummyDeviceExtensionStatus `json:"extension,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -49,6 +49,10 @@ type DummySpecialDeviceStatus struct {
 	// Reports the detail number of speed.
 	// +optional
 	RotatingSpeed int32 `json:"rotatingSpeed,omitempty"`
+
+	// Reports the status of device extension.
+	// +optional
+	Extension *DummyDeviceExtensionStatus `json:"extension,omitempty"`
 }
 
 // +kubebuilder:object:root=true
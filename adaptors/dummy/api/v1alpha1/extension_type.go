@@ -8,3 +8,10 @@ type DummyDeviceExtension struct {
 	// +optional
 	MQTT *mqttapi.MQTTOptions `json:"mqtt,omitempty"`
 }
+
+// DummyDeviceExtensionStatus defines the observed state of device extension.
+type DummyDeviceExtensionStatus struct {
+	// Reports the connection status of MQTT extension.
+	// +optional
+	MQTT *mqttapi.MQTTConnectionStatus `json:"mqtt,omitempty"`
+}
@@ -44,6 +44,26 @@ func (in *DummyDeviceExtension) DeepCopy() *DummyDeviceExtension {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DummyDeviceExtensionStatus) DeepCopyInto(out *DummyDeviceExtensionStatus) {
+	*out = *in
+	if in.MQTT != nil {
+		in, out := &in.MQTT, &out.MQTT
+		*out = new(api.MQTTConnectionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DummyDeviceExtensionStatus.
+func (in *DummyDeviceExtensionStatus) DeepCopy() *DummyDeviceExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DummyDeviceExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DummyProtocolDevice) DeepCopyInto(out *DummyProtocolDevice) {
 	*out = *in
@@ -199,6 +219,11 @@ func (in *DummyProtocolDeviceStatus) DeepCopyInto(out *DummyProtocolDeviceStatus
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Extension != nil {
+		in, out := &in.Extension, &out.Extension
+		*out = new(DummyDeviceExtensionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DummyProtocolDeviceStatus.
@@ -282,7 +307,7 @@ func (in *DummySpecialDevice) DeepCopyInto(out *DummySpecialDevice) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DummySpecialDevice.
@@ -374,6 +399,11 @@ func (in *DummySpecialDeviceSpec) DeepCopy() *DummySpecialDeviceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DummySpecialDeviceStatus) DeepCopyInto(out *DummySpecialDeviceStatus) {
 	*out = *in
+	if in.Extension != nil {
+		in, out := &in.Extension, &out.Extension
+		*out = new(DummyDeviceExtensionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DummySpecialDeviceStatus.
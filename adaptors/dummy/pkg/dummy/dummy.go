@@ -2,13 +2,17 @@
 
 import (
 	"golang.org/x/sync/errgroup"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/rancher/octopus/adaptors/dummy/api/v1alpha1"
 	"github.com/rancher/octopus/adaptors/dummy/pkg/adaptor"
 	"github.com/rancher/octopus/adaptors/dummy/pkg/metadata"
 	api "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
 	"github.com/rancher/octopus/pkg/adaptor/connection"
 	"github.com/rancher/octopus/pkg/adaptor/log"
+	"github.com/rancher/octopus/pkg/adaptor/metrics"
+	"github.com/rancher/octopus/pkg/adaptor/metrics/metricsflag"
 	"github.com/rancher/octopus/pkg/adaptor/registration"
 	"github.com/rancher/octopus/pkg/util/critical"
 )
@@ -29,12 +33,20 @@ func Run() error {
 		// start adaptor to receive requests from Limb
 		return connection.Serve(metadata.Endpoint, adaptor.NewService(), stop)
 	})
+	eg.Go(func() error {
+		// serve the metrics of adaptor
+		return metrics.Serve(metricsflag.GetAddress(), stop)
+	})
 	eg.Go(func() error {
 		// register adaptor to Limb
 		return registration.Register(ctx, api.RegisterRequest{
 			Name:     metadata.Name,
 			Version:  metadata.Version,
 			Endpoint: metadata.Endpoint,
+			Models: []*metav1.TypeMeta{
+				{APIVersion: v1alpha1.GroupVersion.String(), Kind: "DummySpecialDevice"},
+				{APIVersion: v1alpha1.GroupVersion.String(), Kind: "DummyProtocolDevice"},
+			},
 		})
 	})
 	return eg.Wait()
@@ -69,7 +69,10 @@ func (d *protocolDevice) Configure(references api.ReferencesHandler, configurati
 		}
 
 		if newExtension.MQTT != nil {
-			var cli, err = mqtt.NewClient(*newExtension.MQTT, object.GetControlledOwnerObjectReference(device), references)
+			var clientBuilder = mqtt.NewClientBuilder(*newExtension.MQTT, object.GetControlledOwnerObjectReference(device))
+			clientBuilder.Render(references)
+			clientBuilder.OnStatusChanged(d.receiveMQTTStatus)
+			var cli, err = clientBuilder.Build()
 			if err != nil {
 				return errors.Wrap(err, "failed to create MQTT client")
 			}
@@ -171,13 +174,22 @@ func (d *protocolDevice) startMock() {
 
 // sync combines all synchronization operations.
 func (d *protocolDevice) sync() error {
+	if d.mqttClient != nil {
+		var mqttStatus = d.mqttClient.Status()
+		d.instance.Status.Extension = &v1alpha1.DummyDeviceExtensionStatus{MQTT: &mqttStatus}
+	} else {
+		d.instance.Status.Extension = nil
+	}
 	if d.toLimb != nil {
 		if err := d.toLimb(d.instance); err != nil {
 			return err
 		}
 	}
 	if d.mqttClient != nil {
-		if err := d.mqttClient.Publish(mqtt.PublishMessage{Payload: d.instance.Status}); err != nil {
+		// the status of extension is reported to limb only.
+		var status = d.instance.Status
+		status.Extension = nil
+		if err := d.mqttClient.Publish(mqtt.PublishMessage{Payload: status}); err != nil {
 			return err
 		}
 	}
@@ -185,6 +197,21 @@ func (d *protocolDevice) sync() error {
 	return nil
 }
 
+// receiveMQTTStatus reports the changed connection status of MQTT extension to limb.
+func (d *protocolDevice) receiveMQTTStatus() {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.mqttClient == nil || d.toLimb == nil {
+		return
+	}
+	var mqttStatus = d.mqttClient.Status()
+	d.instance.Status.Extension = &v1alpha1.DummyDeviceExtensionStatus{MQTT: &mqttStatus}
+	if err := d.toLimb(d.instance); err != nil {
+		d.log.Error(err, "failed to report MQTT connection status")
+	}
+}
+
 func fillStatusArray(source v1alpha1.DummyProtocolDeviceProperty, length int) []v1alpha1.DummyProtocolDeviceStatusProperty {
 	var target []v1alpha1.DummyProtocolDeviceStatusProperty
 	var sourceProp = source
@@ -1,7 +1,9 @@
 package physical
 
 import (
+	"fmt"
 	"reflect"
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,6 +15,7 @@
 	"github.com/rancher/octopus/adaptors/dummy/api/v1alpha1"
 	"github.com/rancher/octopus/adaptors/dummy/pkg/metadata"
 	api "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/adaptor/command"
 	"github.com/rancher/octopus/pkg/adaptor/socket/handler"
 	"github.com/rancher/octopus/pkg/mqtt"
 	"github.com/rancher/octopus/pkg/util/object"
@@ -68,7 +71,10 @@ func (d *specialDevice) Configure(references api.ReferencesHandler, configuratio
 		}
 
 		if newExtension.MQTT != nil {
-			var cli, err = mqtt.NewClient(*newExtension.MQTT, object.GetControlledOwnerObjectReference(device), references)
+			var clientBuilder = mqtt.NewClientBuilder(*newExtension.MQTT, object.GetControlledOwnerObjectReference(device))
+			clientBuilder.Render(references)
+			clientBuilder.OnStatusChanged(d.receiveMQTTStatus)
+			var cli, err = clientBuilder.Build()
 			if err != nil {
 				return errors.Wrap(err, "failed to create MQTT client")
 			}
@@ -77,6 +83,11 @@ func (d *specialDevice) Configure(references api.ReferencesHandler, configuratio
 			if err != nil {
 				return errors.Wrap(err, "failed to connect MQTT broker")
 			}
+			// receives the writing commands, e.g. the DCMD of Sparkplug B mode.
+			err = cli.Subscribe(nil, d.receiveCommand)
+			if err != nil {
+				return errors.Wrap(err, "failed to subscribe MQTT commands")
+			}
 			d.mqttClient = cli
 		}
 	}
@@ -84,6 +95,43 @@ func (d *specialDevice) Configure(references api.ReferencesHandler, configuratio
 	return d.refresh(newSpec)
 }
 
+// receiveCommand turns on/off the device or changes the gear of device, and replies the results.
+func (d *specialDevice) receiveCommand(msg mqtt.SubscribeMessage) {
+	command.Receive(d.log, metadata.Endpoint, msg, func(values map[string]interface{}) mqtt.CommandReply {
+		d.Lock()
+		defer d.Unlock()
+
+		var newSpec = d.instance.Spec
+		var reply mqtt.CommandReply
+		for name, value := range values {
+			var result = mqtt.CommandResult{Name: name, Value: value}
+			switch name {
+			case "on":
+				var on, err = strconv.ParseBool(command.FormatValue(value))
+				if err != nil {
+					result.Value, result.Error = nil, fmt.Sprintf("invalid value %v", value)
+					break
+				}
+				newSpec.On = on
+			case "gear":
+				switch gear := v1alpha1.DummySpecialDeviceGear(command.FormatValue(value)); gear {
+				case v1alpha1.DummySpecialDeviceGearSlow, v1alpha1.DummySpecialDeviceGearMiddle, v1alpha1.DummySpecialDeviceGearFast:
+					newSpec.Gear = gear
+				default:
+					result.Value, result.Error = nil, fmt.Sprintf("invalid value %v", value)
+				}
+			default:
+				result.Value, result.Error = nil, fmt.Sprintf("cannot find property %s", name)
+			}
+			reply.Results = append(reply.Results, result)
+		}
+		if err := d.refresh(newSpec); err != nil {
+			d.log.Error(err, "failed to refresh")
+		}
+		return reply
+	})
+}
+
 func (d *specialDevice) Shutdown() {
 	d.Lock()
 	defer d.Unlock()
@@ -202,16 +250,40 @@ func (d *specialDevice) startMock(gear v1alpha1.DummySpecialDeviceGear) {
 
 // sync combines all synchronization operations.
 func (d *specialDevice) sync() error {
+	if d.mqttClient != nil {
+		var mqttStatus = d.mqttClient.Status()
+		d.instance.Status.Extension = &v1alpha1.DummyDeviceExtensionStatus{MQTT: &mqttStatus}
+	} else {
+		d.instance.Status.Extension = nil
+	}
 	if d.toLimb != nil {
 		if err := d.toLimb(d.instance); err != nil {
 			return err
 		}
 	}
 	if d.mqttClient != nil {
-		if err := d.mqttClient.Publish(mqtt.PublishMessage{Payload: d.instance.Status}); err != nil {
+		// the status of extension is reported to limb only.
+		var status = d.instance.Status
+		status.Extension = nil
+		if err := d.mqttClient.Publish(mqtt.PublishMessage{Payload: status}); err != nil {
 			return err
 		}
 	}
 	d.log.V(1).Info("Synced")
 	return nil
 }
+
+// receiveMQTTStatus reports the changed connection status of MQTT extension to limb.
+func (d *specialDevice) receiveMQTTStatus() {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.mqttClient == nil || d.toLimb == nil {
+		return
+	}
+	var mqttStatus = d.mqttClient.Status()
+	d.instance.Status.Extension = &v1alpha1.DummyDeviceExtensionStatus{MQTT: &mqttStatus}
+	if err := d.toLimb(d.instance); err != nil {
+		d.log.Error(err, "failed to report MQTT connection status")
+	}
+}
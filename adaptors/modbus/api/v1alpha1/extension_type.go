@@ -10,3 +10,10 @@ type ModbusDeviceExtension struct {
 	// +optional
 	MQTT *mqttapi.MQTTOptions `json:"mqtt,omitempty"`
 }
+
+// ModbusDeviceExtensionStatus defines the observed state of device extension.
+type ModbusDeviceExtensionStatus struct {
+	// Reports the connection status of MQTT extension.
+	// +optional
+	MQTT *mqttapi.MQTTConnectionStatus `json:"mqtt,omitempty"`
+}
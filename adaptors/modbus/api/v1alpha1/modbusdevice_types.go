@@ -5,6 +5,8 @@
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pollapi "github.com/rancher/octopus/pkg/adaptor/poll/api"
 )
 
 // ModbusDeviceRegisterType defines the type for the register to read a device property.
@@ -213,6 +215,32 @@ type ModbusDeviceParameters struct {
 	// The default value is "10s".
 	// +kubebuilder:default="10s"
 	Timeout v1.Duration `json:"timeout,omitempty"`
+
+	// Specifies to read the properties in blocks when synchronizing,
+	// the properties are read one by one if it is not specified.
+	// +optional
+	BlockRead *ModbusDeviceBlockRead `json:"blockRead,omitempty"`
+
+	// Specifies how to synchronize the polled values to limb,
+	// the values are synchronized after each polling if it is not specified.
+	// +optional
+	Sync *pollapi.PollSyncOptions `json:"sync,omitempty"`
+}
+
+// ModbusDeviceBlockRead defines how to coalesce the properties of the same register type into block reads.
+type ModbusDeviceBlockRead struct {
+	// Specifies the max number of unused registers(or coils) between two properties in the same block,
+	// the unused registers are read but dropped.
+	// The default value is "0", which means only the contiguous properties are coalesced.
+	// +optional
+	MaxGap uint16 `json:"maxGap,omitempty"`
+
+	// Specifies the max quantity of registers(or coils) in one block,
+	// it cannot exceed the protocol limits, which are 125 for registers and 2000 for coils.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2000
+	// +optional
+	MaxQuantity *uint16 `json:"maxQuantity,omitempty"`
 }
 
 func (in *ModbusDeviceParameters) GetSyncInterval() time.Duration {
@@ -299,6 +327,13 @@ type ModbusDeviceProtocolRTU struct {
 	// +kubebuilder:validation:Enum=1;2
 	// +kubebuilder:default=1
 	StopBits int `json:"stopBits,omitempty"`
+
+	// Specifies the delay between two transactions on the serial port,
+	// it is useful for the slow slaves which need more silent interval than the protocol requires.
+	// The devices on the same serial port share one bus,
+	// so they must have the same serial settings, inter-frame delay and timeout.
+	// +optional
+	InterFrameDelay *v1.Duration `json:"interFrameDelay,omitempty"`
 }
 
 // ModbusDeviceArithmeticOperationType defines the type of arithmetic operation.
@@ -377,6 +412,20 @@ type ModbusDeviceProperty struct {
 	// Specifies the value of property, only available in the writable property.
 	// +optional
 	Value string `json:"value,omitempty"`
+
+	// Specifies to read the property back after writing and compare with the written value,
+	// the writing is failed if they are different.
+	// The default value is "false".
+	// +optional
+	VerifyWrite bool `json:"verifyWrite,omitempty"`
+
+	// Specifies the group of writing, the writable properties of the same group are written in order as a transaction,
+	// if any of them fails, the written properties of the group are rolled back to the previous values.
+	// +optional
+	WriteGroup string `json:"writeGroup,omitempty"`
+
+	// Specifies the polling of property.
+	pollapi.PollPropertyOptions `json:",inline"`
 }
 
 // ModbusDeviceSpec defines the desired state of ModbusDevice.
@@ -405,6 +454,42 @@ type ModbusDeviceStatus struct {
 	// Reports the properties of device.
 	// +optional
 	Properties []ModbusDeviceStatusProperty `json:"properties,omitempty"`
+
+	// Reports the status of device extension.
+	// +optional
+	Extension *ModbusDeviceExtensionStatus `json:"extension,omitempty"`
+
+	// Reports the status of the shared serial bus, only available in RTU protocol.
+	// +optional
+	Bus *ModbusDeviceBusStatus `json:"bus,omitempty"`
+}
+
+// ModbusDeviceBusStatus defines the observed state of the serial bus shared by the RTU devices.
+type ModbusDeviceBusStatus struct {
+	// Reports the serial port of the bus.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Reports the number of devices on the bus.
+	// +optional
+	Devices int32 `json:"devices,omitempty"`
+
+	// Reports the last error of the bus, e.g. the I/O error of serial port,
+	// which affects all devices on the bus.
+	// +optional
+	LastBusError string `json:"lastBusError,omitempty"`
+
+	// Reports the timestamp of the last bus error.
+	// +optional
+	LastBusErrorTime *metav1.Time `json:"lastBusErrorTime,omitempty"`
+
+	// Reports the last error of the device as a slave, e.g. no response, exception response or invalid response.
+	// +optional
+	LastSlaveError string `json:"lastSlaveError,omitempty"`
+
+	// Reports the timestamp of the last slave error.
+	// +optional
+	LastSlaveErrorTime *metav1.Time `json:"lastSlaveErrorTime,omitempty"`
 }
 
 // ModbusDeviceStatusProperty defines the observed property of ModbusDevice.
@@ -428,6 +513,44 @@ type ModbusDeviceStatusProperty struct {
 	// Reports the updated timestamp of property.
 	// +optional
 	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+
+	// Reports the outcome of the last writing.
+	// +optional
+	LastWrite *ModbusDeviceWriteStatus `json:"lastWrite,omitempty"`
+}
+
+// ModbusDeviceWriteState defines the state of writing a property.
+// Written: The value is written without verification.
+// Verified: The value is written and verified by reading back.
+// Failed: The value cannot be written, or it is different from the reading back.
+// RolledBack: The value is written but rolled back, as another property of the same write group failed.
+// +kubebuilder:validation:Enum=Written;Verified;Failed;RolledBack
+type ModbusDeviceWriteState string
+
+const (
+	ModbusDeviceWriteStateWritten    ModbusDeviceWriteState = "Written"
+	ModbusDeviceWriteStateVerified   ModbusDeviceWriteState = "Verified"
+	ModbusDeviceWriteStateFailed     ModbusDeviceWriteState = "Failed"
+	ModbusDeviceWriteStateRolledBack ModbusDeviceWriteState = "RolledBack"
+)
+
+// ModbusDeviceWriteStatus defines the observed outcome of writing a property.
+type ModbusDeviceWriteStatus struct {
+	// Reports the state of writing.
+	// +optional
+	State ModbusDeviceWriteState `json:"state,omitempty"`
+
+	// Reports the value to write.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Reports the error of writing.
+	// +optional
+	Error string `json:"error,omitempty"`
+
+	// Reports the timestamp of writing.
+	// +optional
+	Time *metav1.Time `json:"time,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -20,7 +20,9 @@
 package v1alpha1
 
 import (
+	pollapi "github.com/rancher/octopus/pkg/adaptor/poll/api"
 	"github.com/rancher/octopus/pkg/mqtt/api"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -66,6 +68,49 @@ func (in *ModbusDeviceArithmeticOperation) DeepCopy() *ModbusDeviceArithmeticOpe
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModbusDeviceBlockRead) DeepCopyInto(out *ModbusDeviceBlockRead) {
+	*out = *in
+	if in.MaxQuantity != nil {
+		in, out := &in.MaxQuantity, &out.MaxQuantity
+		*out = new(uint16)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModbusDeviceBlockRead.
+func (in *ModbusDeviceBlockRead) DeepCopy() *ModbusDeviceBlockRead {
+	if in == nil {
+		return nil
+	}
+	out := new(ModbusDeviceBlockRead)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModbusDeviceBusStatus) DeepCopyInto(out *ModbusDeviceBusStatus) {
+	*out = *in
+	if in.LastBusErrorTime != nil {
+		in, out := &in.LastBusErrorTime, &out.LastBusErrorTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSlaveErrorTime != nil {
+		in, out := &in.LastSlaveErrorTime, &out.LastSlaveErrorTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModbusDeviceBusStatus.
+func (in *ModbusDeviceBusStatus) DeepCopy() *ModbusDeviceBusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModbusDeviceBusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModbusDeviceExtension) DeepCopyInto(out *ModbusDeviceExtension) {
 	*out = *in
@@ -86,6 +131,26 @@ func (in *ModbusDeviceExtension) DeepCopy() *ModbusDeviceExtension {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModbusDeviceExtensionStatus) DeepCopyInto(out *ModbusDeviceExtensionStatus) {
+	*out = *in
+	if in.MQTT != nil {
+		in, out := &in.MQTT, &out.MQTT
+		*out = new(api.MQTTConnectionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModbusDeviceExtensionStatus.
+func (in *ModbusDeviceExtensionStatus) DeepCopy() *ModbusDeviceExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModbusDeviceExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModbusDeviceList) DeepCopyInto(out *ModbusDeviceList) {
 	*out = *in
@@ -123,6 +188,16 @@ func (in *ModbusDeviceParameters) DeepCopyInto(out *ModbusDeviceParameters) {
 	*out = *in
 	out.SyncInterval = in.SyncInterval
 	out.Timeout = in.Timeout
+	if in.BlockRead != nil {
+		in, out := &in.BlockRead, &out.BlockRead
+		*out = new(ModbusDeviceBlockRead)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sync != nil {
+		in, out := &in.Sync, &out.Sync
+		*out = new(pollapi.PollSyncOptions)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModbusDeviceParameters.
@@ -139,6 +214,7 @@ func (in *ModbusDeviceParameters) DeepCopy() *ModbusDeviceParameters {
 func (in *ModbusDeviceProperty) DeepCopyInto(out *ModbusDeviceProperty) {
 	*out = *in
 	in.Visitor.DeepCopyInto(&out.Visitor)
+	in.PollPropertyOptions.DeepCopyInto(&out.PollPropertyOptions)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModbusDeviceProperty.
@@ -177,7 +253,7 @@ func (in *ModbusDeviceProtocol) DeepCopyInto(out *ModbusDeviceProtocol) {
 	if in.RTU != nil {
 		in, out := &in.RTU, &out.RTU
 		*out = new(ModbusDeviceProtocolRTU)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.TCP != nil {
 		in, out := &in.TCP, &out.TCP
@@ -199,6 +275,11 @@ func (in *ModbusDeviceProtocol) DeepCopy() *ModbusDeviceProtocol {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModbusDeviceProtocolRTU) DeepCopyInto(out *ModbusDeviceProtocolRTU) {
 	*out = *in
+	if in.InterFrameDelay != nil {
+		in, out := &in.InterFrameDelay, &out.InterFrameDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModbusDeviceProtocolRTU.
@@ -237,7 +318,7 @@ func (in *ModbusDeviceSpec) DeepCopyInto(out *ModbusDeviceSpec) {
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
 		*out = new(ModbusDeviceParameters)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	in.Protocol.DeepCopyInto(&out.Protocol)
 	if in.Properties != nil {
@@ -269,6 +350,16 @@ func (in *ModbusDeviceStatus) DeepCopyInto(out *ModbusDeviceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Extension != nil {
+		in, out := &in.Extension, &out.Extension
+		*out = new(ModbusDeviceExtensionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bus != nil {
+		in, out := &in.Bus, &out.Bus
+		*out = new(ModbusDeviceBusStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModbusDeviceStatus.
@@ -288,6 +379,11 @@ func (in *ModbusDeviceStatusProperty) DeepCopyInto(out *ModbusDeviceStatusProper
 		in, out := &in.UpdatedAt, &out.UpdatedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.LastWrite != nil {
+		in, out := &in.LastWrite, &out.LastWrite
+		*out = new(ModbusDeviceWriteStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModbusDeviceStatusProperty.
@@ -299,3 +395,22 @@ func (in *ModbusDeviceStatusProperty) DeepCopy() *ModbusDeviceStatusProperty {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModbusDeviceWriteStatus) DeepCopyInto(out *ModbusDeviceWriteStatus) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModbusDeviceWriteStatus.
+func (in *ModbusDeviceWriteStatus) DeepCopy() *ModbusDeviceWriteStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModbusDeviceWriteStatus)
+	in.DeepCopyInto(out)
+	return out
+}
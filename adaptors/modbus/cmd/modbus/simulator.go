@@ -0,0 +1,123 @@
+package main
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/rancher/octopus/adaptors/modbus/pkg/simulator"
+	"github.com/rancher/octopus/pkg/adaptor/log"
+	"github.com/rancher/octopus/pkg/util/critical"
+	"github.com/rancher/octopus/pkg/util/log/logflag"
+)
+
+const (
+	simulatorName        = "simulator"
+	simulatorDescription = `Simulate the Modbus slaves from a declarative register map,
+which can be served via Modbus TCP or Modbus RTU over a pseudo terminal.`
+)
+
+type simulatorOptions struct {
+	config     string
+	tcpAddress string
+	rtu        bool
+	rtuLink    string
+}
+
+func newSimulatorCommand() *cobra.Command {
+	var opts simulatorOptions
+	var c = &cobra.Command{
+		Use:  simulatorName,
+		Long: simulatorDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logflag.SetLogger(log.SetLogger)
+
+			return runSimulator(opts)
+		},
+	}
+
+	c.Flags().StringVar(&opts.config, "config", opts.config, "The path of register map file in YAML.")
+	c.Flags().StringVar(&opts.tcpAddress, "tcp-address", opts.tcpAddress, "The address to serve Modbus TCP, e.g. \":5020\".")
+	c.Flags().BoolVar(&opts.rtu, "rtu", opts.rtu, "Serve Modbus RTU over a pseudo terminal.")
+	c.Flags().StringVar(&opts.rtuLink, "rtu-link", opts.rtuLink, "The path of symbolic link to the pseudo terminal, e.g. \"/tmp/ttyMODBUS\".")
+	logflag.AddFlags(c.Flags())
+	return c
+}
+
+func runSimulator(opts simulatorOptions) error {
+	if opts.tcpAddress == "" && !opts.rtu {
+		return errors.New("either --tcp-address or --rtu is required")
+	}
+
+	var data, err = ioutil.ReadFile(opts.config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read register map file %s", opts.config)
+	}
+	var config simulator.Config
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal register map file %s", opts.config)
+	}
+	var logger = log.WithName(simulatorName)
+	sim, err := simulator.NewSimulator(logger, config)
+	if err != nil {
+		return errors.Wrap(err, "failed to create simulator")
+	}
+
+	var stop = ctrl.SetupSignalHandler()
+	var ctx = critical.Context(stop)
+	eg, ctx := errgroup.WithContext(ctx)
+	stop = ctx.Done()
+	eg.Go(func() error {
+		// update the scripted values
+		sim.Run(stop)
+		return nil
+	})
+	if opts.tcpAddress != "" {
+		var listener, err = net.Listen("tcp", opts.tcpAddress)
+		if err != nil {
+			return errors.Wrapf(err, "failed to listen on %s", opts.tcpAddress)
+		}
+		eg.Go(func() error {
+			// serve Modbus TCP
+			return sim.ServeTCP(listener, stop)
+		})
+	}
+	if opts.rtu {
+		var pty, err = simulator.OpenPTY()
+		if err != nil {
+			return err
+		}
+		if opts.rtuLink != "" {
+			_ = os.Remove(opts.rtuLink)
+			if err := os.Symlink(pty.Name(), opts.rtuLink); err != nil {
+				_ = pty.Close()
+				return errors.Wrapf(err, "failed to link %s to pseudo terminal", opts.rtuLink)
+			}
+		}
+		logger.Info("Serving Modbus RTU", "port", pty.Name(), "link", opts.rtuLink)
+		eg.Go(func() error {
+			// serve Modbus RTU until the pseudo terminal is closed
+			var err = sim.ServeRTU(pty)
+			select {
+			case <-stop:
+				return nil
+			default:
+			}
+			return err
+		})
+		eg.Go(func() error {
+			<-stop
+			if opts.rtuLink != "" {
+				_ = os.Remove(opts.rtuLink)
+			}
+			return pty.Close()
+		})
+	}
+	return eg.Wait()
+}
@@ -0,0 +1,119 @@
+package physical
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/rancher/octopus/adaptors/modbus/api/v1alpha1"
+)
+
+const (
+	// maxBlockRegisters is the max quantity of 16-bits registers in one read request.
+	maxBlockRegisters = 125
+	// maxBlockCoils is the max quantity of 1-bit registers in one read request.
+	maxBlockCoils = 2000
+)
+
+// readBlock is a contiguous range of the same register type, which is read in one request.
+type readBlock struct {
+	register v1alpha1.ModbusDeviceRegisterType
+	offset   uint16
+	quantity uint16
+	// props are the indexes of the properties covered by the block.
+	props []int
+}
+
+// end returns the offset next to the last register of the block.
+func (b *readBlock) end() int {
+	return int(b.offset) + int(b.quantity)
+}
+
+// slice returns the data of the given property from the data of block,
+// the result is in the same form as reading the property separately.
+func (b *readBlock) slice(data []byte, visitor v1alpha1.ModbusDevicePropertyVisitor) ([]byte, error) {
+	var start = int(visitor.Offset - b.offset)
+	var quantity = int(visitor.Quantity)
+
+	if is1BitRegister(b.register) {
+		if (start+quantity+7)/8 > len(data) {
+			return nil, errors.Errorf("response bytes of %s block isn't in valid size", b.register)
+		}
+		// the coils are packed from the low order bit of the first byte,
+		// shifts them to the start of the result.
+		var ret = make([]byte, (quantity+7)/8)
+		for i := 0; i < quantity; i++ {
+			var bit = start + i
+			if data[bit/8]&(1<<uint(bit%8)) != 0 {
+				ret[i/8] |= 1 << uint(i%8)
+			}
+		}
+		return ret, nil
+	}
+
+	if (start+quantity)*2 > len(data) {
+		return nil, errors.Errorf("response bytes of %s block isn't in valid size", b.register)
+	}
+	return data[start*2 : (start+quantity)*2], nil
+}
+
+// planReadBlocks groups the properties by register type,
+// and coalesces the properties whose gap is not larger than the max gap into one block.
+// The property which quantity exceeds the max quantity of block is read in a block alone.
+func planReadBlocks(props []v1alpha1.ModbusDeviceProperty, options *v1alpha1.ModbusDeviceBlockRead) []readBlock {
+	var indexes = make([]int, len(props))
+	for i := range props {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		var l, r = props[indexes[i]].Visitor, props[indexes[j]].Visitor
+		if l.Register != r.Register {
+			return l.Register < r.Register
+		}
+		return l.Offset < r.Offset
+	})
+
+	var blocks []readBlock
+	var current *readBlock
+	for _, idx := range indexes {
+		var visitor = props[idx].Visitor
+		var maxQuantity = getMaxBlockQuantity(visitor.Register, options)
+
+		if current != nil && current.register == visitor.Register {
+			var start = int(visitor.Offset)
+			var end = start + int(visitor.Quantity)
+			if start <= current.end()+int(options.MaxGap) && end-int(current.offset) <= maxQuantity {
+				if end > current.end() {
+					current.quantity = uint16(end - int(current.offset))
+				}
+				current.props = append(current.props, idx)
+				continue
+			}
+		}
+
+		blocks = append(blocks, readBlock{
+			register: visitor.Register,
+			offset:   visitor.Offset,
+			quantity: visitor.Quantity,
+			props:    []int{idx},
+		})
+		current = &blocks[len(blocks)-1]
+	}
+	return blocks
+}
+
+// getMaxBlockQuantity returns the max quantity of the block for the given register type.
+func getMaxBlockQuantity(register v1alpha1.ModbusDeviceRegisterType, options *v1alpha1.ModbusDeviceBlockRead) int {
+	var ret = maxBlockRegisters
+	if is1BitRegister(register) {
+		ret = maxBlockCoils
+	}
+	if options.MaxQuantity != nil && int(*options.MaxQuantity) < ret {
+		ret = int(*options.MaxQuantity)
+	}
+	return ret
+}
+
+func is1BitRegister(register v1alpha1.ModbusDeviceRegisterType) bool {
+	return register == v1alpha1.ModbusDeviceCoilRegister || register == v1alpha1.ModbusDeviceDiscreteInputRegister
+}
@@ -0,0 +1,127 @@
+package physical
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rancher/octopus/adaptors/modbus/api/v1alpha1"
+)
+
+func newTestProperty(register v1alpha1.ModbusDeviceRegisterType, offset, quantity uint16) v1alpha1.ModbusDeviceProperty {
+	return v1alpha1.ModbusDeviceProperty{
+		Visitor: v1alpha1.ModbusDevicePropertyVisitor{
+			Register: register,
+			Offset:   offset,
+			Quantity: quantity,
+		},
+	}
+}
+
+func Test_planReadBlocks(t *testing.T) {
+	var maxQuantity uint16 = 8
+
+	var testCases = []struct {
+		name     string
+		given    []v1alpha1.ModbusDeviceProperty
+		options  v1alpha1.ModbusDeviceBlockRead
+		expected []readBlock
+	}{
+		{
+			name: "contiguous",
+			given: []v1alpha1.ModbusDeviceProperty{
+				newTestProperty(v1alpha1.ModbusDeviceHoldingRegister, 2, 2),
+				newTestProperty(v1alpha1.ModbusDeviceHoldingRegister, 0, 2),
+				newTestProperty(v1alpha1.ModbusDeviceHoldingRegister, 5, 1),
+			},
+			expected: []readBlock{
+				{register: v1alpha1.ModbusDeviceHoldingRegister, offset: 0, quantity: 4, props: []int{1, 0}},
+				{register: v1alpha1.ModbusDeviceHoldingRegister, offset: 5, quantity: 1, props: []int{2}},
+			},
+		},
+		{
+			name: "nearly contiguous",
+			given: []v1alpha1.ModbusDeviceProperty{
+				newTestProperty(v1alpha1.ModbusDeviceHoldingRegister, 0, 2),
+				newTestProperty(v1alpha1.ModbusDeviceHoldingRegister, 3, 2),
+				newTestProperty(v1alpha1.ModbusDeviceHoldingRegister, 3, 1),
+			},
+			options: v1alpha1.ModbusDeviceBlockRead{MaxGap: 1},
+			expected: []readBlock{
+				{register: v1alpha1.ModbusDeviceHoldingRegister, offset: 0, quantity: 5, props: []int{0, 1, 2}},
+			},
+		},
+		{
+			name: "group by register type",
+			given: []v1alpha1.ModbusDeviceProperty{
+				newTestProperty(v1alpha1.ModbusDeviceInputRegister, 0, 1),
+				newTestProperty(v1alpha1.ModbusDeviceCoilRegister, 1, 1),
+				newTestProperty(v1alpha1.ModbusDeviceHoldingRegister, 1, 1),
+				newTestProperty(v1alpha1.ModbusDeviceCoilRegister, 0, 1),
+			},
+			expected: []readBlock{
+				{register: v1alpha1.ModbusDeviceCoilRegister, offset: 0, quantity: 2, props: []int{3, 1}},
+				{register: v1alpha1.ModbusDeviceHoldingRegister, offset: 1, quantity: 1, props: []int{2}},
+				{register: v1alpha1.ModbusDeviceInputRegister, offset: 0, quantity: 1, props: []int{0}},
+			},
+		},
+		{
+			name: "exceed max quantity",
+			given: []v1alpha1.ModbusDeviceProperty{
+				newTestProperty(v1alpha1.ModbusDeviceHoldingRegister, 0, 4),
+				newTestProperty(v1alpha1.ModbusDeviceHoldingRegister, 4, 4),
+				newTestProperty(v1alpha1.ModbusDeviceHoldingRegister, 8, 4),
+				newTestProperty(v1alpha1.ModbusDeviceHoldingRegister, 12, 10),
+			},
+			options: v1alpha1.ModbusDeviceBlockRead{MaxQuantity: &maxQuantity},
+			expected: []readBlock{
+				{register: v1alpha1.ModbusDeviceHoldingRegister, offset: 0, quantity: 8, props: []int{0, 1}},
+				{register: v1alpha1.ModbusDeviceHoldingRegister, offset: 8, quantity: 4, props: []int{2}},
+				{register: v1alpha1.ModbusDeviceHoldingRegister, offset: 12, quantity: 10, props: []int{3}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = planReadBlocks(tc.given, &tc.options)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
+func Test_readBlock_slice(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		block    readBlock
+		given    []byte
+		visitor  v1alpha1.ModbusDevicePropertyVisitor
+		expected []byte
+	}{
+		{
+			name:     "16-bits registers",
+			block:    readBlock{register: v1alpha1.ModbusDeviceHoldingRegister, offset: 10, quantity: 3},
+			given:    []byte{0x00, 0x01, 0x00, 0x02, 0x00, 0x03},
+			visitor:  v1alpha1.ModbusDevicePropertyVisitor{Offset: 11, Quantity: 2},
+			expected: []byte{0x00, 0x02, 0x00, 0x03},
+		},
+		{
+			name:     "single coil",
+			block:    readBlock{register: v1alpha1.ModbusDeviceCoilRegister, offset: 0, quantity: 10},
+			given:    []byte{0xCD, 0x01},
+			visitor:  v1alpha1.ModbusDevicePropertyVisitor{Offset: 8, Quantity: 1},
+			expected: []byte{0x01},
+		},
+		{
+			name:     "multiple coils",
+			block:    readBlock{register: v1alpha1.ModbusDeviceCoilRegister, offset: 20, quantity: 16},
+			given:    []byte{0xCD, 0x01},
+			visitor:  v1alpha1.ModbusDevicePropertyVisitor{Offset: 22, Quantity: 10},
+			expected: []byte{0x73, 0x00},
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual, err = tc.block.slice(tc.given, tc.visitor)
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
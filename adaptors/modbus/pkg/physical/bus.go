@@ -0,0 +1,347 @@
+package physical
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/goburrow/modbus"
+	"github.com/goburrow/serial"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/octopus/adaptors/modbus/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/util/log/logflag"
+)
+
+var errBusClosed = errors.New("bus is closed")
+
+// rtuBuses manages the buses of all serial ports.
+var rtuBuses = &busManager{buses: make(map[string]*rtuBus)}
+
+// busError is the error of the bus, which affects all slaves on the bus.
+type busError struct {
+	error
+}
+
+// busTransporter is the transporter of the serial port.
+type busTransporter interface {
+	modbus.Transporter
+	Close() error
+}
+
+// busStatusReporter reports the status of the bus which the device is on.
+type busStatusReporter interface {
+	BusStatus() *v1alpha1.ModbusDeviceBusStatus
+}
+
+// busManager shares one bus among the RTU devices of the same serial port.
+type busManager struct {
+	sync.Mutex
+
+	buses map[string]*rtuBus
+}
+
+// attach returns the handler of the given device on the bus, the bus is created if it's the first device.
+func (m *busManager) attach(config v1alpha1.ModbusDeviceProtocolRTU, timeout time.Duration) (ModbusClientHandler, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	var settings = busSettings{config: config, timeout: timeout}
+
+	var bus, exist = m.buses[config.Endpoint]
+	if exist {
+		if diffs := bus.settings.diff(settings); len(diffs) != 0 {
+			return nil, errors.Errorf("serial port %s is shared by the devices with different settings: %s",
+				config.Endpoint, strings.Join(diffs, ", "))
+		}
+	} else {
+		var err error
+		bus, err = newRTUBus(settings)
+		if err != nil {
+			return nil, err
+		}
+		m.buses[config.Endpoint] = bus
+	}
+	bus.refs++
+
+	// the handler is used as packager only, which encodes/decodes the frame with the slave ID.
+	var packager = modbus.NewRTUClientHandler(config.Endpoint)
+	packager.SlaveId = byte(config.WorkerID)
+	return &rtuBusClientHandler{bus: bus, packager: packager}, nil
+}
+
+// detach releases the bus of the given handler, the bus is closed if it's the last device.
+func (m *busManager) detach(bus *rtuBus) {
+	m.Lock()
+	defer m.Unlock()
+
+	bus.refs--
+	if bus.refs > 0 {
+		return
+	}
+	// closes the serial port synchronously, so that the port can be reopened as soon as the bus is dropped.
+	close(bus.stop)
+	<-bus.done
+	if err := bus.transporter.Close(); err != nil {
+		log.Printf("failed to close serial port %s: %v\n", bus.settings.config.Endpoint, err)
+	}
+	delete(m.buses, bus.settings.config.Endpoint)
+}
+
+// busSettings is the settings which must be the same among the devices on the bus.
+type busSettings struct {
+	config  v1alpha1.ModbusDeviceProtocolRTU
+	timeout time.Duration
+}
+
+// diff returns the descriptions of the settings which are different from the given one.
+func (s busSettings) diff(other busSettings) []string {
+	var ret []string
+	if s.config.BaudRate != other.config.BaudRate {
+		ret = append(ret, fmt.Sprintf("baud rate %d != %d", s.config.BaudRate, other.config.BaudRate))
+	}
+	if s.config.DataBits != other.config.DataBits {
+		ret = append(ret, fmt.Sprintf("data bits %d != %d", s.config.DataBits, other.config.DataBits))
+	}
+	if s.config.Parity != other.config.Parity {
+		ret = append(ret, fmt.Sprintf("parity %q != %q", s.config.Parity, other.config.Parity))
+	}
+	if s.config.StopBits != other.config.StopBits {
+		ret = append(ret, fmt.Sprintf("stop bits %d != %d", s.config.StopBits, other.config.StopBits))
+	}
+	if s.interFrameDelay() != other.interFrameDelay() {
+		ret = append(ret, fmt.Sprintf("inter-frame delay %s != %s", s.interFrameDelay(), other.interFrameDelay()))
+	}
+	if s.timeout != other.timeout {
+		ret = append(ret, fmt.Sprintf("timeout %s != %s", s.timeout, other.timeout))
+	}
+	return ret
+}
+
+// interFrameDelay returns the delay between two transactions, it's 0 if not specified.
+func (s busSettings) interFrameDelay() time.Duration {
+	if s.config.InterFrameDelay == nil {
+		return 0
+	}
+	return s.config.InterFrameDelay.Duration
+}
+
+// busRequest is a transaction on the bus.
+type busRequest struct {
+	aduRequest []byte
+	response   chan busResponse
+}
+
+type busResponse struct {
+	aduResponse []byte
+	err         error
+}
+
+// rtuBus owns the serial port, and serializes the transactions from all slaves on the port.
+type rtuBus struct {
+	sync.Mutex
+
+	settings    busSettings
+	refs        int
+	transporter busTransporter
+	requests    chan busRequest
+	stop        chan struct{}
+	done        chan struct{}
+	lastError   string
+	lastTime    *metav1.Time
+}
+
+// send sends the request and waits for the response,
+// the requests are processed in arrival order, so that no slave starves.
+func (b *rtuBus) send(aduRequest []byte) ([]byte, error) {
+	var req = busRequest{aduRequest: aduRequest, response: make(chan busResponse, 1)}
+	select {
+	case <-b.stop:
+		return nil, busError{errBusClosed}
+	case b.requests <- req:
+	}
+
+	var resp = <-req.response
+	return resp.aduResponse, resp.err
+}
+
+// run processes the transactions one by one until stopped.
+func (b *rtuBus) run() {
+	defer close(b.done)
+
+	var delay = b.settings.interFrameDelay()
+
+	for {
+		var req busRequest
+		select {
+		case <-b.stop:
+			return
+		case req = <-b.requests:
+		}
+
+		var aduResponse, err = b.transporter.Send(req.aduRequest)
+		if err != nil && isPortError(err) {
+			b.record(err)
+			err = busError{err}
+		}
+		req.response <- busResponse{aduResponse: aduResponse, err: err}
+
+		if delay > 0 {
+			select {
+			case <-b.stop:
+				return
+			case <-time.After(delay):
+			}
+		}
+	}
+}
+
+// isPortError returns true if the error is raised by opening, reading or writing the serial port,
+// the other errors, e.g. timeout or short response, are caused by the slave.
+func isPortError(err error) bool {
+	switch err.(type) {
+	case syscall.Errno, *os.SyscallError, *os.PathError:
+		return true
+	}
+	return err != serial.ErrTimeout && strings.HasPrefix(err.Error(), "serial: ")
+}
+
+// record records the last error of the bus.
+func (b *rtuBus) record(err error) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.lastError = err.Error()
+	b.lastTime = now()
+}
+
+// status returns the status of the bus.
+func (b *rtuBus) status() v1alpha1.ModbusDeviceBusStatus {
+	rtuBuses.Lock()
+	var refs = b.refs
+	rtuBuses.Unlock()
+
+	b.Lock()
+	defer b.Unlock()
+	return v1alpha1.ModbusDeviceBusStatus{
+		Endpoint:         b.settings.config.Endpoint,
+		Devices:          int32(refs),
+		LastBusError:     b.lastError,
+		LastBusErrorTime: b.lastTime,
+	}
+}
+
+func newRTUBus(settings busSettings) (*rtuBus, error) {
+	var logger *log.Logger
+	if logflag.GetLogVerbosity() > 4 {
+		logger = log.New(os.Stdout, "modbus.client", log.LstdFlags)
+	}
+
+	var rtuConfig = settings.config
+	var rtuClientHandler = modbus.NewRTUClientHandler(rtuConfig.Endpoint)
+	rtuClientHandler.BaudRate = rtuConfig.BaudRate
+	rtuClientHandler.DataBits = rtuConfig.DataBits
+	rtuClientHandler.Parity = rtuConfig.Parity
+	rtuClientHandler.StopBits = rtuConfig.StopBits
+	rtuClientHandler.Timeout = settings.timeout
+	rtuClientHandler.Logger = logger
+
+	if err := rtuClientHandler.Connect(); err != nil {
+		return nil, errors.Wrap(err, "failed to connect via RTU")
+	}
+
+	var bus = &rtuBus{
+		settings:    settings,
+		transporter: rtuClientHandler,
+		requests:    make(chan busRequest),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go bus.run()
+	return bus, nil
+}
+
+// rtuBusClientHandler is the ModbusClientHandler of a slave on the bus,
+// it encodes/decodes the frames with its own slave ID, and sends the frames via the bus.
+type rtuBusClientHandler struct {
+	sync.Mutex
+
+	bus       *rtuBus
+	packager  *modbus.RTUClientHandler
+	lastError string
+	lastTime  *metav1.Time
+	closeOnce sync.Once
+}
+
+func (h *rtuBusClientHandler) Connect() modbus.Client {
+	return modbus.NewClient2(h, h)
+}
+
+func (h *rtuBusClientHandler) Close() error {
+	h.closeOnce.Do(func() {
+		rtuBuses.detach(h.bus)
+	})
+	return nil
+}
+
+// Encode implements the modbus.Packager interface.
+func (h *rtuBusClientHandler) Encode(pdu *modbus.ProtocolDataUnit) ([]byte, error) {
+	return h.packager.Encode(pdu)
+}
+
+// Verify implements the modbus.Packager interface.
+func (h *rtuBusClientHandler) Verify(aduRequest []byte, aduResponse []byte) error {
+	return h.record(h.packager.Verify(aduRequest, aduResponse))
+}
+
+// Decode implements the modbus.Packager interface,
+// the exception response is recorded as the slave error.
+func (h *rtuBusClientHandler) Decode(adu []byte) (*modbus.ProtocolDataUnit, error) {
+	var pdu, err = h.packager.Decode(adu)
+	if err == nil && pdu.FunctionCode&0x80 != 0 {
+		var exception = &modbus.ModbusError{FunctionCode: pdu.FunctionCode}
+		if len(pdu.Data) != 0 {
+			exception.ExceptionCode = pdu.Data[0]
+		}
+		_ = h.record(exception)
+	}
+	return pdu, h.record(err)
+}
+
+// Send implements the modbus.Transporter interface.
+func (h *rtuBusClientHandler) Send(aduRequest []byte) ([]byte, error) {
+	var aduResponse, err = h.bus.send(aduRequest)
+	if _, isBusErr := err.(busError); isBusErr {
+		return nil, err
+	}
+	return aduResponse, h.record(err)
+}
+
+// BusStatus returns the status of bus along with the last error of the slave.
+func (h *rtuBusClientHandler) BusStatus() *v1alpha1.ModbusDeviceBusStatus {
+	var status = h.bus.status()
+
+	h.Lock()
+	defer h.Unlock()
+	status.LastSlaveError = h.lastError
+	status.LastSlaveErrorTime = h.lastTime
+	return &status
+}
+
+// record records the last error of the slave, and returns the given error.
+func (h *rtuBusClientHandler) record(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	h.Lock()
+	defer h.Unlock()
+	h.lastError = err.Error()
+	h.lastTime = now()
+	return err
+}
@@ -0,0 +1,256 @@
+package physical
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/goburrow/modbus"
+	"github.com/goburrow/serial"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/octopus/adaptors/modbus/api/v1alpha1"
+)
+
+// fakeTransporter responds the requests by the slave ID.
+type fakeTransporter struct {
+	sending  int32
+	overlaps int32
+	closed   int32
+	respond  func(slaveID byte, aduRequest []byte) ([]byte, error)
+}
+
+func (t *fakeTransporter) Send(aduRequest []byte) ([]byte, error) {
+	if atomic.AddInt32(&t.sending, 1) > 1 {
+		atomic.AddInt32(&t.overlaps, 1)
+	}
+	defer atomic.AddInt32(&t.sending, -1)
+	time.Sleep(time.Millisecond)
+	return t.respond(aduRequest[0], aduRequest)
+}
+
+func (t *fakeTransporter) Close() error {
+	atomic.AddInt32(&t.closed, 1)
+	return nil
+}
+
+func newTestBusClientHandler(bus *rtuBus, slaveID byte) *rtuBusClientHandler {
+	var packager = modbus.NewRTUClientHandler(bus.settings.config.Endpoint)
+	packager.SlaveId = slaveID
+	return &rtuBusClientHandler{bus: bus, packager: packager}
+}
+
+func TestRTUBus(t *testing.T) {
+	var transporter = &fakeTransporter{
+		respond: func(slaveID byte, aduRequest []byte) ([]byte, error) {
+			var pdu *modbus.ProtocolDataUnit
+			switch slaveID {
+			case 1:
+				// responds 1 holding register
+				pdu = &modbus.ProtocolDataUnit{FunctionCode: aduRequest[1], Data: []byte{2, 0x00, 0x01}}
+			case 2:
+				// responds illegal data address exception
+				pdu = &modbus.ProtocolDataUnit{FunctionCode: aduRequest[1] | 0x80, Data: []byte{modbus.ExceptionCodeIllegalDataAddress}}
+			case 3:
+				return nil, serial.ErrTimeout
+			case 4:
+				return nil, io.ErrUnexpectedEOF
+			default:
+				return nil, syscall.EIO
+			}
+			var packager = modbus.NewRTUClientHandler("")
+			packager.SlaveId = slaveID
+			return packager.Encode(pdu)
+		},
+	}
+	var bus = &rtuBus{
+		settings: busSettings{
+			config: v1alpha1.ModbusDeviceProtocolRTU{Endpoint: "/dev/ttyUSB0"},
+		},
+		refs:        5,
+		transporter: transporter,
+		requests:    make(chan busRequest),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go bus.run()
+	defer close(bus.stop)
+
+	var handlers = []*rtuBusClientHandler{
+		newTestBusClientHandler(bus, 1),
+		newTestBusClientHandler(bus, 2),
+		newTestBusClientHandler(bus, 3),
+		newTestBusClientHandler(bus, 4),
+		newTestBusClientHandler(bus, 5),
+	}
+
+	// serializes the transactions from all slaves
+	var wg sync.WaitGroup
+	var results = make([]error, len(handlers))
+	for i := range handlers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				_, results[i] = handlers[i].Connect().ReadHoldingRegisters(0, 1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(0), atomic.LoadInt32(&transporter.overlaps))
+
+	// reports the slave errors and bus errors separately
+	assert.NoError(t, results[0])
+	var status = handlers[0].BusStatus()
+	assert.Equal(t, int32(5), status.Devices)
+	assert.Equal(t, "", status.LastSlaveError)
+	assert.Equal(t, "input/output error", status.LastBusError)
+
+	assert.Error(t, results[1])
+	status = handlers[1].BusStatus()
+	assert.Contains(t, status.LastSlaveError, "illegal data address")
+
+	assert.Equal(t, serial.ErrTimeout, results[2])
+	status = handlers[2].BusStatus()
+	assert.Equal(t, serial.ErrTimeout.Error(), status.LastSlaveError)
+
+	// the short response is caused by the slave
+	assert.Equal(t, io.ErrUnexpectedEOF, results[3])
+	status = handlers[3].BusStatus()
+	assert.Equal(t, io.ErrUnexpectedEOF.Error(), status.LastSlaveError)
+
+	assert.Error(t, results[4])
+	status = handlers[4].BusStatus()
+	assert.Equal(t, "", status.LastSlaveError)
+	assert.Equal(t, "input/output error", status.LastBusError)
+}
+
+func TestBusManager_Detach(t *testing.T) {
+	var transporter = &fakeTransporter{}
+	var bus = &rtuBus{
+		settings: busSettings{
+			config: v1alpha1.ModbusDeviceProtocolRTU{Endpoint: "/dev/ttyUSB0"},
+		},
+		refs:        2,
+		transporter: transporter,
+		requests:    make(chan busRequest),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go bus.run()
+	var manager = &busManager{buses: map[string]*rtuBus{"/dev/ttyUSB0": bus}}
+
+	// keeps the bus if there are other devices
+	manager.detach(bus)
+	assert.Contains(t, manager.buses, "/dev/ttyUSB0")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&transporter.closed))
+
+	// closes the serial port before dropping the bus
+	manager.detach(bus)
+	assert.NotContains(t, manager.buses, "/dev/ttyUSB0")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&transporter.closed))
+}
+
+func TestBusManager_Attach(t *testing.T) {
+	var shared = v1alpha1.ModbusDeviceProtocolRTU{
+		Endpoint: "/dev/ttyUSB0",
+		BaudRate: 19200,
+		DataBits: 8,
+		Parity:   "E",
+		StopBits: 1,
+	}
+	var withSettings = func(change func(config *v1alpha1.ModbusDeviceProtocolRTU)) v1alpha1.ModbusDeviceProtocolRTU {
+		var config = shared
+		config.WorkerID = 2
+		change(&config)
+		return config
+	}
+
+	type given struct {
+		config  v1alpha1.ModbusDeviceProtocolRTU
+		timeout time.Duration
+	}
+	type expected struct {
+		refs int
+		err  string
+	}
+	var testCases = []struct {
+		name     string
+		given    given
+		expected expected
+	}{
+		{
+			name: "same settings with another slave",
+			given: given{
+				config:  withSettings(func(*v1alpha1.ModbusDeviceProtocolRTU) {}),
+				timeout: time.Second,
+			},
+			expected: expected{
+				refs: 2,
+			},
+		},
+		{
+			name: "different baud rate and parity",
+			given: given{
+				config: withSettings(func(config *v1alpha1.ModbusDeviceProtocolRTU) {
+					config.BaudRate = 9600
+					config.Parity = "N"
+				}),
+				timeout: time.Second,
+			},
+			expected: expected{
+				refs: 1,
+				err:  `serial port /dev/ttyUSB0 is shared by the devices with different settings: baud rate 19200 != 9600, parity "E" != "N"`,
+			},
+		},
+		{
+			name: "different inter-frame delay",
+			given: given{
+				config: withSettings(func(config *v1alpha1.ModbusDeviceProtocolRTU) {
+					config.InterFrameDelay = &metav1.Duration{Duration: 10 * time.Millisecond}
+				}),
+				timeout: time.Second,
+			},
+			expected: expected{
+				refs: 1,
+				err:  `serial port /dev/ttyUSB0 is shared by the devices with different settings: inter-frame delay 0s != 10ms`,
+			},
+		},
+		{
+			name: "different timeout",
+			given: given{
+				config:  withSettings(func(*v1alpha1.ModbusDeviceProtocolRTU) {}),
+				timeout: 2 * time.Second,
+			},
+			expected: expected{
+				refs: 1,
+				err:  `serial port /dev/ttyUSB0 is shared by the devices with different settings: timeout 1s != 2s`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var config = shared
+		config.WorkerID = 1
+		var bus = &rtuBus{
+			settings: busSettings{config: config, timeout: time.Second},
+			refs:     1,
+		}
+		var manager = &busManager{buses: map[string]*rtuBus{shared.Endpoint: bus}}
+
+		var handler, err = manager.attach(tc.given.config, tc.given.timeout)
+		var actual expected
+		actual.refs = bus.refs
+		if err != nil {
+			actual.err = err.Error()
+		} else {
+			assert.Equal(t, bus, handler.(*rtuBusClientHandler).bus, "case %q", tc.name)
+			assert.Equal(t, byte(tc.given.config.WorkerID), handler.(*rtuBusClientHandler).packager.SlaveId, "case %q", tc.name)
+		}
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
@@ -1,12 +1,14 @@
 package physical
 
 import (
+	"encoding/json"
 	"io"
 	"reflect"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/goburrow/modbus"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
@@ -14,6 +16,9 @@
 	"github.com/rancher/octopus/adaptors/modbus/api/v1alpha1"
 	"github.com/rancher/octopus/adaptors/modbus/pkg/metadata"
 	api "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/adaptor/command"
+	"github.com/rancher/octopus/pkg/adaptor/poll"
+	pollapi "github.com/rancher/octopus/pkg/adaptor/poll/api"
 	"github.com/rancher/octopus/pkg/adaptor/socket/handler"
 	"github.com/rancher/octopus/pkg/mqtt"
 	"github.com/rancher/octopus/pkg/util/object"
@@ -25,6 +30,8 @@ type Device interface {
 	Shutdown()
 	// Configure uses to set up the device.
 	Configure(references api.ReferencesHandler, device *v1alpha1.ModbusDevice) error
+	// Act uses to execute an action on the device, and returns the result in form JSON bytes.
+	Act(name string, arguments []byte) ([]byte, error)
 }
 
 // NewDevice creates a Device.
@@ -43,6 +50,12 @@ func NewDevice(log logr.Logger, meta metav1.ObjectMeta, toLimb ModbusDeviceLimbS
 	bits = 8
 )
 
+// ModbusDeviceActionArguments defines the arguments of the action.
+type ModbusDeviceActionArguments struct {
+	// Specifies the value to write, the value of property spec is used if it is blank.
+	Value string `json:"value,omitempty"`
+}
+
 type modbusDevice struct {
 	sync.Mutex
 
@@ -79,7 +92,10 @@ func (d *modbusDevice) Configure(references api.ReferencesHandler, device *v1alp
 		}
 
 		if newExtension.MQTT != nil {
-			var cli, err = mqtt.NewClient(*newExtension.MQTT, object.GetControlledOwnerObjectReference(device), references)
+			var clientBuilder = mqtt.NewClientBuilder(*newExtension.MQTT, object.GetControlledOwnerObjectReference(device))
+			clientBuilder.Render(references)
+			clientBuilder.OnStatusChanged(d.receiveMQTTStatus)
+			var cli, err = clientBuilder.Build()
 			if err != nil {
 				return errors.Wrap(err, "failed to create MQTT client")
 			}
@@ -88,6 +104,11 @@ func (d *modbusDevice) Configure(references api.ReferencesHandler, device *v1alp
 			if err != nil {
 				return errors.Wrap(err, "failed to connect MQTT broker")
 			}
+			// receives the writing commands, e.g. the DCMD of Sparkplug B mode.
+			err = cli.Subscribe(nil, d.receiveCommand)
+			if err != nil {
+				return errors.Wrap(err, "failed to subscribe MQTT commands")
+			}
 			d.mqttClient = cli
 		}
 	}
@@ -113,6 +134,113 @@ func (d *modbusDevice) Configure(references api.ReferencesHandler, device *v1alp
 	return d.refresh(newSpec)
 }
 
+// Act writes the value of arguments to the writable property named as the action,
+// and then reads the property back as the result.
+func (d *modbusDevice) Act(name string, arguments []byte) ([]byte, error) {
+	defer runtime.HandleCrash(handler.NewPanicsCleanupSocketHandler(metadata.Endpoint))
+
+	var args ModbusDeviceActionArguments
+	if len(arguments) != 0 {
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal arguments")
+		}
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	var statusProp, err = d.write(name, args.Value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(statusProp)
+}
+
+// receiveCommand writes the values of command to the properties, and replies the results.
+func (d *modbusDevice) receiveCommand(msg mqtt.SubscribeMessage) {
+	command.Receive(d.log, metadata.Endpoint, msg, func(values map[string]interface{}) mqtt.CommandReply {
+		d.Lock()
+		defer d.Unlock()
+
+		var reply mqtt.CommandReply
+		for name, value := range values {
+			var result = mqtt.CommandResult{Name: name}
+			var statusProp, err = d.write(name, command.FormatValue(value))
+			if err != nil {
+				d.log.Error(err, "Error executing command", "property", name)
+				result.Error = err.Error()
+			} else {
+				result.Value = statusProp.Value
+			}
+			reply.Results = append(reply.Results, result)
+		}
+		return reply
+	})
+}
+
+// write writes the value to the writable property, the value of property spec is used if the value is blank,
+// and then reads the property back.
+func (d *modbusDevice) write(name string, value string) (*v1alpha1.ModbusDeviceStatusProperty, error) {
+	if d.modbusHandler == nil {
+		return nil, errors.New("device hasn't been configured")
+	}
+
+	var prop *v1alpha1.ModbusDeviceProperty
+	for i := range d.instance.Spec.Properties {
+		if d.instance.Spec.Properties[i].Name == name {
+			var p = d.instance.Spec.Properties[i]
+			prop = &p
+			break
+		}
+	}
+	if prop == nil {
+		return nil, errors.Errorf("cannot find property %s", name)
+	}
+	if prop.ReadOnly {
+		return nil, errors.Errorf("property %s is read-only", name)
+	}
+	if value != "" {
+		prop.Value = value
+	}
+
+	var writeStatuses, writeErr = d.writeProperties([]v1alpha1.ModbusDeviceProperty{*prop})
+
+	// records
+	var statusProp = v1alpha1.ModbusDeviceStatusProperty{
+		Name: prop.Name,
+		Type: prop.Type,
+	}
+	for _, p := range d.instance.Status.Properties {
+		if p.Name == prop.Name {
+			statusProp = p
+			break
+		}
+	}
+	if writeStatus := writeStatuses[prop.Name]; writeStatus != nil {
+		statusProp.LastWrite = writeStatus
+	}
+	var readValue, operatedValue, err = d.readProperty(prop)
+	if err != nil {
+		if writeErr == nil {
+			return nil, errors.Wrapf(err, "failed to read back property %s", prop.Name)
+		}
+		d.log.Error(err, "Error reading back property", "property", prop.Name)
+	} else {
+		d.log.V(4).Info("Read property", "property", prop.Name, "type", prop.Type)
+		statusProp.Value = readValue
+		statusProp.OperatedValue = operatedValue
+		statusProp.UpdatedAt = now()
+	}
+	d.setStatusProperty(statusProp)
+	if err := d.sync(); err != nil {
+		d.log.Error(err, "failed to sync")
+	}
+	if writeErr != nil {
+		return nil, writeErr
+	}
+	return &statusProp, nil
+}
+
 func (d *modbusDevice) Shutdown() {
 	d.Lock()
 	defer d.Unlock()
@@ -137,22 +265,20 @@ func (d *modbusDevice) Shutdown() {
 func (d *modbusDevice) refresh(newSpec v1alpha1.ModbusDeviceSpec) error {
 	var status = d.instance.Status
 	var staleSpec = d.instance.Spec
-	if !reflect.DeepEqual(staleSpec.Properties, newSpec.Properties) {
+	if !reflect.DeepEqual(getWritingProperties(staleSpec.Properties), getWritingProperties(newSpec.Properties)) {
 		d.stopFetch()
 
 		// configures properties
 		var specProps = newSpec.Properties
+		var writeStatuses, writeErr = d.writeProperties(specProps)
 		var statusProps = make([]v1alpha1.ModbusDeviceStatusProperty, 0, len(specProps))
 		for _, prop := range specProps {
-			if !prop.ReadOnly {
-				if err := d.writeProperty(&prop); err != nil {
-					return errors.Wrapf(err, "failed to write property %s", prop.Name)
-				}
-				d.log.V(4).Info("Write property", "property", prop.Name, "type", prop.Type)
-			}
 			var value, operatedValue, err = d.readProperty(&prop)
 			if err != nil {
-				return errors.Wrapf(err, "failed to read property %s", prop.Name)
+				if writeErr == nil {
+					return errors.Wrapf(err, "failed to read property %s", prop.Name)
+				}
+				d.log.Error(err, "Error reading property", "property", prop.Name)
 			}
 			d.log.V(4).Info("Read property", "property", prop.Name, "type", prop.Type)
 			statusProps = append(statusProps, v1alpha1.ModbusDeviceStatusProperty{
@@ -161,13 +287,26 @@ func (d *modbusDevice) refresh(newSpec v1alpha1.ModbusDeviceSpec) error {
 				OperatedValue: operatedValue,
 				Type:          prop.Type,
 				UpdatedAt:     now(),
+				LastWrite:     writeStatuses[prop.Name],
 			})
 		}
+		if writeErr != nil {
+			// reports the outcome of writing,
+			// the new spec is not recorded, so that the properties are configured again in next time.
+			d.instance.Status = v1alpha1.ModbusDeviceStatus{Properties: statusProps}
+			if err := d.sync(); err != nil {
+				d.log.Error(err, "failed to sync")
+			}
+			return writeErr
+		}
 		status = v1alpha1.ModbusDeviceStatus{Properties: statusProps}
+	} else if !reflect.DeepEqual(staleSpec.Properties, newSpec.Properties) || !reflect.DeepEqual(staleSpec.Parameters, newSpec.Parameters) {
+		// reschedules the polling without writing, e.g. the poll interval of property is changed.
+		d.stopFetch()
 	}
 
 	// fetches in backend
-	d.startFetch(newSpec.Parameters.GetSyncInterval())
+	d.startFetch(getFetchInterval(newSpec))
 
 	// records
 	d.instance.Spec = newSpec
@@ -177,6 +316,8 @@ func (d *modbusDevice) refresh(newSpec v1alpha1.ModbusDeviceSpec) error {
 
 // fetch is blocked, it is used to sync the modbus device status periodically,
 // it's worth noting that it just reads the properties from modbus device.
+// The properties are polled at their own intervals,
+// and the status is synchronized after polling or when any property changes according to the sync mode.
 func (d *modbusDevice) fetch(interval time.Duration, stop <-chan struct{}) {
 	defer runtime.HandleCrash(handler.NewPanicsCleanupSocketHandler(metadata.Endpoint))
 
@@ -188,40 +329,60 @@ func (d *modbusDevice) fetch(interval time.Duration, stop <-chan struct{}) {
 	var ticker = time.NewTicker(interval)
 	defer ticker.Stop()
 
+	var schedule = poll.NewSchedule(interval)
+	var filter *poll.Filter
+
 	for {
+		var now time.Time
 		select {
 		case <-stop:
 			return
-		case <-ticker.C:
+		case now = <-ticker.C:
 		}
 
 		d.Lock()
 		func() {
 			defer d.Unlock()
 
-			// read according to the properties defined by the spec,
+			var params = d.instance.Spec.Parameters
+			if filter == nil {
+				var options *pollapi.PollSyncOptions
+				if params != nil {
+					options = params.Sync
+				}
+				filter = poll.NewFilter(options)
+			}
+
+			// read according to the properties which are due to poll,
 			// and finally fill it back to status.
-			var specProps = d.instance.Spec.Properties
-			var statusProps = make([]v1alpha1.ModbusDeviceStatusProperty, 0, len(specProps))
-			for _, prop := range specProps {
-				var value, operatedValue, err = d.readProperty(&prop)
-				if err != nil {
-					// TODO give a way to feedback this to limb.
-					d.log.Error(err, "Error fetching device property", "property", prop.Name)
+			var syncInterval = params.GetSyncInterval()
+			var dueProps []v1alpha1.ModbusDeviceProperty
+			for _, prop := range d.instance.Spec.Properties {
+				if schedule.Due(prop.Name, prop.GetPollInterval(syncInterval), now) {
+					dueProps = append(dueProps, prop)
+				}
+			}
+			if len(dueProps) == 0 && !filter.ShouldSync(now) {
+				return
+			}
+
+			var statusProps = d.readProperties(dueProps)
+			for i, statusProp := range statusProps {
+				var value = statusProp.OperatedValue
+				if value == "" {
+					value = statusProp.Value
 				}
-				d.log.V(4).Info("Read property", "property", prop.Name, "type", prop.Type)
-				statusProps = append(statusProps, v1alpha1.ModbusDeviceStatusProperty{
-					Name:          prop.Name,
-					Value:         value,
-					OperatedValue: operatedValue,
-					Type:          prop.Type,
-					UpdatedAt:     now(),
-				})
+				filter.Observe(statusProp.Name, value, dueProps[i].Deadband)
+				d.setStatusProperty(statusProp)
+			}
+			if !filter.ShouldSync(now) {
+				return
 			}
-			d.instance.Status.Properties = statusProps
 			if err := d.sync(); err != nil {
 				d.log.Error(err, "failed to sync")
+				return
 			}
+			filter.Synced(now)
 		}()
 
 		select {
@@ -232,6 +393,21 @@ func() {
 	}
 }
 
+// setStatusProperty replaces the status property which has the same name,
+// the outcome of the last writing is kept if the given status property doesn't have one.
+func (d *modbusDevice) setStatusProperty(statusProp v1alpha1.ModbusDeviceStatusProperty) {
+	for i := range d.instance.Status.Properties {
+		if d.instance.Status.Properties[i].Name == statusProp.Name {
+			if statusProp.LastWrite == nil {
+				statusProp.LastWrite = d.instance.Status.Properties[i].LastWrite
+			}
+			d.instance.Status.Properties[i] = statusProp
+			return
+		}
+	}
+	d.instance.Status.Properties = append(d.instance.Status.Properties, statusProp)
+}
+
 // writeProperty writes data of a property to CoilRegister or HoldingRegister.
 func (d *modbusDevice) writeProperty(prop *v1alpha1.ModbusDeviceProperty) error {
 	var client = d.modbusHandler.Connect()
@@ -265,6 +441,91 @@ func (d *modbusDevice) readProperty(prop *v1alpha1.ModbusDeviceProperty) (value
 	}
 }
 
+// readProperties reads data of the properties, and returns the status properties in the same order.
+// The properties are read in blocks if configured, otherwise they are read one by one.
+func (d *modbusDevice) readProperties(props []v1alpha1.ModbusDeviceProperty) []v1alpha1.ModbusDeviceStatusProperty {
+	var statusProps = make([]v1alpha1.ModbusDeviceStatusProperty, len(props))
+	var readOne = func(idx int, read registerReadFunc) {
+		var prop = props[idx]
+		var value, operatedValue string
+		var err error
+		switch {
+		case read == nil:
+			value, operatedValue, err = d.readProperty(&prop)
+		case is1BitRegister(prop.Visitor.Register):
+			value, operatedValue, err = read1BitRegister(&prop, read)
+		default:
+			value, operatedValue, err = read16BitsRegister(&prop, read)
+		}
+		if err != nil {
+			// TODO give a way to feedback this to limb.
+			d.log.Error(err, "Error fetching device property", "property", prop.Name)
+		}
+		d.log.V(4).Info("Read property", "property", prop.Name, "type", prop.Type)
+		statusProps[idx] = v1alpha1.ModbusDeviceStatusProperty{
+			Name:          prop.Name,
+			Value:         value,
+			OperatedValue: operatedValue,
+			Type:          prop.Type,
+			UpdatedAt:     now(),
+		}
+	}
+
+	var params = d.instance.Spec.Parameters
+	if params == nil || params.BlockRead == nil {
+		for idx := range props {
+			readOne(idx, nil)
+		}
+		return statusProps
+	}
+
+	for _, block := range planReadBlocks(props, params.BlockRead) {
+		if len(block.props) == 1 {
+			readOne(block.props[0], nil)
+			continue
+		}
+
+		var data []byte
+		var read, err = getReadFunc(d.modbusHandler.Connect(), block.register)
+		if err == nil {
+			data, err = read(block.offset, block.quantity)
+		}
+		if err != nil {
+			// falls back to read the properties one by one,
+			// as the device may refuse to read the gap between the properties.
+			d.log.Error(err, "Error fetching device properties in block, fallback to read one by one", "register", block.register, "offset", block.offset, "quantity", block.quantity)
+			for _, idx := range block.props {
+				readOne(idx, nil)
+			}
+			continue
+		}
+		d.log.V(4).Info("Read block", "register", block.register, "offset", block.offset, "quantity", block.quantity)
+
+		for _, idx := range block.props {
+			readOne(idx, func(_, _ uint16) ([]byte, error) {
+				return block.slice(data, props[idx].Visitor)
+			})
+		}
+	}
+	return statusProps
+}
+
+// getReadFunc returns the func to read the given register.
+func getReadFunc(client modbus.Client, register v1alpha1.ModbusDeviceRegisterType) (registerReadFunc, error) {
+	switch register {
+	case v1alpha1.ModbusDeviceCoilRegister:
+		return client.ReadCoils, nil
+	case v1alpha1.ModbusDeviceDiscreteInputRegister:
+		return client.ReadDiscreteInputs, nil
+	case v1alpha1.ModbusDeviceHoldingRegister:
+		return client.ReadHoldingRegisters, nil
+	case v1alpha1.ModbusDeviceInputRegister:
+		return client.ReadInputRegisters, nil
+	default:
+		return nil, errors.Errorf("invalid readable register %s", register)
+	}
+}
+
 func (d *modbusDevice) stopFetch() {
 	if d.stop != nil {
 		close(d.stop)
@@ -281,13 +542,27 @@ func (d *modbusDevice) startFetch(fetchInterval time.Duration) {
 
 // sync combines all synchronization operations.
 func (d *modbusDevice) sync() error {
+	if d.mqttClient != nil {
+		var mqttStatus = d.mqttClient.Status()
+		d.instance.Status.Extension = &v1alpha1.ModbusDeviceExtensionStatus{MQTT: &mqttStatus}
+	} else {
+		d.instance.Status.Extension = nil
+	}
+	if reporter, ok := d.modbusHandler.(busStatusReporter); ok {
+		d.instance.Status.Bus = reporter.BusStatus()
+	} else {
+		d.instance.Status.Bus = nil
+	}
 	if d.toLimb != nil {
 		if err := d.toLimb(d.instance); err != nil {
 			return err
 		}
 	}
 	if d.mqttClient != nil {
-		if err := d.mqttClient.Publish(mqtt.PublishMessage{Payload: d.instance.Status}); err != nil {
+		// the status of extension is reported to limb only.
+		var status = d.instance.Status
+		status.Extension = nil
+		if err := d.mqttClient.Publish(mqtt.PublishMessage{Payload: status}); err != nil {
 			return err
 		}
 	}
@@ -295,6 +570,46 @@ func (d *modbusDevice) sync() error {
 	return nil
 }
 
+// receiveMQTTStatus reports the changed connection status of MQTT extension to limb.
+func (d *modbusDevice) receiveMQTTStatus() {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.mqttClient == nil || d.toLimb == nil {
+		return
+	}
+	var mqttStatus = d.mqttClient.Status()
+	d.instance.Status.Extension = &v1alpha1.ModbusDeviceExtensionStatus{MQTT: &mqttStatus}
+	if err := d.toLimb(d.instance); err != nil {
+		d.log.Error(err, "failed to report MQTT connection status")
+	}
+}
+
+// getFetchInterval returns the interval of ticking which can serve the polling intervals of all properties.
+func getFetchInterval(spec v1alpha1.ModbusDeviceSpec) time.Duration {
+	var syncInterval = spec.Parameters.GetSyncInterval()
+	var intervals = []time.Duration{syncInterval}
+	for _, prop := range spec.Properties {
+		intervals = append(intervals, prop.GetPollInterval(syncInterval))
+	}
+	return poll.TickInterval(intervals...)
+}
+
+// getWritingProperties returns the properties without the fields which don't affect the writing,
+// e.g. the description and the polling options.
+func getWritingProperties(props []v1alpha1.ModbusDeviceProperty) []v1alpha1.ModbusDeviceProperty {
+	if len(props) == 0 {
+		return nil
+	}
+	var ret = make([]v1alpha1.ModbusDeviceProperty, 0, len(props))
+	for _, prop := range props {
+		prop.Description = ""
+		prop.PollPropertyOptions = pollapi.PollPropertyOptions{}
+		ret = append(ret, prop)
+	}
+	return ret
+}
+
 func now() *metav1.Time {
 	var ret = metav1.Now()
 	return &ret
@@ -58,20 +58,8 @@ func NewModbusClientHandler(protocol v1alpha1.ModbusDeviceProtocol, timeout time
 	}
 
 	if protocol.RTU != nil {
-		var rtuConfig = protocol.RTU
-		rtuClientHandler := modbus.NewRTUClientHandler(rtuConfig.Endpoint)
-		rtuClientHandler.BaudRate = rtuConfig.BaudRate
-		rtuClientHandler.DataBits = rtuConfig.DataBits
-		rtuClientHandler.Parity = rtuConfig.Parity
-		rtuClientHandler.StopBits = rtuConfig.StopBits
-		rtuClientHandler.SlaveId = byte(rtuConfig.WorkerID)
-		rtuClientHandler.Timeout = timeout
-		rtuClientHandler.Logger = logger
-
-		if err := rtuClientHandler.Connect(); err != nil {
-			return nil, errors.Wrap(err, "failed to connect via RTU")
-		}
-		return &modbusClientHandler{handler: rtuClientHandler, closer: rtuClientHandler}, nil
+		// the RTU devices of the same serial port share one bus.
+		return rtuBuses.attach(*protocol.RTU, timeout)
 	}
 
 	return nil, errors.New("failed to create Modbus handler with empty protocol")
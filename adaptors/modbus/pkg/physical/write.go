@@ -0,0 +1,220 @@
+package physical
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/rancher/octopus/adaptors/modbus/api/v1alpha1"
+)
+
+// registerSnapshot is the raw data of the registers of a property, which is used to roll back the writing.
+type registerSnapshot struct {
+	prop *v1alpha1.ModbusDeviceProperty
+	data []byte
+}
+
+// writeProperties writes the writable properties in order, and returns the outcome of each written property by name.
+// The properties of the same write group are written as a transaction in the order of declaration,
+// once any of them fails, the written properties of the group are rolled back to the previous values.
+// It stops at the first failure, so the rest properties are not written.
+func (d *modbusDevice) writeProperties(props []v1alpha1.ModbusDeviceProperty) (map[string]*v1alpha1.ModbusDeviceWriteStatus, error) {
+	var groups [][]*v1alpha1.ModbusDeviceProperty
+	var groupIndexes = make(map[string]int)
+	for i := range props {
+		var prop = &props[i]
+		if prop.ReadOnly {
+			continue
+		}
+		if prop.WriteGroup == "" {
+			groups = append(groups, []*v1alpha1.ModbusDeviceProperty{prop})
+			continue
+		}
+		var idx, exist = groupIndexes[prop.WriteGroup]
+		if !exist {
+			idx = len(groups)
+			groupIndexes[prop.WriteGroup] = idx
+			groups = append(groups, nil)
+		}
+		groups[idx] = append(groups[idx], prop)
+	}
+
+	var statuses = make(map[string]*v1alpha1.ModbusDeviceWriteStatus)
+	for _, group := range groups {
+		if err := d.writeGroup(group, statuses); err != nil {
+			return statuses, err
+		}
+	}
+	return statuses, nil
+}
+
+// writeGroup writes the properties of a group, the group is transactional if the properties have the write group.
+func (d *modbusDevice) writeGroup(props []*v1alpha1.ModbusDeviceProperty, statuses map[string]*v1alpha1.ModbusDeviceWriteStatus) error {
+	var transactional = props[0].WriteGroup != ""
+	var snapshots []registerSnapshot
+
+	for _, prop := range props {
+		var status = &v1alpha1.ModbusDeviceWriteStatus{
+			Value: prop.Value,
+			Time:  now(),
+		}
+		statuses[prop.Name] = status
+
+		var snapshot registerSnapshot
+		var err error
+		if transactional {
+			snapshot, err = d.snapshot(prop)
+		}
+		if err == nil {
+			err = d.writeProperty(prop)
+		}
+		if err == nil {
+			// the written property is rolled back even if it fails to verify.
+			if transactional {
+				snapshots = append(snapshots, snapshot)
+			}
+			d.log.V(4).Info("Write property", "property", prop.Name, "type", prop.Type)
+			status.State = v1alpha1.ModbusDeviceWriteStateWritten
+			if prop.VerifyWrite {
+				err = d.verifyProperty(prop)
+				status.State = v1alpha1.ModbusDeviceWriteStateVerified
+			}
+		}
+		if err != nil {
+			status.State = v1alpha1.ModbusDeviceWriteStateFailed
+			status.Error = err.Error()
+			if transactional {
+				d.rollback(snapshots, statuses)
+			}
+			return errors.Wrapf(err, "failed to write property %s", prop.Name)
+		}
+	}
+	return nil
+}
+
+// rollback restores the snapshots in reverse order.
+func (d *modbusDevice) rollback(snapshots []registerSnapshot, statuses map[string]*v1alpha1.ModbusDeviceWriteStatus) {
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		var snapshot = snapshots[i]
+		var status = statuses[snapshot.prop.Name]
+		if err := d.restore(snapshot); err != nil {
+			d.log.Error(err, "Error rolling back property", "property", snapshot.prop.Name)
+			if status.State == v1alpha1.ModbusDeviceWriteStateFailed {
+				status.Error += ", and failed to roll back: " + err.Error()
+			} else {
+				status.State = v1alpha1.ModbusDeviceWriteStateFailed
+				status.Error = "failed to roll back: " + err.Error()
+			}
+			continue
+		}
+		d.log.V(4).Info("Rolled back property", "property", snapshot.prop.Name)
+		if status.State != v1alpha1.ModbusDeviceWriteStateFailed {
+			status.State = v1alpha1.ModbusDeviceWriteStateRolledBack
+		}
+	}
+}
+
+// snapshot reads the raw data of the property.
+func (d *modbusDevice) snapshot(prop *v1alpha1.ModbusDeviceProperty) (registerSnapshot, error) {
+	var client = d.modbusHandler.Connect()
+
+	var read registerReadFunc
+	switch prop.Visitor.Register {
+	case v1alpha1.ModbusDeviceCoilRegister:
+		read = client.ReadCoils
+	case v1alpha1.ModbusDeviceHoldingRegister:
+		read = client.ReadHoldingRegisters
+	default:
+		return registerSnapshot{}, errors.Errorf("invalid writable register %s", prop.Visitor.Register)
+	}
+
+	var data, err = read(prop.Visitor.Offset, prop.Visitor.Quantity)
+	if err != nil {
+		return registerSnapshot{}, errors.Wrap(err, "failed to read the previous value")
+	}
+	return registerSnapshot{prop: prop, data: data}, nil
+}
+
+// restore writes the raw data of the snapshot back.
+func (d *modbusDevice) restore(snapshot registerSnapshot) error {
+	var client = d.modbusHandler.Connect()
+	var visitor = snapshot.prop.Visitor
+
+	var err error
+	switch visitor.Register {
+	case v1alpha1.ModbusDeviceCoilRegister:
+		_, err = client.WriteMultipleCoils(visitor.Offset, visitor.Quantity, snapshot.data)
+	case v1alpha1.ModbusDeviceHoldingRegister:
+		_, err = client.WriteMultipleRegisters(visitor.Offset, visitor.Quantity, snapshot.data)
+	default:
+		err = errors.Errorf("invalid writable register %s", visitor.Register)
+	}
+	return err
+}
+
+// verifyProperty reads the property back, and compares with the written value.
+func (d *modbusDevice) verifyProperty(prop *v1alpha1.ModbusDeviceProperty) error {
+	var value, _, err = d.readProperty(prop)
+	if err != nil {
+		return errors.Wrap(err, "failed to read back")
+	}
+	if !valueEquals(prop.Type, prop.Value, value) {
+		return errors.Errorf("failed to verify, wrote %s but read back %s", prop.Value, value)
+	}
+	return nil
+}
+
+// valueEquals returns true if the written value is equal to the read value in the given type.
+func valueEquals(propType v1alpha1.ModbusDevicePropertyType, written, read string) bool {
+	if written == read {
+		return true
+	}
+
+	switch propType {
+	case v1alpha1.ModbusDevicePropertyTypeBoolean:
+		var w, werr = strconv.ParseBool(written)
+		var r, rerr = strconv.ParseBool(read)
+		return werr == nil && rerr == nil && w == r
+	case v1alpha1.ModbusDevicePropertyTypeHexString:
+		return strings.EqualFold(written, read)
+	case v1alpha1.ModbusDevicePropertyTypeFloat:
+		var w, werr = strconv.ParseFloat(written, 32)
+		var r, rerr = strconv.ParseFloat(read, 32)
+		return werr == nil && rerr == nil && float32(w) == float32(r)
+	case v1alpha1.ModbusDevicePropertyTypeDouble:
+		var w, werr = strconv.ParseFloat(written, 64)
+		var r, rerr = strconv.ParseFloat(read, 64)
+		return werr == nil && rerr == nil && w == r
+	case v1alpha1.ModbusDevicePropertyTypeInt16, v1alpha1.ModbusDevicePropertyTypeUint16:
+		return bitsEquals(written, read, 16)
+	case v1alpha1.ModbusDevicePropertyTypeInt, v1alpha1.ModbusDevicePropertyTypeInt32,
+		v1alpha1.ModbusDevicePropertyTypeUint, v1alpha1.ModbusDevicePropertyTypeUint32:
+		return bitsEquals(written, read, 32)
+	case v1alpha1.ModbusDevicePropertyTypeInt64, v1alpha1.ModbusDevicePropertyTypeUint64:
+		return bitsEquals(written, read, 64)
+	}
+	return false
+}
+
+// bitsEquals returns true if the integers are the same in the given bit size,
+// e.g. "-1" and "65535" are the same in 16 bits.
+func bitsEquals(written, read string, bitSize uint) bool {
+	var w, wok = parseBits(written, bitSize)
+	var r, rok = parseBits(read, bitSize)
+	return wok && rok && w == r
+}
+
+func parseBits(value string, bitSize uint) (uint64, bool) {
+	var mask = ^uint64(0)
+	if bitSize < 64 {
+		mask = 1<<bitSize - 1
+	}
+	if val, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return uint64(val) & mask, true
+	}
+	if val, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return val & mask, true
+	}
+	return 0, false
+}
@@ -0,0 +1,223 @@
+package physical
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/goburrow/modbus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/octopus/adaptors/modbus/api/v1alpha1"
+	"github.com/rancher/octopus/adaptors/modbus/pkg/simulator"
+	"github.com/rancher/octopus/pkg/util/log/zap"
+)
+
+// simulatorClientHandler sends the requests to the simulator directly,
+// the writing to the stuck addresses is acknowledged but not applied,
+// and the writing to the broken addresses is responded with exception.
+type simulatorClientHandler struct {
+	sim    *simulator.Simulator
+	stuck  map[uint16]bool
+	broken map[uint16]bool
+}
+
+func (h *simulatorClientHandler) Connect() modbus.Client {
+	return modbus.NewClient2(h, h)
+}
+
+func (h *simulatorClientHandler) Close() error {
+	return nil
+}
+
+func (h *simulatorClientHandler) Encode(pdu *modbus.ProtocolDataUnit) ([]byte, error) {
+	return append([]byte{1, pdu.FunctionCode}, pdu.Data...), nil
+}
+
+func (h *simulatorClientHandler) Decode(adu []byte) (*modbus.ProtocolDataUnit, error) {
+	return &modbus.ProtocolDataUnit{FunctionCode: adu[1], Data: adu[2:]}, nil
+}
+
+func (h *simulatorClientHandler) Verify(_ []byte, _ []byte) error {
+	return nil
+}
+
+func (h *simulatorClientHandler) Send(aduRequest []byte) ([]byte, error) {
+	var funcCode = aduRequest[1]
+	if funcCode == modbus.FuncCodeWriteMultipleRegisters {
+		var address = binary.BigEndian.Uint16(aduRequest[2:])
+		switch {
+		case h.broken[address]:
+			return []byte{1, funcCode | 0x80, modbus.ExceptionCodeServerDeviceFailure}, nil
+		case h.stuck[address]:
+			return append([]byte{1, funcCode}, aduRequest[2:6]...), nil
+		}
+	}
+	return append([]byte{1}, h.sim.Handle(1, aduRequest[1:])...), nil
+}
+
+func newTestWriteProperty(name string, offset uint16, value string) v1alpha1.ModbusDeviceProperty {
+	return v1alpha1.ModbusDeviceProperty{
+		Name: name,
+		Type: v1alpha1.ModbusDevicePropertyTypeUint16,
+		Visitor: v1alpha1.ModbusDevicePropertyVisitor{
+			Register:   v1alpha1.ModbusDeviceHoldingRegister,
+			Offset:     offset,
+			Quantity:   1,
+			Endianness: v1alpha1.ModbusDevicePropertyValueEndiannessBigEndian,
+		},
+		Value: value,
+	}
+}
+
+func Test_valueEquals(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		propType v1alpha1.ModbusDevicePropertyType
+		written  string
+		read     string
+		expected bool
+	}{
+		{name: "same string", propType: v1alpha1.ModbusDevicePropertyTypeUint16, written: "1", read: "1", expected: true},
+		{name: "negative int16", propType: v1alpha1.ModbusDevicePropertyTypeInt16, written: "-1", read: "65535", expected: true},
+		{name: "different int32", propType: v1alpha1.ModbusDevicePropertyTypeInt32, written: "1", read: "2", expected: false},
+		{name: "float precision", propType: v1alpha1.ModbusDevicePropertyTypeFloat, written: "20.10", read: "20.1", expected: true},
+		{name: "hex string case", propType: v1alpha1.ModbusDevicePropertyTypeHexString, written: "CAFE", read: "cafe", expected: true},
+		{name: "boolean", propType: v1alpha1.ModbusDevicePropertyTypeBoolean, written: "1", read: "true", expected: true},
+	}
+
+	for _, tc := range testCases {
+		var actual = valueEquals(tc.propType, tc.written, tc.read)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
+func TestModbusDevice_writeProperties(t *testing.T) {
+	var sim, err = simulator.NewSimulator(zap.NewNullLogger(), simulator.Config{
+		Slaves: []simulator.Slave{
+			{
+				ID: 1,
+				Registers: []simulator.Register{
+					{Name: "a", Register: v1alpha1.ModbusDeviceHoldingRegister, Offset: 0, Value: "10"},
+					{Name: "b", Register: v1alpha1.ModbusDeviceHoldingRegister, Offset: 1, Value: "20"},
+				},
+			},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	var handler = &simulatorClientHandler{
+		sim:    sim,
+		stuck:  map[uint16]bool{3: true},
+		broken: map[uint16]bool{2: true},
+	}
+	var d = &modbusDevice{
+		log:           zap.NewNullLogger(),
+		instance:      &v1alpha1.ModbusDevice{},
+		modbusHandler: handler,
+	}
+	var readRegister = func(offset uint16) string {
+		var prop = newTestWriteProperty("", offset, "")
+		var value, _, _ = d.readProperty(&prop)
+		return value
+	}
+
+	// verifies the writing
+	var verified = newTestWriteProperty("verified", 5, "42")
+	verified.VerifyWrite = true
+	var stuck = newTestWriteProperty("stuck", 3, "42")
+	stuck.VerifyWrite = true
+	statuses, err := d.writeProperties([]v1alpha1.ModbusDeviceProperty{verified, stuck})
+	assert.Error(t, err)
+	assert.Equal(t, v1alpha1.ModbusDeviceWriteStateVerified, statuses["verified"].State)
+	assert.Equal(t, v1alpha1.ModbusDeviceWriteStateFailed, statuses["stuck"].State)
+	assert.Contains(t, statuses["stuck"].Error, "failed to verify")
+
+	// rolls back the group
+	var first = newTestWriteProperty("a", 0, "11")
+	first.WriteGroup = "g"
+	var second = newTestWriteProperty("b", 1, "21")
+	second.WriteGroup = "g"
+	var broken = newTestWriteProperty("c", 2, "31")
+	broken.WriteGroup = "g"
+	var ungrouped = newTestWriteProperty("d", 4, "41")
+	statuses, err = d.writeProperties([]v1alpha1.ModbusDeviceProperty{first, ungrouped, second, broken})
+	assert.Error(t, err)
+	assert.Equal(t, v1alpha1.ModbusDeviceWriteStateRolledBack, statuses["a"].State)
+	assert.Equal(t, v1alpha1.ModbusDeviceWriteStateRolledBack, statuses["b"].State)
+	assert.Equal(t, v1alpha1.ModbusDeviceWriteStateFailed, statuses["c"].State)
+	assert.Nil(t, statuses["d"], "the properties after the failed group are not written")
+	assert.Equal(t, "10", readRegister(0))
+	assert.Equal(t, "20", readRegister(1))
+	assert.Equal(t, "0", readRegister(4))
+
+	// commits the group
+	statuses, err = d.writeProperties([]v1alpha1.ModbusDeviceProperty{first, second, ungrouped})
+	assert.NoError(t, err)
+	assert.Equal(t, v1alpha1.ModbusDeviceWriteStateWritten, statuses["a"].State)
+	assert.Equal(t, v1alpha1.ModbusDeviceWriteStateWritten, statuses["d"].State)
+	assert.Equal(t, "11", readRegister(0))
+	assert.Equal(t, "21", readRegister(1))
+	assert.Equal(t, "41", readRegister(4))
+}
+
+func TestModbusDevice_refresh(t *testing.T) {
+	var sim, err = simulator.NewSimulator(zap.NewNullLogger(), simulator.Config{
+		Slaves: []simulator.Slave{
+			{
+				ID: 1,
+				Registers: []simulator.Register{
+					{Name: "a", Register: v1alpha1.ModbusDeviceHoldingRegister, Offset: 0, Value: "10"},
+				},
+			},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	var d = &modbusDevice{
+		log:           zap.NewNullLogger(),
+		instance:      &v1alpha1.ModbusDevice{},
+		modbusHandler: &simulatorClientHandler{sim: sim},
+	}
+	defer d.Shutdown()
+	var refresh = func(prop v1alpha1.ModbusDeviceProperty) error {
+		d.Lock()
+		defer d.Unlock()
+		return d.refresh(v1alpha1.ModbusDeviceSpec{Properties: []v1alpha1.ModbusDeviceProperty{prop}})
+	}
+	var readRegister = func() string {
+		d.Lock()
+		defer d.Unlock()
+		var prop = newTestWriteProperty("", 0, "")
+		var value, _, _ = d.readProperty(&prop)
+		return value
+	}
+	var overwriteRegister = func(value string) {
+		d.Lock()
+		defer d.Unlock()
+		var prop = newTestWriteProperty("", 0, value)
+		_ = d.writeProperty(&prop)
+	}
+
+	// writes the property at first
+	var prop = newTestWriteProperty("a", 0, "11")
+	assert.NoError(t, refresh(prop))
+	assert.Equal(t, "11", readRegister())
+
+	// reschedules the polling without writing if the poll interval is changed
+	overwriteRegister("15")
+	var staleStop = d.stop
+	prop.PollInterval = &metav1.Duration{Duration: time.Second}
+	prop.Description = "changes the poll interval"
+	assert.NoError(t, refresh(prop))
+	assert.Equal(t, "15", readRegister())
+	assert.NotEqual(t, staleStop, d.stop)
+
+	// writes the property if the value is changed
+	prop.Value = "12"
+	assert.NoError(t, refresh(prop))
+	assert.Equal(t, "12", readRegister())
+}
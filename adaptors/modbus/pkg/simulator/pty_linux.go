@@ -0,0 +1,98 @@
+// +build linux
+
+package simulator
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+// PTY is a pseudo terminal pair, the simulator serves on the master side,
+// and the client connects to the slave side as a serial port.
+type PTY struct {
+	master *os.File
+	slave  *os.File
+}
+
+// Name returns the path of the slave side.
+func (p *PTY) Name() string {
+	return p.slave.Name()
+}
+
+func (p *PTY) Read(b []byte) (int, error) {
+	return p.master.Read(b)
+}
+
+func (p *PTY) Write(b []byte) (int, error) {
+	return p.master.Write(b)
+}
+
+func (p *PTY) Close() error {
+	var err = p.master.Close()
+	if serr := p.slave.Close(); err == nil {
+		err = serr
+	}
+	return err
+}
+
+// OpenPTY opens a pseudo terminal pair in raw mode.
+func OpenPTY() (*PTY, error) {
+	// opens in non-blocking mode, so that the reading can be interrupted by closing.
+	var fd, err = syscall.Open("/dev/ptmx", syscall.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open pseudo terminal master")
+	}
+	var master = os.NewFile(uintptr(fd), "/dev/ptmx")
+
+	var unlock int32
+	if err := ioctl(uintptr(fd), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); err != nil {
+		_ = master.Close()
+		return nil, errors.Wrap(err, "failed to unlock pseudo terminal")
+	}
+	var number uint32
+	if err := ioctl(uintptr(fd), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&number))); err != nil {
+		_ = master.Close()
+		return nil, errors.Wrap(err, "failed to get the number of pseudo terminal")
+	}
+
+	// keeps the slave side opening, so that the master side can be read after the client disconnects.
+	slave, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", number), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		_ = master.Close()
+		return nil, errors.Wrap(err, "failed to open pseudo terminal slave")
+	}
+	if err := makeRaw(slave.Fd()); err != nil {
+		_ = slave.Close()
+		_ = master.Close()
+		return nil, errors.Wrap(err, "failed to set pseudo terminal in raw mode")
+	}
+	return &PTY{master: master, slave: slave}, nil
+}
+
+// makeRaw sets the terminal in raw mode as same as cfmakeraw.
+func makeRaw(fd uintptr) error {
+	var termios syscall.Termios
+	if err := ioctl(fd, syscall.TCGETS, uintptr(unsafe.Pointer(&termios))); err != nil {
+		return err
+	}
+	termios.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	termios.Oflag &^= syscall.OPOST
+	termios.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	termios.Cflag &^= syscall.CSIZE | syscall.PARENB
+	termios.Cflag |= syscall.CS8
+	termios.Cc[syscall.VMIN] = 1
+	termios.Cc[syscall.VTIME] = 0
+	return ioctl(fd, syscall.TCSETS, uintptr(unsafe.Pointer(&termios)))
+}
+
+func ioctl(fd, request, arg uintptr) error {
+	var _, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, request, arg)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// +build !linux
+
+package simulator
+
+import (
+	"github.com/pkg/errors"
+)
+
+// PTY is a pseudo terminal pair, which is only supported on Linux.
+type PTY struct{}
+
+func (p *PTY) Name() string {
+	return ""
+}
+
+func (p *PTY) Read(_ []byte) (int, error) {
+	return 0, errors.New("pseudo terminal is not supported")
+}
+
+func (p *PTY) Write(_ []byte) (int, error) {
+	return 0, errors.New("pseudo terminal is not supported")
+}
+
+func (p *PTY) Close() error {
+	return nil
+}
+
+// OpenPTY is only supported on Linux.
+func OpenPTY() (*PTY, error) {
+	return nil, errors.New("pseudo terminal is not supported")
+}
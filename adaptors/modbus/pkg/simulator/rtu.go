@@ -0,0 +1,107 @@
+package simulator
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// rtuBroadcastID is the slave ID of broadcast, all slaves process the request without response.
+	rtuBroadcastID = 0
+	// rtuMaxSize is the max size of RTU frame.
+	rtuMaxSize = 256
+)
+
+var (
+	errUnknownFunction = errors.New("unknown function code")
+	errInvalidCRC      = errors.New("invalid CRC")
+)
+
+// ServeRTU is blocked, it serves the Modbus RTU requests from the serial port until the port is closed.
+func (s *Simulator) ServeRTU(port io.ReadWriter) error {
+	var reader = bufio.NewReaderSize(port, rtuMaxSize)
+	for {
+		var frame, err = readRTUFrame(reader)
+		if err != nil {
+			if err == errUnknownFunction || err == errInvalidCRC {
+				// the frame boundary is lost, drops the received bytes to resynchronize.
+				s.log.Error(err, "Error reading RTU frame, drop the received bytes")
+				_, _ = reader.Discard(reader.Buffered())
+				continue
+			}
+			return err
+		}
+
+		var slaveID, pdu = frame[0], frame[1 : len(frame)-2]
+		if slaveID == rtuBroadcastID {
+			for _, sl := range s.slaves {
+				_ = sl.handle(pdu)
+			}
+			continue
+		}
+		var resp = s.Handle(slaveID, pdu)
+		if resp == nil {
+			continue
+		}
+		var adu = make([]byte, 0, len(resp)+3)
+		adu = append(adu, slaveID)
+		adu = append(adu, resp...)
+		var crc = crc16(adu)
+		adu = append(adu, byte(crc), byte(crc>>8))
+		if _, err := port.Write(adu); err != nil {
+			return errors.Wrap(err, "failed to write RTU response")
+		}
+	}
+}
+
+// readRTUFrame reads a request frame, the size of frame is determined by the function code.
+func readRTUFrame(reader *bufio.Reader) ([]byte, error) {
+	var head, err = reader.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+
+	var size int
+	switch head[1] {
+	case funcCodeReadCoils, funcCodeReadDiscreteInputs, funcCodeReadHoldingRegisters, funcCodeReadInputRegisters,
+		funcCodeWriteSingleCoil, funcCodeWriteSingleRegister:
+		// slave ID + function code + address + quantity/value + CRC
+		size = 8
+	case funcCodeWriteMultipleCoils, funcCodeWriteMultipleRegisters:
+		// slave ID + function code + address + quantity + byte count + values + CRC
+		head, err = reader.Peek(7)
+		if err != nil {
+			return nil, err
+		}
+		size = 9 + int(head[6])
+	default:
+		return nil, errUnknownFunction
+	}
+
+	var frame = make([]byte, size)
+	if _, err = io.ReadFull(reader, frame); err != nil {
+		return nil, err
+	}
+	if crc := crc16(frame[:size-2]); byte(crc) != frame[size-2] || byte(crc>>8) != frame[size-1] {
+		return nil, errInvalidCRC
+	}
+	return frame, nil
+}
+
+// crc16 calculates the Modbus CRC16 of the data.
+func crc16(data []byte) uint16 {
+	var crc uint16 = 0xFFFF
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
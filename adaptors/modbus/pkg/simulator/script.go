@@ -0,0 +1,137 @@
+package simulator
+
+import (
+	"encoding/csv"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// generator generates the next value of the scripted register.
+type generator interface {
+	next(now time.Time) string
+}
+
+type rampGenerator struct {
+	script  RampScript
+	current float64
+}
+
+func (g *rampGenerator) next(_ time.Time) string {
+	var ret = g.current
+	g.current += g.script.Step
+	if (g.script.Step > 0 && g.current > g.script.To) || (g.script.Step < 0 && g.current < g.script.To) {
+		g.current = g.script.From
+	}
+	return formatFloat(ret)
+}
+
+type sineGenerator struct {
+	script SineScript
+	period time.Duration
+	start  time.Time
+}
+
+func (g *sineGenerator) next(now time.Time) string {
+	var phase = 2 * math.Pi * float64(now.Sub(g.start)) / float64(g.period)
+	return formatFloat(g.script.Offset + g.script.Amplitude*math.Sin(phase))
+}
+
+type randomGenerator struct {
+	script RandomScript
+	rand   *rand.Rand
+}
+
+func (g *randomGenerator) next(_ time.Time) string {
+	return formatFloat(g.script.Min + g.rand.Float64()*(g.script.Max-g.script.Min))
+}
+
+type csvGenerator struct {
+	values []string
+	loop   bool
+	index  int
+}
+
+func (g *csvGenerator) next(_ time.Time) string {
+	var ret = g.values[g.index]
+	if g.index+1 < len(g.values) {
+		g.index++
+	} else if g.loop {
+		g.index = 0
+	}
+	return ret
+}
+
+// newGenerator creates the generator of the given script.
+func newGenerator(script *Script, start time.Time, random *rand.Rand) (generator, error) {
+	switch {
+	case script.Ramp != nil:
+		var s = *script.Ramp
+		if s.Step == 0 {
+			s.Step = 1
+		}
+		if (s.Step > 0 && s.From > s.To) || (s.Step < 0 && s.From < s.To) {
+			return nil, errors.New("ramp cannot reach the end by the step")
+		}
+		return &rampGenerator{script: s, current: s.From}, nil
+	case script.Sine != nil:
+		var period = time.Minute
+		if script.Sine.Period != nil && script.Sine.Period.Duration > 0 {
+			period = script.Sine.Period.Duration
+		}
+		return &sineGenerator{script: *script.Sine, period: period, start: start}, nil
+	case script.Random != nil:
+		if script.Random.Max < script.Random.Min {
+			return nil, errors.New("the max of random is less than the min")
+		}
+		return &randomGenerator{script: *script.Random, rand: random}, nil
+	case script.CSV != nil:
+		var values, err = loadCSV(*script.CSV)
+		if err != nil {
+			return nil, err
+		}
+		return &csvGenerator{values: values, loop: !script.CSV.NoLoop}, nil
+	default:
+		return nil, errors.New("none of ramp, sine, random and csv is specified")
+	}
+}
+
+// loadCSV loads the values of the column from the CSV file.
+func loadCSV(script CSVScript) ([]string, error) {
+	var file, err = os.Open(script.File)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open CSV file %s", script.File)
+	}
+	defer file.Close()
+
+	var reader = csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read CSV file %s", script.File)
+	}
+	if script.Header && len(records) != 0 {
+		records = records[1:]
+	}
+
+	var values = make([]string, 0, len(records))
+	for i, record := range records {
+		if script.Column < 0 || script.Column >= len(record) {
+			return nil, errors.Errorf("column %d is not found in row %d of CSV file %s", script.Column, i+1, script.File)
+		}
+		values = append(values, record[script.Column])
+	}
+	if len(values) == 0 {
+		return nil, errors.Errorf("CSV file %s is empty", script.File)
+	}
+	return values, nil
+}
+
+func formatFloat(val float64) string {
+	return strconv.FormatFloat(val, 'f', -1, 64)
+}
@@ -0,0 +1,129 @@
+package simulator
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/rancher/octopus/adaptors/modbus/api/v1alpha1"
+)
+
+// scriptedRegister is a register whose value is changed by script.
+type scriptedRegister struct {
+	slave     *slave
+	register  *Register
+	generator generator
+}
+
+// Simulator simulates the Modbus slaves from a declarative register map,
+// the slaves can be served via TCP or RTU.
+type Simulator struct {
+	log      logr.Logger
+	interval time.Duration
+	slaves   []*slave
+	scripted []scriptedRegister
+}
+
+// Run is blocked, it updates the scripted values periodically until stopped.
+func (s *Simulator) Run(stop <-chan struct{}) {
+	defer runtime.HandleCrash()
+
+	if len(s.scripted) == 0 {
+		<-stop
+		return
+	}
+
+	var ticker = time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			s.update(now)
+		}
+	}
+}
+
+// update sets the scripted registers with the next values.
+func (s *Simulator) update(now time.Time) {
+	for _, r := range s.scripted {
+		var value = r.generator.next(now)
+		if err := r.slave.set(r.register, value); err != nil {
+			s.log.Error(err, "Error updating register", "register", r.register.Name, "value", value)
+			continue
+		}
+		s.log.V(4).Info("Updated register", "register", r.register.Name, "value", value)
+	}
+}
+
+// Handle processes the request PDU of the given slave ID, and returns the response PDU,
+// it returns nil if no slave accepts the ID.
+func (s *Simulator) Handle(slaveID uint8, pdu []byte) []byte {
+	var wildcard *slave
+	for _, sl := range s.slaves {
+		if sl.id == slaveID {
+			return sl.handle(pdu)
+		}
+		if sl.id == 0 {
+			wildcard = sl
+		}
+	}
+	if wildcard != nil {
+		return wildcard.handle(pdu)
+	}
+	return nil
+}
+
+// NewSimulator creates a Simulator with the given register map.
+func NewSimulator(log logr.Logger, config Config) (*Simulator, error) {
+	if len(config.Slaves) == 0 {
+		return nil, errors.New("no slave is defined")
+	}
+
+	var now = time.Now()
+	var random = rand.New(rand.NewSource(now.UnixNano()))
+	var sim = &Simulator{
+		log:      log,
+		interval: config.GetInterval(),
+	}
+	var ids = make(map[uint8]struct{}, len(config.Slaves))
+	for i := range config.Slaves {
+		var slaveConfig = &config.Slaves[i]
+		if _, exist := ids[slaveConfig.ID]; exist {
+			return nil, errors.Errorf("slave %d is duplicated", slaveConfig.ID)
+		}
+		ids[slaveConfig.ID] = struct{}{}
+
+		var sl = newSlave(slaveConfig.ID)
+		for j := range slaveConfig.Registers {
+			var reg = &slaveConfig.Registers[j]
+			switch reg.Register {
+			case v1alpha1.ModbusDeviceCoilRegister, v1alpha1.ModbusDeviceDiscreteInputRegister,
+				v1alpha1.ModbusDeviceHoldingRegister, v1alpha1.ModbusDeviceInputRegister:
+			default:
+				return nil, errors.Errorf("invalid register type %s of register %s", reg.Register, reg.Name)
+			}
+
+			if err := sl.set(reg, reg.Value); err != nil {
+				return nil, errors.Wrapf(err, "failed to set the initial value of register %s", reg.Name)
+			}
+			if reg.Script == nil {
+				continue
+			}
+			var gen, err = newGenerator(reg.Script, now, random)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to create the script of register %s", reg.Name)
+			}
+			sim.scripted = append(sim.scripted, scriptedRegister{slave: sl, register: reg, generator: gen})
+		}
+		sim.slaves = append(sim.slaves, sl)
+	}
+	// the scripted values are available before serving.
+	sim.update(now)
+	return sim, nil
+}
@@ -0,0 +1,259 @@
+package simulator
+
+import (
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/goburrow/modbus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/octopus/adaptors/modbus/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/util/log/zap"
+)
+
+func newTestConfig() Config {
+	return Config{
+		Slaves: []Slave{
+			{
+				ID: 1,
+				Registers: []Register{
+					{
+						Name:     "temperature",
+						Register: v1alpha1.ModbusDeviceHoldingRegister,
+						Offset:   0,
+						Type:     v1alpha1.ModbusDevicePropertyTypeFloat,
+						Value:    "20.5",
+					},
+					{
+						Name:     "humidity",
+						Register: v1alpha1.ModbusDeviceInputRegister,
+						Offset:   2,
+						Type:     v1alpha1.ModbusDevicePropertyTypeUint16,
+						Value:    "186",
+					},
+					{
+						Name:     "alarm",
+						Register: v1alpha1.ModbusDeviceCoilRegister,
+						Offset:   3,
+						Value:    "true",
+					},
+				},
+			},
+			{
+				ID: 2,
+				Registers: []Register{
+					{
+						Name:     "counter",
+						Register: v1alpha1.ModbusDeviceHoldingRegister,
+						Offset:   0,
+						Script: &Script{
+							Ramp: &RampScript{From: 10, To: 20},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_encodeBytes(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		given    Register
+		value    string
+		expected []byte
+	}{
+		{
+			name:     "default type",
+			given:    Register{},
+			value:    "258",
+			expected: []byte{0x01, 0x02},
+		},
+		{
+			name:     "rounded int",
+			given:    Register{Type: v1alpha1.ModbusDevicePropertyTypeInt16},
+			value:    "-1.6",
+			expected: []byte{0xFF, 0xFE},
+		},
+		{
+			name:     "little endian int32",
+			given:    Register{Type: v1alpha1.ModbusDevicePropertyTypeInt32, Endianness: v1alpha1.ModbusDevicePropertyValueEndiannessLittleEndian},
+			value:    "1",
+			expected: []byte{0x01, 0x00, 0x00, 0x00},
+		},
+		{
+			name:     "float",
+			given:    Register{Type: v1alpha1.ModbusDevicePropertyTypeFloat},
+			value:    "20.5",
+			expected: []byte{0x41, 0xA4, 0x00, 0x00},
+		},
+		{
+			name:     "boolean",
+			given:    Register{Type: v1alpha1.ModbusDevicePropertyTypeBoolean},
+			value:    "true",
+			expected: []byte{0x00, 0x01},
+		},
+		{
+			name:     "hex string",
+			given:    Register{Type: v1alpha1.ModbusDevicePropertyTypeHexString},
+			value:    "cafe",
+			expected: []byte{0xCA, 0xFE},
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual, err = encodeBytes(&tc.given, tc.value)
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
+func Test_generators(t *testing.T) {
+	var start = time.Now()
+
+	var ramp, _ = newGenerator(&Script{Ramp: &RampScript{From: 0, To: 2}}, start, nil)
+	var rampValues []string
+	for i := 0; i < 4; i++ {
+		rampValues = append(rampValues, ramp.next(start))
+	}
+	assert.Equal(t, []string{"0", "1", "2", "0"}, rampValues)
+
+	var sine, _ = newGenerator(&Script{Sine: &SineScript{Offset: 10, Amplitude: 5, Period: &metav1.Duration{Duration: 4 * time.Second}}}, start, nil)
+	assert.Equal(t, "15", sine.next(start.Add(time.Second)))
+
+	var dir, err = ioutil.TempDir("", "simulator")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	var file = filepath.Join(dir, "values.csv")
+	_ = ioutil.WriteFile(file, []byte("time,value\n0,1.5\n1,2.5\n"), 0644)
+	csvGen, err := newGenerator(&Script{CSV: &CSVScript{File: file, Column: 1, Header: true}}, start, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "1.5", csvGen.next(start))
+		assert.Equal(t, "2.5", csvGen.next(start))
+		assert.Equal(t, "1.5", csvGen.next(start))
+	}
+
+	_, err = newGenerator(&Script{Ramp: &RampScript{From: 2, To: 0}}, start, nil)
+	assert.Error(t, err)
+}
+
+func TestSimulator_ServeTCP(t *testing.T) {
+	var sim, err = NewSimulator(zap.WrapAsLogr(zap.NewDevelopmentLogger()), newTestConfig())
+	if !assert.NoError(t, err) {
+		return
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var stop = make(chan struct{})
+	defer close(stop)
+	go func() {
+		_ = sim.ServeTCP(listener, stop)
+	}()
+
+	var handler = modbus.NewTCPClientHandler(listener.Addr().String())
+	handler.SlaveId = 1
+	handler.Timeout = time.Second
+	defer handler.Close()
+	var cli = modbus.NewClient(handler)
+
+	// reads the initial values
+	data, err := cli.ReadHoldingRegisters(0, 2)
+	if assert.NoError(t, err) {
+		assert.Equal(t, float32(20.5), math.Float32frombits(uint32(data[0])<<24|uint32(data[1])<<16|uint32(data[2])<<8|uint32(data[3])))
+	}
+	data, err = cli.ReadInputRegisters(2, 1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte{0x00, 0xBA}, data)
+	}
+	data, err = cli.ReadCoils(0, 4)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte{0x08}, data)
+	}
+
+	// writes and reads back
+	_, err = cli.WriteMultipleCoils(0, 2, []byte{0x03})
+	assert.NoError(t, err)
+	data, err = cli.ReadCoils(0, 4)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte{0x0B}, data)
+	}
+	_, err = cli.WriteSingleRegister(10, 0x1234)
+	assert.NoError(t, err)
+	data, err = cli.ReadHoldingRegisters(10, 1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte{0x12, 0x34}, data)
+	}
+
+	// responds exception
+	_, err = cli.ReadHoldingRegisters(65535, 2)
+	if assert.Error(t, err) {
+		assert.Equal(t, byte(exceptionCodeIllegalDataAddress), err.(*modbus.ModbusError).ExceptionCode)
+	}
+
+	// updates the scripted values
+	handler.SlaveId = 2
+	data, err = cli.ReadHoldingRegisters(0, 1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte{0x00, 10}, data)
+	}
+	sim.update(time.Now())
+	data, err = cli.ReadHoldingRegisters(0, 1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte{0x00, 11}, data)
+	}
+}
+
+func TestSimulator_ServeRTU(t *testing.T) {
+	var sim, err = NewSimulator(zap.WrapAsLogr(zap.NewDevelopmentLogger()), newTestConfig())
+	if !assert.NoError(t, err) {
+		return
+	}
+	pty, err := OpenPTY()
+	if err != nil {
+		t.Skipf("pseudo terminal is not available, %v", err)
+	}
+	defer pty.Close()
+	go func() {
+		_ = sim.ServeRTU(pty)
+	}()
+
+	var handler = modbus.NewRTUClientHandler(pty.Name())
+	handler.BaudRate = 19200
+	handler.Timeout = time.Second
+	defer handler.Close()
+	var cli = modbus.NewClient(handler)
+
+	// serves multiple slaves on the same port
+	handler.SlaveId = 1
+	data, err := cli.ReadInputRegisters(2, 1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte{0x00, 0xBA}, data)
+	}
+	_, err = cli.WriteMultipleRegisters(5, 2, []byte{0x00, 0x01, 0x00, 0x02})
+	assert.NoError(t, err)
+	data, err = cli.ReadHoldingRegisters(5, 2)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte{0x00, 0x01, 0x00, 0x02}, data)
+	}
+	handler.SlaveId = 2
+	data, err = cli.ReadHoldingRegisters(0, 1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte{0x00, 10}, data)
+	}
+
+	// doesn't respond to the nonexistent slave
+	handler.SlaveId = 3
+	handler.Timeout = 100 * time.Millisecond
+	_, err = cli.ReadHoldingRegisters(0, 1)
+	assert.Error(t, err)
+}
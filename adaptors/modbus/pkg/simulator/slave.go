@@ -0,0 +1,360 @@
+package simulator
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/rancher/octopus/adaptors/modbus/api/v1alpha1"
+)
+
+const (
+	funcCodeReadCoils              = 0x01
+	funcCodeReadDiscreteInputs     = 0x02
+	funcCodeReadHoldingRegisters   = 0x03
+	funcCodeReadInputRegisters     = 0x04
+	funcCodeWriteSingleCoil        = 0x05
+	funcCodeWriteSingleRegister    = 0x06
+	funcCodeWriteMultipleCoils     = 0x0F
+	funcCodeWriteMultipleRegisters = 0x10
+
+	exceptionCodeIllegalFunction    = 0x01
+	exceptionCodeIllegalDataAddress = 0x02
+	exceptionCodeIllegalDataValue   = 0x03
+
+	// addressSpace is the quantity of each register type.
+	addressSpace = 65536
+	// maxReadBits is the max quantity of 1-bit registers in one read request.
+	maxReadBits = 2000
+	// maxReadRegisters is the max quantity of 16-bits registers in one read request.
+	maxReadRegisters = 125
+	// maxWriteBits is the max quantity of 1-bit registers in one write request.
+	maxWriteBits = 1968
+	// maxWriteRegisters is the max quantity of 16-bits registers in one write request.
+	maxWriteRegisters = 123
+)
+
+// slave holds the registers of a simulated slave, and processes the requests.
+type slave struct {
+	sync.Mutex
+
+	id               uint8
+	coils            []bool
+	discreteInputs   []bool
+	holdingRegisters []uint16
+	inputRegisters   []uint16
+}
+
+// set sets the value of the given register.
+func (s *slave) set(reg *Register, value string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	switch reg.Register {
+	case v1alpha1.ModbusDeviceCoilRegister, v1alpha1.ModbusDeviceDiscreteInputRegister:
+		var bits, err = encodeBits(reg, value)
+		if err != nil {
+			return err
+		}
+		if int(reg.Offset)+len(bits) > addressSpace {
+			return errors.Errorf("register %s is out of address space", reg.Name)
+		}
+		var target = s.coils
+		if reg.Register == v1alpha1.ModbusDeviceDiscreteInputRegister {
+			target = s.discreteInputs
+		}
+		copy(target[reg.Offset:], bits)
+	case v1alpha1.ModbusDeviceHoldingRegister, v1alpha1.ModbusDeviceInputRegister:
+		var data, err = encodeBytes(reg, value)
+		if err != nil {
+			return err
+		}
+		if int(reg.Offset)+len(data)/2 > addressSpace {
+			return errors.Errorf("register %s is out of address space", reg.Name)
+		}
+		var target = s.holdingRegisters
+		if reg.Register == v1alpha1.ModbusDeviceInputRegister {
+			target = s.inputRegisters
+		}
+		for i := 0; i < len(data)/2; i++ {
+			target[int(reg.Offset)+i] = binary.BigEndian.Uint16(data[i*2:])
+		}
+	default:
+		return errors.Errorf("invalid register type %s of register %s", reg.Register, reg.Name)
+	}
+	return nil
+}
+
+// handle processes the request PDU, and returns the response PDU.
+func (s *slave) handle(pdu []byte) []byte {
+	if len(pdu) == 0 {
+		return nil
+	}
+	var funcCode = pdu[0]
+	var data = pdu[1:]
+
+	s.Lock()
+	defer s.Unlock()
+
+	var resp []byte
+	var exceptionCode byte
+	switch funcCode {
+	case funcCodeReadCoils:
+		resp, exceptionCode = readBits(s.coils, data)
+	case funcCodeReadDiscreteInputs:
+		resp, exceptionCode = readBits(s.discreteInputs, data)
+	case funcCodeReadHoldingRegisters:
+		resp, exceptionCode = readRegisters(s.holdingRegisters, data)
+	case funcCodeReadInputRegisters:
+		resp, exceptionCode = readRegisters(s.inputRegisters, data)
+	case funcCodeWriteSingleCoil:
+		resp, exceptionCode = writeSingleBit(s.coils, data)
+	case funcCodeWriteSingleRegister:
+		resp, exceptionCode = writeSingleRegister(s.holdingRegisters, data)
+	case funcCodeWriteMultipleCoils:
+		resp, exceptionCode = writeBits(s.coils, data)
+	case funcCodeWriteMultipleRegisters:
+		resp, exceptionCode = writeRegisters(s.holdingRegisters, data)
+	default:
+		exceptionCode = exceptionCodeIllegalFunction
+	}
+	if exceptionCode != 0 {
+		return []byte{funcCode | 0x80, exceptionCode}
+	}
+	return append([]byte{funcCode}, resp...)
+}
+
+// parseRange parses the address and quantity of request, and validates them.
+func parseRange(data []byte, maxQuantity int) (address, quantity int, exceptionCode byte) {
+	if len(data) < 4 {
+		return 0, 0, exceptionCodeIllegalDataValue
+	}
+	address = int(binary.BigEndian.Uint16(data[0:]))
+	quantity = int(binary.BigEndian.Uint16(data[2:]))
+	if quantity < 1 || quantity > maxQuantity {
+		return 0, 0, exceptionCodeIllegalDataValue
+	}
+	if address+quantity > addressSpace {
+		return 0, 0, exceptionCodeIllegalDataAddress
+	}
+	return address, quantity, 0
+}
+
+func readBits(bits []bool, data []byte) ([]byte, byte) {
+	var address, quantity, exceptionCode = parseRange(data, maxReadBits)
+	if exceptionCode != 0 {
+		return nil, exceptionCode
+	}
+	var resp = make([]byte, 1+(quantity+7)/8)
+	resp[0] = byte(len(resp) - 1)
+	for i := 0; i < quantity; i++ {
+		if bits[address+i] {
+			resp[1+i/8] |= 1 << uint(i%8)
+		}
+	}
+	return resp, 0
+}
+
+func readRegisters(registers []uint16, data []byte) ([]byte, byte) {
+	var address, quantity, exceptionCode = parseRange(data, maxReadRegisters)
+	if exceptionCode != 0 {
+		return nil, exceptionCode
+	}
+	var resp = make([]byte, 1+quantity*2)
+	resp[0] = byte(quantity * 2)
+	for i := 0; i < quantity; i++ {
+		binary.BigEndian.PutUint16(resp[1+i*2:], registers[address+i])
+	}
+	return resp, 0
+}
+
+func writeSingleBit(bits []bool, data []byte) ([]byte, byte) {
+	if len(data) != 4 {
+		return nil, exceptionCodeIllegalDataValue
+	}
+	var address = binary.BigEndian.Uint16(data[0:])
+	switch binary.BigEndian.Uint16(data[2:]) {
+	case 0xFF00:
+		bits[address] = true
+	case 0x0000:
+		bits[address] = false
+	default:
+		return nil, exceptionCodeIllegalDataValue
+	}
+	return data, 0
+}
+
+func writeSingleRegister(registers []uint16, data []byte) ([]byte, byte) {
+	if len(data) != 4 {
+		return nil, exceptionCodeIllegalDataValue
+	}
+	registers[binary.BigEndian.Uint16(data[0:])] = binary.BigEndian.Uint16(data[2:])
+	return data, 0
+}
+
+func writeBits(bits []bool, data []byte) ([]byte, byte) {
+	var address, quantity, exceptionCode = parseRange(data, maxWriteBits)
+	if exceptionCode != 0 {
+		return nil, exceptionCode
+	}
+	if len(data) < 5 || int(data[4]) != (quantity+7)/8 || len(data) != 5+int(data[4]) {
+		return nil, exceptionCodeIllegalDataValue
+	}
+	var values = data[5:]
+	for i := 0; i < quantity; i++ {
+		bits[address+i] = values[i/8]&(1<<uint(i%8)) != 0
+	}
+	return data[:4], 0
+}
+
+func writeRegisters(registers []uint16, data []byte) ([]byte, byte) {
+	var address, quantity, exceptionCode = parseRange(data, maxWriteRegisters)
+	if exceptionCode != 0 {
+		return nil, exceptionCode
+	}
+	if len(data) < 5 || int(data[4]) != quantity*2 || len(data) != 5+int(data[4]) {
+		return nil, exceptionCodeIllegalDataValue
+	}
+	var values = data[5:]
+	for i := 0; i < quantity; i++ {
+		registers[address+i] = binary.BigEndian.Uint16(values[i*2:])
+	}
+	return data[:4], 0
+}
+
+// encodeBits encodes the value of 1-bit register, which is in the same form as the adaptor reads.
+func encodeBits(reg *Register, value string) ([]bool, error) {
+	switch reg.Type {
+	case "", v1alpha1.ModbusDevicePropertyTypeBoolean:
+		var val, err = parseBool(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the value of register %s to boolean", reg.Name)
+		}
+		return []bool{val}, nil
+	case v1alpha1.ModbusDevicePropertyTypeHexString:
+		var data, err = hex.DecodeString(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the hex value of register %s to byte array", reg.Name)
+		}
+		var bits = make([]bool, len(data)*8)
+		for i := range bits {
+			bits[i] = data[i/8]&(1<<uint(i%8)) != 0
+		}
+		return bits, nil
+	default:
+		return nil, errors.Errorf("1-bit register %s cannot set as %s type", reg.Name, reg.Type)
+	}
+}
+
+// encodeBytes encodes the value of 16-bits register, which is in the same form as the adaptor reads.
+func encodeBytes(reg *Register, value string) ([]byte, error) {
+	var endianness = reg.Endianness
+	if endianness == "" {
+		endianness = v1alpha1.ModbusDevicePropertyValueEndiannessBigEndian
+	}
+
+	var data []byte
+	switch reg.Type {
+	case v1alpha1.ModbusDevicePropertyTypeHexString:
+		var val, err = hex.DecodeString(value)
+		if err != nil || len(val)%2 != 0 {
+			return nil, errors.Errorf("failed to convert the hex value of register %s to registers", reg.Name)
+		}
+		data = val
+	case v1alpha1.ModbusDevicePropertyTypeBoolean:
+		var val, err = parseBool(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the value of register %s to boolean", reg.Name)
+		}
+		data = []byte{0, 0}
+		if val {
+			data[1] = 1
+		}
+	case "", v1alpha1.ModbusDevicePropertyTypeInt16, v1alpha1.ModbusDevicePropertyTypeUint16:
+		var val, err = parseInteger(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the value of register %s to integer", reg.Name)
+		}
+		data = make([]byte, 2)
+		endianness.PutUint16(data, uint16(val))
+	case v1alpha1.ModbusDevicePropertyTypeInt, v1alpha1.ModbusDevicePropertyTypeInt32,
+		v1alpha1.ModbusDevicePropertyTypeUint, v1alpha1.ModbusDevicePropertyTypeUint32:
+		var val, err = parseInteger(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the value of register %s to integer", reg.Name)
+		}
+		data = make([]byte, 4)
+		endianness.PutUint32(data, uint32(val))
+	case v1alpha1.ModbusDevicePropertyTypeInt64, v1alpha1.ModbusDevicePropertyTypeUint64:
+		var val, err = parseInteger(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the value of register %s to integer", reg.Name)
+		}
+		data = make([]byte, 8)
+		endianness.PutUint64(data, uint64(val))
+	case v1alpha1.ModbusDevicePropertyTypeFloat:
+		var val, err = strconv.ParseFloat(value, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the value of register %s to float32", reg.Name)
+		}
+		data = make([]byte, 4)
+		endianness.PutUint32(data, math.Float32bits(float32(val)))
+	case v1alpha1.ModbusDevicePropertyTypeDouble:
+		var val, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the value of register %s to float64", reg.Name)
+		}
+		data = make([]byte, 8)
+		endianness.PutUint64(data, math.Float64bits(val))
+	default:
+		return nil, errors.Errorf("16-bits register %s cannot set as %s type", reg.Name, reg.Type)
+	}
+	return data, nil
+}
+
+// parseBool parses the boolean value, the numeric value is true if it is not 0.
+func parseBool(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	if val, err := strconv.ParseBool(value); err == nil {
+		return val, nil
+	}
+	var val, err = strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false, err
+	}
+	return val != 0, nil
+}
+
+// parseInteger parses the integer value, the float value is rounded.
+func parseInteger(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if val, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return val, nil
+	}
+	if val, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return int64(val), nil
+	}
+	var val, err = strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(math.Round(val)), nil
+}
+
+func newSlave(id uint8) *slave {
+	return &slave{
+		id:               id,
+		coils:            make([]bool, addressSpace),
+		discreteInputs:   make([]bool, addressSpace),
+		holdingRegisters: make([]uint16, addressSpace),
+		inputRegisters:   make([]uint16, addressSpace),
+	}
+}
@@ -0,0 +1,91 @@
+package simulator
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+)
+
+const (
+	// tcpHeaderSize is the size of MBAP header.
+	tcpHeaderSize = 7
+	// tcpMaxLength is the max length of unit ID and PDU in MBAP header.
+	tcpMaxLength = 254
+)
+
+// ServeTCP is blocked, it serves the Modbus TCP requests from the listener until stopped.
+func (s *Simulator) ServeTCP(listener net.Listener, stop <-chan struct{}) error {
+	go func() {
+		<-stop
+		_ = listener.Close()
+	}()
+
+	s.log.Info("Serving Modbus TCP", "address", listener.Addr().String())
+	for {
+		var conn, err = listener.Accept()
+		if err != nil {
+			select {
+			case <-stop:
+				return nil
+			default:
+			}
+			return errors.Wrap(err, "failed to accept Modbus TCP connection")
+		}
+		go s.serveTCPConn(conn, stop)
+	}
+}
+
+// serveTCPConn processes the requests of the connection one by one until the connection is closed.
+func (s *Simulator) serveTCPConn(conn net.Conn, stop <-chan struct{}) {
+	defer runtime.HandleCrash()
+
+	var done = make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stop:
+		case <-done:
+		}
+		_ = conn.Close()
+	}()
+
+	var log = s.log.WithValues("remote", conn.RemoteAddr().String())
+	log.V(4).Info("Accepted connection")
+	for {
+		var header = make([]byte, tcpHeaderSize)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			if err != io.EOF {
+				log.V(4).Info("Closed connection", "reason", err.Error())
+			}
+			return
+		}
+		var length = int(binary.BigEndian.Uint16(header[4:]))
+		if length < 2 || length > tcpMaxLength {
+			log.Error(errors.Errorf("invalid length %d", length), "Error reading MBAP header")
+			return
+		}
+		var pdu = make([]byte, length-1)
+		if _, err := io.ReadFull(conn, pdu); err != nil {
+			log.Error(err, "Error reading PDU")
+			return
+		}
+
+		var resp = s.Handle(header[6], pdu)
+		if resp == nil {
+			// no response as the slave doesn't exist, the client will wait until timeout.
+			continue
+		}
+		var adu = make([]byte, tcpHeaderSize, tcpHeaderSize+len(resp))
+		copy(adu, header[:4])
+		binary.BigEndian.PutUint16(adu[4:], uint16(len(resp)+1))
+		adu[6] = header[6]
+		adu = append(adu, resp...)
+		if _, err := conn.Write(adu); err != nil {
+			log.Error(err, "Error writing response")
+			return
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package simulator
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/octopus/adaptors/modbus/api/v1alpha1"
+)
+
+// Config defines the register map of the simulator.
+type Config struct {
+	// Specifies the interval of updating the scripted values.
+	// The default value is "1s".
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Specifies the slaves to simulate.
+	Slaves []Slave `json:"slaves"`
+}
+
+// GetInterval returns the interval of updating the scripted values.
+func (in *Config) GetInterval() time.Duration {
+	if in != nil && in.Interval != nil {
+		if duration := in.Interval.Duration; duration > 0 {
+			return duration
+		}
+	}
+	return time.Second
+}
+
+// Slave defines the registers of a simulated slave.
+type Slave struct {
+	// Specifies the ID of slave, the slave responds to any ID if it is 0.
+	ID uint8 `json:"id,omitempty"`
+
+	// Specifies the registers of slave,
+	// the undefined registers are still accessible and hold 0 at the beginning.
+	Registers []Register `json:"registers,omitempty"`
+}
+
+// Register defines a value held by a range of registers.
+type Register struct {
+	// Specifies the name of register.
+	Name string `json:"name"`
+
+	// Specifies the register type.
+	Register v1alpha1.ModbusDeviceRegisterType `json:"register"`
+
+	// Specifies the starting offset of register.
+	Offset uint16 `json:"offset"`
+
+	// Specifies the type of value, which is the same as the property type of ModbusDevice.
+	// The default value is "boolean" for 1-bit registers and "uint16" for 16-bits registers.
+	Type v1alpha1.ModbusDevicePropertyType `json:"type,omitempty"`
+
+	// Specifies the endianness of value.
+	// The default value is "BigEndian".
+	Endianness v1alpha1.ModbusDevicePropertyValueEndianness `json:"endianness,omitempty"`
+
+	// Specifies the initial value.
+	Value string `json:"value,omitempty"`
+
+	// Specifies the script of changing the value, the value is constant if it is not specified.
+	Script *Script `json:"script,omitempty"`
+}
+
+// Script defines how to change the value over time, only one of them can be specified.
+type Script struct {
+	// Specifies to change the value linearly.
+	Ramp *RampScript `json:"ramp,omitempty"`
+
+	// Specifies to change the value as a sine wave.
+	Sine *SineScript `json:"sine,omitempty"`
+
+	// Specifies to change the value randomly.
+	Random *RandomScript `json:"random,omitempty"`
+
+	// Specifies to replay the values from a CSV file.
+	CSV *CSVScript `json:"csv,omitempty"`
+}
+
+// RampScript increases the value by step in each update,
+// and restarts from the beginning once the value exceeds the end.
+type RampScript struct {
+	From float64 `json:"from"`
+	To   float64 `json:"to"`
+	// The default value is 1.
+	Step float64 `json:"step,omitempty"`
+}
+
+// SineScript changes the value as "offset + amplitude * sin(2π * t / period)".
+type SineScript struct {
+	Offset    float64 `json:"offset,omitempty"`
+	Amplitude float64 `json:"amplitude"`
+	// The default value is "1m".
+	Period *metav1.Duration `json:"period,omitempty"`
+}
+
+// RandomScript changes the value to a random number in [min, max).
+type RandomScript struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// CSVScript replays the values of a column in CSV file, one row in each update.
+type CSVScript struct {
+	// Specifies the path of CSV file.
+	File string `json:"file"`
+
+	// Specifies the index of column, starting from 0.
+	Column int `json:"column,omitempty"`
+
+	// Specifies to skip the first row.
+	Header bool `json:"header,omitempty"`
+
+	// Specifies to hold the last value instead of replaying from the beginning.
+	NoLoop bool `json:"noLoop,omitempty"`
+}
@@ -0,0 +1,193 @@
+package physical
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/rancher/octopus/adaptors/modbus/api/v1alpha1"
+	"github.com/rancher/octopus/adaptors/modbus/pkg/physical"
+	"github.com/rancher/octopus/pkg/util/log/zap"
+)
+
+// statusRecorder records the latest status synced to limb.
+type statusRecorder struct {
+	sync.Mutex
+
+	status v1alpha1.ModbusDeviceStatus
+}
+
+func (r *statusRecorder) toLimb(in *v1alpha1.ModbusDevice) error {
+	r.Lock()
+	defer r.Unlock()
+	r.status = *in.Status.DeepCopy()
+	return nil
+}
+
+func (r *statusRecorder) value(name string) string {
+	r.Lock()
+	defer r.Unlock()
+	for _, prop := range r.status.Properties {
+		if prop.Name == name {
+			return prop.Value
+		}
+	}
+	return ""
+}
+
+var _ = Describe("verify physical device", func() {
+	var (
+		testNamespace = "default"
+
+		err error
+		log logr.Logger
+	)
+
+	JustBeforeEach(func() {
+		log = zap.WrapAsLogr(zap.NewDevelopmentLogger())
+	})
+
+	newTestInstance := func() *v1alpha1.ModbusDevice {
+		var timestamp = time.Now().UnixNano()
+		return &v1alpha1.ModbusDevice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNamespace,
+				Name:      fmt.Sprintf("m-%d", timestamp),
+				UID:       types.UID(fmt.Sprintf("uid-%d", timestamp)),
+			},
+		}
+	}
+
+	Context("on Modbus TCP", func() {
+
+		It("should read and write the properties", func() {
+			var recorder = &statusRecorder{}
+			var testInstance = newTestInstance()
+			var testDevice = physical.NewDevice(log, testInstance.ObjectMeta, recorder.toLimb)
+			defer testDevice.Shutdown()
+
+			testInstance.Spec = v1alpha1.ModbusDeviceSpec{
+				Parameters: &v1alpha1.ModbusDeviceParameters{
+					SyncInterval: metav1.Duration{Duration: time.Second},
+					Timeout:      metav1.Duration{Duration: time.Second},
+				},
+				Protocol: v1alpha1.ModbusDeviceProtocol{
+					TCP: &v1alpha1.ModbusDeviceProtocolTCP{
+						Endpoint: testSimulatorAddress,
+						WorkerID: 1,
+					},
+				},
+				Properties: []v1alpha1.ModbusDeviceProperty{
+					{
+						Name: "temperature",
+						Type: v1alpha1.ModbusDevicePropertyTypeFloat,
+						Visitor: v1alpha1.ModbusDevicePropertyVisitor{
+							Register:   v1alpha1.ModbusDeviceHoldingRegister,
+							Offset:     0,
+							Quantity:   2,
+							Endianness: v1alpha1.ModbusDevicePropertyValueEndiannessBigEndian,
+						},
+					},
+					{
+						Name: "counter",
+						Type: v1alpha1.ModbusDevicePropertyTypeUint16,
+						Visitor: v1alpha1.ModbusDevicePropertyVisitor{
+							Register:   v1alpha1.ModbusDeviceInputRegister,
+							Offset:     0,
+							Quantity:   1,
+							Endianness: v1alpha1.ModbusDevicePropertyValueEndiannessBigEndian,
+						},
+						ReadOnly: true,
+					},
+				},
+			}
+			err = testDevice.Configure(nil, testInstance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.value("temperature")).To(Equal("20.5"))
+
+			// the scripted value is changing
+			var counter = recorder.value("counter")
+			Eventually(func() string {
+				return recorder.value("counter")
+			}, 5*time.Second, 200*time.Millisecond).ShouldNot(Equal(counter))
+
+			// writes the value and reads back
+			_, err = testDevice.Act("temperature", []byte(`{"value":"30.25"}`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.value("temperature")).To(Equal("30.25"))
+		})
+
+	})
+
+	Context("on Modbus RTU", func() {
+
+		BeforeEach(func() {
+			if testSimulatorPTY == nil {
+				Skip("pseudo terminal is not available")
+			}
+		})
+
+		It("should read the properties of the slaves on the same serial port", func() {
+			var testDevices []physical.Device
+			var recorders []*statusRecorder
+			defer func() {
+				for _, testDevice := range testDevices {
+					testDevice.Shutdown()
+				}
+			}()
+
+			for _, workerID := range []int{1, 2} {
+				var recorder = &statusRecorder{}
+				var testInstance = newTestInstance()
+				var testDevice = physical.NewDevice(log, testInstance.ObjectMeta, recorder.toLimb)
+				testDevices = append(testDevices, testDevice)
+				recorders = append(recorders, recorder)
+
+				testInstance.Spec = v1alpha1.ModbusDeviceSpec{
+					Parameters: &v1alpha1.ModbusDeviceParameters{
+						SyncInterval: metav1.Duration{Duration: time.Second},
+						Timeout:      metav1.Duration{Duration: time.Second},
+					},
+					Protocol: v1alpha1.ModbusDeviceProtocol{
+						RTU: &v1alpha1.ModbusDeviceProtocolRTU{
+							Endpoint: testSimulatorPTY.Name(),
+							WorkerID: workerID,
+							BaudRate: 19200,
+							DataBits: 8,
+							Parity:   "E",
+							StopBits: 1,
+						},
+					},
+					Properties: []v1alpha1.ModbusDeviceProperty{
+						{
+							Name: "humidity-" + strconv.Itoa(workerID),
+							Type: v1alpha1.ModbusDevicePropertyTypeUint16,
+							Visitor: v1alpha1.ModbusDevicePropertyVisitor{
+								Register:   v1alpha1.ModbusDeviceHoldingRegister,
+								Offset:     1,
+								Quantity:   1,
+								Endianness: v1alpha1.ModbusDevicePropertyValueEndiannessBigEndian,
+							},
+							ReadOnly: true,
+						},
+					},
+				}
+				err = testDevice.Configure(nil, testInstance)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			// the slave 1 doesn't define the register, which holds 0
+			Expect(recorders[0].value("humidity-1")).To(Equal("0"))
+			Expect(recorders[1].value("humidity-2")).To(Equal("186"))
+		})
+
+	})
+
+})
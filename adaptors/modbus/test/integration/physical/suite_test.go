@@ -0,0 +1,125 @@
+package physical
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/octopus/adaptors/modbus/api/v1alpha1"
+	"github.com/rancher/octopus/adaptors/modbus/pkg/simulator"
+	"github.com/rancher/octopus/pkg/util/log/zap"
+	"github.com/rancher/octopus/test/framework/envtest/printer"
+)
+
+var (
+	testCtx       context.Context
+	testCtxCancel context.CancelFunc
+
+	testSimulator        *simulator.Simulator
+	testSimulatorPTY     *simulator.PTY
+	testSimulatorAddress string
+)
+
+func TestPhysical(t *testing.T) {
+	defer GinkgoRecover()
+
+	RegisterFailHandler(Fail)
+
+	RunSpecsWithDefaultAndCustomReporters(t,
+		"physical suite",
+		[]Reporter{printer.NewlineReporter{}})
+}
+
+var _ = BeforeSuite(func(done Done) {
+	defer close(done)
+
+	testCtx, testCtxCancel = context.WithCancel(context.Background())
+
+	By("starting test Modbus simulator")
+	Expect(startSimulator()).Should(Succeed())
+}, 600)
+
+var _ = AfterSuite(func(done Done) {
+	defer close(done)
+
+	By("tearing down test Modbus simulator")
+	if testSimulatorPTY != nil {
+		_ = testSimulatorPTY.Close()
+	}
+
+	if testCtxCancel != nil {
+		testCtxCancel()
+	}
+}, 600)
+
+func startSimulator() error {
+	var err error
+	testSimulator, err = simulator.NewSimulator(zap.WrapAsLogr(zap.NewDevelopmentLogger()), simulator.Config{
+		Interval: &metav1.Duration{Duration: 500 * time.Millisecond},
+		Slaves: []simulator.Slave{
+			{
+				ID: 1,
+				Registers: []simulator.Register{
+					{
+						Name:     "temperature",
+						Register: v1alpha1.ModbusDeviceHoldingRegister,
+						Offset:   0,
+						Type:     v1alpha1.ModbusDevicePropertyTypeFloat,
+						Value:    "20.5",
+					},
+					{
+						Name:     "counter",
+						Register: v1alpha1.ModbusDeviceInputRegister,
+						Offset:   0,
+						Type:     v1alpha1.ModbusDevicePropertyTypeUint16,
+						Script: &simulator.Script{
+							Ramp: &simulator.RampScript{From: 0, To: 1000},
+						},
+					},
+				},
+			},
+			{
+				ID: 2,
+				Registers: []simulator.Register{
+					{
+						Name:     "humidity",
+						Register: v1alpha1.ModbusDeviceHoldingRegister,
+						Offset:   1,
+						Type:     v1alpha1.ModbusDevicePropertyTypeUint16,
+						Value:    "186",
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	go testSimulator.Run(testCtx.Done())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	testSimulatorAddress = listener.Addr().String()
+	go func() {
+		_ = testSimulator.ServeTCP(listener, testCtx.Done())
+	}()
+
+	// the RTU cases are skipped if the pseudo terminal is not available.
+	testSimulatorPTY, err = simulator.OpenPTY()
+	if err != nil {
+		GinkgoT().Logf("failed to open pseudo terminal, %v", err)
+		testSimulatorPTY = nil
+		return nil
+	}
+	go func() {
+		_ = testSimulator.ServeRTU(testSimulatorPTY)
+	}()
+	return nil
+}
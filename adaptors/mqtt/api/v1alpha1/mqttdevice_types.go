@@ -1,8 +1,11 @@
 package v1alpha1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
 	mqttapi "github.com/rancher/octopus/pkg/mqtt/api"
 )
 
@@ -140,6 +143,56 @@ type MQTTDeviceStatusProperty struct {
 	UpdatedAt *metav1.Time `json:"updateAt,omitempty"`
 }
 
+// MQTTDeviceDiscoveryTemplate defines the DeviceLink which is created for the discovered device.
+type MQTTDeviceDiscoveryTemplate struct {
+	// Specifies the name of the DeviceLink, it's a Go template rendered with the identities,
+	// e.g. "sensor-{{ .site }}-{{ .sensor }}".
+	// The default value is the name of the discovering DeviceLink joined with all identities.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Specifies the labels of the DeviceLink.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Specifies the spec of the DeviceLink, the string values are Go templates rendered with the identities,
+	// e.g. "site/{{ .site }}/sensor/{{ .sensor }}/telemetry".
+	// The adaptor node(selector) of the discovering DeviceLink is used if both of them are blank.
+	// +kubebuilder:validation:Required
+	Spec edgev1alpha1.DeviceLinkSpec `json:"spec"`
+}
+
+// MQTTDeviceDiscovery defines the discovery mode of MQTTDevice,
+// which subscribes the wildcard topic and creates a DeviceLink for each newly seen device.
+type MQTTDeviceDiscovery struct {
+	// Specifies the names of the identities in order,
+	// which are extracted from the single-level wildcard(+) segments of the subscribed topic,
+	// e.g. ["site", "sensor"] for "site/+/sensor/+/telemetry".
+	// +kubebuilder:validation:MinItems=1
+	Identities []string `json:"identities"`
+
+	// Specifies the template of the DeviceLink created for the discovered device.
+	// +kubebuilder:validation:Required
+	Template MQTTDeviceDiscoveryTemplate `json:"template"`
+
+	// Specifies the inactivity TTL of the discovered device,
+	// the created DeviceLink is deleted if no message is received within the TTL.
+	// The default value is "1h".
+	// +kubebuilder:default="1h"
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// GetTTL returns the inactivity TTL of the discovered device.
+func (in *MQTTDeviceDiscovery) GetTTL() time.Duration {
+	if in != nil && in.TTL != nil {
+		if duration := in.TTL.Duration; duration > 0 {
+			return duration
+		}
+	}
+	return time.Hour
+}
+
 // MQTTDeviceSpec defines the desired state of MQTTDevice.
 type MQTTDeviceSpec struct {
 	// Specifies the protocol for accessing the MQTT service.
@@ -151,6 +204,25 @@ type MQTTDeviceSpec struct {
 	// +listMapKey=name
 	// +optional
 	Properties []MQTTDeviceProperty `json:"properties,omitempty"`
+
+	// Specifies the discovery mode of MQTTDevice, the properties are not allowed in this mode.
+	// +optional
+	Discovery *MQTTDeviceDiscovery `json:"discovery,omitempty"`
+}
+
+// MQTTDeviceDiscoveredStatus defines the observed device in discovery mode.
+type MQTTDeviceDiscoveredStatus struct {
+	// Reports the name of the created DeviceLink.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Reports the identities of the device.
+	// +optional
+	Identities map[string]string `json:"identities,omitempty"`
+
+	// Reports the timestamp of the last received message.
+	// +optional
+	LastSeen *metav1.Time `json:"lastSeen,omitempty"`
 }
 
 // MQTTDeviceStatus defines the observed state of MQTTDevice.
@@ -158,6 +230,12 @@ type MQTTDeviceStatus struct {
 	// Reports the properties of MQTTDevice.
 	// +optional
 	Properties []MQTTDeviceStatusProperty `json:"properties,omitempty"`
+
+	// Reports the discovered devices of MQTTDevice.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Discovered []MQTTDeviceDiscoveredStatus `json:"discovered,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -20,6 +20,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -50,6 +51,81 @@ func (in *MQTTDevice) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTDeviceDiscoveredStatus) DeepCopyInto(out *MQTTDeviceDiscoveredStatus) {
+	*out = *in
+	if in.Identities != nil {
+		in, out := &in.Identities, &out.Identities
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastSeen != nil {
+		in, out := &in.LastSeen, &out.LastSeen
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTDeviceDiscoveredStatus.
+func (in *MQTTDeviceDiscoveredStatus) DeepCopy() *MQTTDeviceDiscoveredStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTDeviceDiscoveredStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTDeviceDiscovery) DeepCopyInto(out *MQTTDeviceDiscovery) {
+	*out = *in
+	if in.Identities != nil {
+		in, out := &in.Identities, &out.Identities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTDeviceDiscovery.
+func (in *MQTTDeviceDiscovery) DeepCopy() *MQTTDeviceDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTDeviceDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTDeviceDiscoveryTemplate) DeepCopyInto(out *MQTTDeviceDiscoveryTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTDeviceDiscoveryTemplate.
+func (in *MQTTDeviceDiscoveryTemplate) DeepCopy() *MQTTDeviceDiscoveryTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTDeviceDiscoveryTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MQTTDeviceList) DeepCopyInto(out *MQTTDeviceList) {
 	*out = *in
@@ -183,6 +259,11 @@ func (in *MQTTDeviceSpec) DeepCopyInto(out *MQTTDeviceSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Discovery != nil {
+		in, out := &in.Discovery, &out.Discovery
+		*out = new(MQTTDeviceDiscovery)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTDeviceSpec.
@@ -205,6 +286,13 @@ func (in *MQTTDeviceStatus) DeepCopyInto(out *MQTTDeviceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Discovered != nil {
+		in, out := &in.Discovered, &out.Discovered
+		*out = make([]MQTTDeviceDiscoveredStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTDeviceStatus.
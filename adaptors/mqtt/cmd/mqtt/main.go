@@ -7,6 +7,7 @@
 
 	"github.com/rancher/octopus/adaptors/mqtt/pkg/mqtt"
 	"github.com/rancher/octopus/pkg/adaptor/log"
+	"github.com/rancher/octopus/pkg/adaptor/metrics/metricsflag"
 	_ "github.com/rancher/octopus/pkg/util/log/handler"
 	"github.com/rancher/octopus/pkg/util/log/logflag"
 	"github.com/rancher/octopus/pkg/util/version/verflag"
@@ -31,6 +32,7 @@ func newCommand() *cobra.Command {
 
 	verflag.AddFlags(c.Flags())
 	logflag.AddFlags(c.Flags())
+	metricsflag.AddFlags(c.Flags())
 	return c
 }
 
@@ -1,6 +1,9 @@
 package adaptor
 
 import (
+	"fmt"
+	"sync"
+
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -45,6 +48,8 @@ func (s *Service) toJSON(in metav1.Object) []byte {
 
 func (s *Service) Connect(server api.Connection_ConnectServer) error {
 	var holder physical.Device
+	// it is not safe to call Send on the same stream in different goroutines.
+	var sendLock sync.Mutex
 	defer func() {
 		if holder != nil {
 			holder.Shutdown()
@@ -71,6 +76,22 @@ func (s *Service) Connect(server api.Connection_ConnectServer) error {
 			return status.Errorf(codes.InvalidArgument, "invalid model group: %s", modelGVK.Group)
 		}
 
+		// processes action
+		if action := req.GetAction(); action != nil {
+			var resp = &api.ConnectResponseAction{
+				Id:           action.GetId(),
+				ErrorMessage: fmt.Sprintf("unsupported action %s", action.GetName()),
+			}
+
+			sendLock.Lock()
+			err := server.Send(&api.ConnectResponse{Action: resp})
+			sendLock.Unlock()
+			if err != nil {
+				return status.Errorf(codes.Unknown, "failed to send action result to limb, %v", err)
+			}
+			continue
+		}
+
 		// processes device
 		switch modelGVK.Kind {
 		case "MQTTDevice":
@@ -108,6 +129,8 @@ func (s *Service) Connect(server api.Connection_ConnectServer) error {
 						resp = &api.ConnectResponse{Device: deviceBytes}
 					}
 					// send device to limb
+					sendLock.Lock()
+					defer sendLock.Unlock()
 					if err := server.Send(resp); err != nil {
 						return status.Errorf(codes.Unknown, "failed to send device to limb, %v", err)
 					}
@@ -2,19 +2,24 @@
 
 import (
 	"golang.org/x/sync/errgroup"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/rancher/octopus/adaptors/mqtt/api/v1alpha1"
 	"github.com/rancher/octopus/adaptors/mqtt/pkg/adaptor"
 	"github.com/rancher/octopus/adaptors/mqtt/pkg/metadata"
 	api "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
 	"github.com/rancher/octopus/pkg/adaptor/connection"
 	"github.com/rancher/octopus/pkg/adaptor/log"
+	"github.com/rancher/octopus/pkg/adaptor/metrics"
+	"github.com/rancher/octopus/pkg/adaptor/metrics/metricsflag"
 	"github.com/rancher/octopus/pkg/adaptor/registration"
 	"github.com/rancher/octopus/pkg/util/critical"
 )
 
 // +kubebuilder:rbac:groups=devices.edge.cattle.io,resources=mqttdevices,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=devices.edge.cattle.io,resources=mqttdevices/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=edge.cattle.io,resources=devicelinks,verbs=get;list;create;delete
 
 func Run() error {
 	log.Info("Starting")
@@ -27,12 +32,19 @@ func Run() error {
 		// start adaptor to receive requests from Limb
 		return connection.Serve(metadata.Endpoint, adaptor.NewService(), stop)
 	})
+	eg.Go(func() error {
+		// serve the metrics of adaptor
+		return metrics.Serve(metricsflag.GetAddress(), stop)
+	})
 	eg.Go(func() error {
 		// register adaptor to Limb
 		return registration.Register(ctx, api.RegisterRequest{
 			Name:     metadata.Name,
 			Version:  metadata.Version,
 			Endpoint: metadata.Endpoint,
+			Models: []*metav1.TypeMeta{
+				{APIVersion: v1alpha1.GroupVersion.String(), Kind: "MQTTDevice"},
+			},
 		})
 	})
 	return eg.Wait()
@@ -1,6 +1,7 @@
 package physical
 
 import (
+	"encoding/json"
 	"io"
 	"reflect"
 	"sync"
@@ -10,6 +11,7 @@
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
 
@@ -48,12 +50,17 @@ type mqttDevice struct {
 	instance   *v1alpha1.MQTTDevice
 	toLimb     MQTTDeviceLimbSyncer
 	mqttClient mqtt.Client
+	discoverer *discoverer
 }
 
 func (d *mqttDevice) Shutdown() {
 	d.Lock()
 	defer d.Unlock()
 
+	if d.discoverer != nil {
+		d.discoverer.close()
+		d.discoverer = nil
+	}
 	if d.mqttClient != nil {
 		d.mqttClient.Disconnect()
 		d.mqttClient = nil
@@ -72,16 +79,35 @@ func (d *mqttDevice) Configure(references api.ReferencesHandler, configuration i
 	}
 	var newSpec = device.Spec
 
+	if newSpec.Discovery != nil && len(newSpec.Properties) != 0 {
+		return errors.New("properties are not supported in discovery mode, please specify them in the discovery template")
+	}
+
 	d.Lock()
 	defer d.Unlock()
 
-	if !reflect.DeepEqual(d.instance.Spec.Protocol, newSpec.Protocol) {
+	if !reflect.DeepEqual(d.instance.Spec.Protocol, newSpec.Protocol) || !reflect.DeepEqual(d.instance.Spec.Discovery, newSpec.Discovery) {
+		if d.discoverer != nil {
+			d.discoverer.close()
+			d.discoverer = nil
+		}
 		if d.mqttClient != nil {
 			d.mqttClient.Disconnect()
 			d.mqttClient = nil
 			d.log.V(1).Info("Disconnected stale connection")
 		}
 
+		// the MQTT device is subscribed via the message settings, which are ignored in Sparkplug B mode.
+		if newSpec.Protocol.SparkplugB != nil {
+			return errors.New("Sparkplug B mode is not supported")
+		}
+		if newSpec.Protocol.Message.Command != nil {
+			return errors.New("command topic is not supported, please write the properties via the device spec")
+		}
+		if newSpec.Protocol.Message.Batch != nil {
+			return errors.New("batch publishing is not supported, the properties must be written one by one")
+		}
+
 		var clientBuilder = mqtt.NewClientBuilder(newSpec.Protocol.MQTTOptions, object.GetControlledOwnerObjectReference(device))
 		clientBuilder.Render(references)
 		clientBuilder.ConfigureOptions(func(options *MQTT.ClientOptions) error {
@@ -118,11 +144,59 @@ func (d *mqttDevice) Configure(references api.ReferencesHandler, configuration i
 		}
 		d.mqttClient = cli
 		d.log.V(1).Info("Connected to MQTT broker")
+
+		if newSpec.Discovery != nil {
+			return d.discover(object.GetControlledOwnerObjectReference(device), newSpec)
+		}
 	}
 
+	if newSpec.Discovery != nil {
+		d.instance.Spec = newSpec
+		return nil
+	}
 	return d.refresh(newSpec)
 }
 
+// discover subscribes the wildcard topic and creates a DeviceLink for each newly seen device,
+// the received payloads are not recorded in discovery mode.
+func (d *mqttDevice) discover(owner corev1.ObjectReference, newSpec v1alpha1.MQTTDeviceSpec) error {
+	var dis *discoverer
+	var onChange = func(discovered []v1alpha1.MQTTDeviceDiscoveredStatus) {
+		d.Lock()
+		defer d.Unlock()
+
+		// ignores the stale discoverer.
+		if d.discoverer != dis {
+			return
+		}
+		d.instance.Status.Discovered = discovered
+		if err := d.sync(); err != nil {
+			d.log.Error(err, "failed to sync")
+		}
+	}
+	var err error
+	dis, err = newDiscoverer(d.log, owner, newSpec.Protocol.Message.Topic, newSpec.Discovery, onChange)
+	if err != nil {
+		return errors.Wrap(err, "failed to create discoverer")
+	}
+
+	var subscribeTopics = []mqtt.SubscribeTopic{{}}
+	var subscribeHandler = func(msg mqtt.SubscribeMessage) {
+		dis.enqueue(msg.Topic)
+	}
+	if err := d.mqttClient.Subscribe(subscribeTopics, subscribeHandler); err != nil {
+		return errors.Wrap(err, "failed to subscribe")
+	}
+
+	d.discoverer = dis
+	dis.start()
+
+	// records
+	d.instance.Spec = newSpec
+	d.instance.Status = v1alpha1.MQTTDeviceStatus{}
+	return d.sync()
+}
+
 // refresh refreshes the status with new spec.
 func (d *mqttDevice) refresh(newSpec v1alpha1.MQTTDeviceSpec) error {
 	// indexes stale status properties
@@ -198,7 +272,11 @@ func (d *mqttDevice) refreshAsAttributedMessage(staleSpecPropsIndex map[string]v
 			d.Lock()
 			defer d.Unlock()
 
-			var payload = msg.Payload
+			var payload, err = msg.JSONPayload()
+			if err != nil {
+				d.log.Error(err, "failed to decode payload", "type", "AttributedMessage")
+				return
+			}
 			for idx, prop := range d.instance.Status.Properties {
 				var propValue = &v1alpha1.MQTTDevicePropertyValue{}
 				var result = gjson.GetBytes(payload, getPath(prop.Name, prop.Path))
@@ -246,7 +324,8 @@ func (d *mqttDevice) refreshAsAttributedMessage(staleSpecPropsIndex map[string]v
 		}
 	}
 	if len(payload) != 0 && !reflect.DeepEqual(stalePayload, payload) {
-		if err := d.mqttClient.Publish(mqtt.PublishMessage{Payload: payload}); err != nil {
+		// wraps the JSON payload as raw message, so that the codec can encode it.
+		if err := d.mqttClient.Publish(mqtt.PublishMessage{Payload: json.RawMessage(payload)}); err != nil {
 			return errors.Wrap(err, "failed to publish")
 		}
 		d.log.V(4).Info("Sent payload", "type", "AttributedMessage")
@@ -285,8 +364,14 @@ func (d *mqttDevice) refreshAsAttributedTopic(staleSpecPropsIndex map[string]v1a
 				return
 			}
 
+			var payload, err = msg.JSONPayload()
+			if err != nil {
+				d.log.Error(err, "failed to decode payload", "type", "AttributedTopic")
+				return
+			}
+
 			var prop = &d.instance.Status.Properties[msg.Index]
-			prop.Value = &v1alpha1.MQTTDevicePropertyValue{Raw: msg.Payload}
+			prop.Value = &v1alpha1.MQTTDevicePropertyValue{Raw: payload}
 			prop.UpdatedAt = now()
 			d.log.V(4).Info("Received payload", "type", "AttributedTopic", "property", prop.Name)
 			// TODO should we debounce here?
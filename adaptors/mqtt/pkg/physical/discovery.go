@@ -0,0 +1,498 @@
+package physical
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/rancher/octopus/adaptors/mqtt/api/v1alpha1"
+	"github.com/rancher/octopus/adaptors/mqtt/pkg/metadata"
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/adaptor/socket/handler"
+)
+
+const (
+	// DiscoveredByLabel is the label of the discovered DeviceLink, the value is the name of discovering DeviceLink.
+	DiscoveredByLabel = "edge.cattle.io/discovered-by"
+	// DiscoveredIdentitiesAnnotation is the annotation of the discovered DeviceLink, the value is the identities in JSON.
+	DiscoveredIdentitiesAnnotation = "edge.cattle.io/discovered-identities"
+)
+
+// discoveryQueueSize is the max number of the received topics waiting to be observed.
+const discoveryQueueSize = 1024
+
+var (
+	kubeClient     client.Client
+	kubeClientErr  error
+	kubeClientOnce sync.Once
+)
+
+// getKubeClient returns the client to create/delete the discovered DeviceLinks.
+var getKubeClient = func() (client.Client, error) {
+	kubeClientOnce.Do(func() {
+		var scheme = k8sruntime.NewScheme()
+		utilruntime.Must(edgev1alpha1.AddToScheme(scheme))
+
+		var cfg, err = ctrl.GetConfig()
+		if err != nil {
+			kubeClientErr = errors.Wrap(err, "failed to get kubernetes config")
+			return
+		}
+		kubeClient, kubeClientErr = client.New(cfg, client.Options{Scheme: scheme})
+	})
+	return kubeClient, kubeClientErr
+}
+
+// discovered is the device discovered from the topic.
+type discovered struct {
+	identities map[string]string
+	lastSeen   time.Time
+}
+
+// discoverer creates a DeviceLink for each newly seen device of the wildcard topic,
+// and deletes the DeviceLink if the device is inactive over the TTL.
+type discoverer struct {
+	sync.Mutex
+
+	log logr.Logger
+	// owner is the discovering DeviceLink.
+	owner      corev1.ObjectReference
+	filter     []string
+	identities []string
+	template   v1alpha1.MQTTDeviceDiscoveryTemplate
+	ttl        time.Duration
+	onChange   func(discovered []v1alpha1.MQTTDeviceDiscoveredStatus)
+
+	kubeClient client.Client
+	devices    map[string]*discovered
+	// failures records the failed time of creating, avoids creating the same DeviceLink frequently.
+	failures map[string]time.Time
+	// queue buffers the received topics to be observed by the worker.
+	queue chan string
+	stop  chan struct{}
+}
+
+// enqueue queues the topic to be observed, the topic is dropped if the queue is full,
+// the device of it can be observed with the next received message.
+func (d *discoverer) enqueue(topicName string) {
+	select {
+	case d.queue <- topicName:
+	default:
+		d.log.V(1).Info("Dropped the received topic as the observing queue is full", "topic", topicName)
+	}
+}
+
+// observe records the device of the given topic, and creates the DeviceLink if the device is newly seen.
+func (d *discoverer) observe(topicName string) {
+	var identities, ok = extractIdentities(d.filter, d.identities, topicName)
+	if !ok {
+		return
+	}
+	var name, err = d.renderName(identities)
+	if err != nil {
+		d.log.Error(err, "Failed to render the name of discovered DeviceLink", "topic", topicName)
+		return
+	}
+	var now = time.Now()
+
+	d.Lock()
+	if device, exist := d.devices[name]; exist {
+		device.lastSeen = now
+		d.Unlock()
+		return
+	}
+	if failedAt, exist := d.failures[name]; exist && now.Sub(failedAt) < time.Minute {
+		d.Unlock()
+		return
+	}
+	d.Unlock()
+
+	// creates without holding the lock, the observing is serial in the worker,
+	// so the same DeviceLink is not created concurrently.
+	if err := d.create(name, identities); err != nil {
+		d.Lock()
+		d.failures[name] = now
+		d.Unlock()
+		d.log.Error(err, "Failed to create discovered DeviceLink", "name", name)
+		return
+	}
+
+	d.Lock()
+	delete(d.failures, name)
+	d.devices[name] = &discovered{identities: identities, lastSeen: now}
+	var status = d.status()
+	d.Unlock()
+
+	d.log.V(1).Info("Discovered device", "name", name, "identities", identities)
+	d.onChange(status)
+}
+
+// create creates the DeviceLink with the given name and identities, it's fine if the DeviceLink has been created by the owner.
+func (d *discoverer) create(name string, identities map[string]string) error {
+	var spec, err = renderSpec(d.template.Spec, identities)
+	if err != nil {
+		return err
+	}
+	if spec.Adaptor.Node == "" && len(spec.Adaptor.NodeSelector) == 0 {
+		var owner edgev1alpha1.DeviceLink
+		if err := d.kubeClient.Get(context.TODO(), client.ObjectKey{Namespace: d.owner.Namespace, Name: d.owner.Name}, &owner); err != nil {
+			return errors.Wrap(err, "failed to get discovering DeviceLink")
+		}
+		spec.Adaptor.Node = owner.Spec.Adaptor.Node
+		spec.Adaptor.NodeSelector = owner.Spec.Adaptor.NodeSelector
+	}
+
+	var identitiesBytes, _ = json.Marshal(identities)
+	var link = &edgev1alpha1.DeviceLink{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: d.owner.Namespace,
+			Name:      name,
+			Labels:    make(map[string]string, len(d.template.Labels)+1),
+			Annotations: map[string]string{
+				DiscoveredIdentitiesAnnotation: string(identitiesBytes),
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: d.owner.APIVersion,
+					Kind:       d.owner.Kind,
+					Name:       d.owner.Name,
+					UID:        d.owner.UID,
+				},
+			},
+		},
+		Spec: *spec,
+	}
+	for k, v := range d.template.Labels {
+		link.Labels[k] = v
+	}
+	link.Labels[DiscoveredByLabel] = d.owner.Name
+
+	err = d.kubeClient.Create(context.TODO(), link)
+	if err == nil || !apierrs.IsAlreadyExists(err) {
+		return err
+	}
+
+	// accepts the existing DeviceLink only if it was discovered by the owner,
+	// so that a DeviceLink with the same name is never adopted and then deleted.
+	var existing edgev1alpha1.DeviceLink
+	if err := d.kubeClient.Get(context.TODO(), client.ObjectKey{Namespace: d.owner.Namespace, Name: name}, &existing); err != nil {
+		return errors.Wrap(err, "failed to get existing DeviceLink")
+	}
+	if !d.owns(&existing) {
+		return errors.Errorf("DeviceLink %s exists but isn't discovered by %s", name, d.owner.Name)
+	}
+	return nil
+}
+
+// owns returns true if the given DeviceLink is discovered by the owner.
+func (d *discoverer) owns(link *edgev1alpha1.DeviceLink) bool {
+	if link.Labels[DiscoveredByLabel] != d.owner.Name {
+		return false
+	}
+	for _, ref := range link.OwnerReferences {
+		if ref.UID == d.owner.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// adopt records the DeviceLinks which are created before, they are treated as seen at this moment.
+func (d *discoverer) adopt() error {
+	var links edgev1alpha1.DeviceLinkList
+	var err = d.kubeClient.List(context.TODO(), &links,
+		client.InNamespace(d.owner.Namespace),
+		client.MatchingLabels{DiscoveredByLabel: d.owner.Name},
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to list discovered DeviceLinks")
+	}
+
+	var now = time.Now()
+	d.Lock()
+	for i := range links.Items {
+		var link = &links.Items[i]
+		if !d.owns(link) {
+			continue
+		}
+		var identities map[string]string
+		_ = json.Unmarshal([]byte(link.Annotations[DiscoveredIdentitiesAnnotation]), &identities)
+		d.devices[link.Name] = &discovered{identities: identities, lastSeen: now}
+	}
+	var status = d.status()
+	d.Unlock()
+
+	d.onChange(status)
+	return nil
+}
+
+// collect deletes the DeviceLinks of the devices which are inactive over the TTL.
+func (d *discoverer) collect(now time.Time) {
+	var inactives []string
+	d.Lock()
+	for name, device := range d.devices {
+		if now.Sub(device.lastSeen) >= d.ttl {
+			inactives = append(inactives, name)
+		}
+	}
+	d.Unlock()
+
+	var deleted = make([]string, 0, len(inactives))
+	for _, name := range inactives {
+		if err := d.delete(name); err != nil {
+			d.log.Error(err, "Failed to delete inactive DeviceLink", "name", name)
+			continue
+		}
+		deleted = append(deleted, name)
+		d.log.V(1).Info("Deleted inactive device", "name", name)
+	}
+
+	d.Lock()
+	for _, name := range deleted {
+		delete(d.devices, name)
+	}
+	var status = d.status()
+	d.Unlock()
+
+	// reports the last seen timestamps along with the collection,
+	// instead of syncing on every received message.
+	d.onChange(status)
+}
+
+// delete deletes the DeviceLink with the given name if it is discovered by the owner,
+// it's fine if the DeviceLink has been deleted or isn't discovered by the owner.
+func (d *discoverer) delete(name string) error {
+	var link edgev1alpha1.DeviceLink
+	if err := d.kubeClient.Get(context.TODO(), client.ObjectKey{Namespace: d.owner.Namespace, Name: name}, &link); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to get inactive DeviceLink")
+	}
+	if !d.owns(&link) {
+		d.log.Info("Skipped deleting the DeviceLink which isn't discovered", "name", name)
+		return nil
+	}
+
+	// the UID precondition guarantees that the checked DeviceLink is the deleting one.
+	var uid = link.UID
+	if err := d.kubeClient.Delete(context.TODO(), &link, client.Preconditions{UID: &uid}); err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// start starts observing and collecting in background.
+func (d *discoverer) start() {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.stop == nil {
+		d.stop = make(chan struct{})
+		go d.run(d.stop)
+	}
+}
+
+// run adopts the created DeviceLinks, and then observes the queued topics and collects the inactive devices periodically.
+func (d *discoverer) run(stop <-chan struct{}) {
+	defer utilruntime.HandleCrash(handler.NewPanicsCleanupSocketHandler(metadata.Endpoint))
+
+	if err := d.adopt(); err != nil {
+		d.log.Error(err, "Failed to adopt discovered DeviceLinks")
+	}
+
+	var interval = d.ttl / 2
+	if interval > time.Minute {
+		interval = time.Minute
+	}
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case topicName := <-d.queue:
+			d.observe(topicName)
+		case now := <-ticker.C:
+			d.collect(now)
+		}
+	}
+}
+
+// close stops observing and collecting.
+func (d *discoverer) close() {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+}
+
+// status returns the discovered devices in name order.
+func (d *discoverer) status() []v1alpha1.MQTTDeviceDiscoveredStatus {
+	var ret = make([]v1alpha1.MQTTDeviceDiscoveredStatus, 0, len(d.devices))
+	for name, device := range d.devices {
+		var lastSeen = metav1.NewTime(device.lastSeen)
+		ret = append(ret, v1alpha1.MQTTDeviceDiscoveredStatus{
+			Name:       name,
+			Identities: device.identities,
+			LastSeen:   &lastSeen,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// renderName renders the name of DeviceLink with the identities.
+func (d *discoverer) renderName(identities map[string]string) (string, error) {
+	if d.template.Name == "" {
+		var segments = make([]string, 0, len(d.identities)+1)
+		segments = append(segments, d.owner.Name)
+		for _, identity := range d.identities {
+			segments = append(segments, identities[identity])
+		}
+		return sanitizeName(strings.Join(segments, "-")), nil
+	}
+
+	var t, err = template.New("name").Option("missingkey=error").Parse(d.template.Name)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse name template")
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, identities); err != nil {
+		return "", errors.Wrap(err, "failed to render name template")
+	}
+	var name = sanitizeName(buf.String())
+	if name == "" {
+		return "", errors.New("rendered name is blank")
+	}
+	return name, nil
+}
+
+// newDiscoverer creates the discoverer of the given discovery settings,
+// the topic is the subscribed wildcard topic.
+func newDiscoverer(log logr.Logger, owner corev1.ObjectReference, topic string, discovery *v1alpha1.MQTTDeviceDiscovery, onChange func([]v1alpha1.MQTTDeviceDiscoveredStatus)) (*discoverer, error) {
+	if owner.Name == "" || owner.UID == "" {
+		return nil, errors.New("failed to get the discovering DeviceLink")
+	}
+
+	var filter = strings.Split(topic, "/")
+	var wildcards int
+	for _, segment := range filter {
+		if segment == "+" {
+			wildcards++
+		}
+	}
+	if len(discovery.Identities) == 0 {
+		return nil, errors.New("identities are required")
+	}
+	if len(discovery.Identities) > wildcards {
+		return nil, errors.Errorf("topic %s has %d single-level wildcards, but %d identities are required", topic, wildcards, len(discovery.Identities))
+	}
+
+	var kubeClient, err = getKubeClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create kubernetes client")
+	}
+
+	return &discoverer{
+		log:        log,
+		owner:      owner,
+		filter:     filter,
+		identities: discovery.Identities,
+		template:   discovery.Template,
+		ttl:        discovery.GetTTL(),
+		onChange:   onChange,
+		kubeClient: kubeClient,
+		devices:    make(map[string]*discovered),
+		failures:   make(map[string]time.Time),
+		queue:      make(chan string, discoveryQueueSize),
+	}, nil
+}
+
+// extractIdentities extracts the identities from the single-level wildcard(+) segments of the topic in order.
+func extractIdentities(filter []string, names []string, topicName string) (map[string]string, bool) {
+	var segments = strings.Split(topicName, "/")
+	var identities = make(map[string]string, len(names))
+	var idx int
+	for i, f := range filter {
+		if f == "#" {
+			break
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		if f != "+" {
+			continue
+		}
+		if idx < len(names) {
+			if segments[i] == "" {
+				return nil, false
+			}
+			identities[names[idx]] = segments[i]
+			idx++
+		}
+	}
+	return identities, idx == len(names)
+}
+
+// renderSpec renders the string values of the spec with the identities.
+func renderSpec(spec edgev1alpha1.DeviceLinkSpec, identities map[string]string) (*edgev1alpha1.DeviceLinkSpec, error) {
+	var specBytes, err = json.Marshal(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal spec template")
+	}
+	t, err := template.New("spec").Option("missingkey=error").Parse(string(specBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse spec template")
+	}
+
+	// escapes the identities, so that the rendered spec is still a legal JSON.
+	var escaped = make(map[string]string, len(identities))
+	for k, v := range identities {
+		var vBytes, _ = json.Marshal(v)
+		escaped[k] = string(vBytes[1 : len(vBytes)-1])
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, escaped); err != nil {
+		return nil, errors.Wrap(err, "failed to render spec template")
+	}
+
+	var ret edgev1alpha1.DeviceLinkSpec
+	if err := json.Unmarshal(buf.Bytes(), &ret); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal rendered spec")
+	}
+	return &ret, nil
+}
+
+var illegalNameCharacters = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// sanitizeName converts the given string to a legal DNS-1123 subdomain in 63 characters.
+func sanitizeName(name string) string {
+	name = illegalNameCharacters.ReplaceAllString(strings.ToLower(name), "-")
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return strings.Trim(name, "-.")
+}
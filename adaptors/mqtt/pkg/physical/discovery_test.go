@@ -0,0 +1,161 @@
+package physical
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/rancher/octopus/adaptors/mqtt/api/v1alpha1"
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+)
+
+// listDiscovered returns the DeviceLinks discovered by the given owner.
+func listDiscovered(t *testing.T, kubeCli client.Client, owner string) map[string]edgev1alpha1.DeviceLink {
+	var links edgev1alpha1.DeviceLinkList
+	if err := kubeCli.List(context.TODO(), &links, client.MatchingLabels{DiscoveredByLabel: owner}); err != nil {
+		t.Fatalf("failed to list DeviceLinks: %v", err)
+	}
+	var ret = make(map[string]edgev1alpha1.DeviceLink, len(links.Items))
+	for _, link := range links.Items {
+		ret[link.Name] = link
+	}
+	return ret
+}
+
+func Test_extractIdentities(t *testing.T) {
+	var filter = strings.Split("site/+/sensor/+/telemetry/#", "/")
+
+	var testCases = []struct {
+		name     string
+		given    string
+		expected map[string]string
+	}{
+		{
+			name:     "matched",
+			given:    "site/s1/sensor/t1/telemetry",
+			expected: map[string]string{"site": "s1", "sensor": "t1"},
+		},
+		{
+			name:     "matched with multi-level wildcard",
+			given:    "site/s1/sensor/t1/telemetry/temperature",
+			expected: map[string]string{"site": "s1", "sensor": "t1"},
+		},
+		{
+			name:     "blank identity",
+			given:    "site//sensor/t1/telemetry",
+			expected: nil,
+		},
+		{
+			name:     "short topic",
+			given:    "site/s1/sensor",
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual, _ = extractIdentities(filter, []string{"site", "sensor"}, tc.given)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
+func Test_discoverer(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	_ = edgev1alpha1.AddToScheme(scheme)
+	var kubeCli = fake.NewFakeClientWithScheme(scheme,
+		&edgev1alpha1.DeviceLink{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "discovery", UID: "fake-uid"},
+			Spec: edgev1alpha1.DeviceLinkSpec{
+				Adaptor: edgev1alpha1.DeviceAdaptor{Node: "edge-worker"},
+			},
+		},
+		// the DeviceLink created by the user has the same name as the discovered one
+		&edgev1alpha1.DeviceLink{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sensor-t9", Labels: map[string]string{"device": "sensor"}},
+		},
+	)
+	getKubeClient = func() (client.Client, error) {
+		return kubeCli, nil
+	}
+
+	var discovery = &v1alpha1.MQTTDeviceDiscovery{
+		Identities: []string{"site", "sensor"},
+		Template: v1alpha1.MQTTDeviceDiscoveryTemplate{
+			Labels: map[string]string{"device": "sensor"},
+			Spec: edgev1alpha1.DeviceLinkSpec{
+				Adaptor: edgev1alpha1.DeviceAdaptor{Name: "adaptors.edge.cattle.io/mqtt"},
+				Template: edgev1alpha1.DeviceTemplateSpec{
+					Spec: &runtime.RawExtension{Raw: []byte(`{"protocol":{"message":{"topic":"site/{{ .site }}/sensor/{{ .sensor }}/telemetry"}}}`)},
+				},
+			},
+		},
+		TTL: &metav1.Duration{Duration: time.Minute},
+	}
+	var owner = corev1.ObjectReference{
+		Namespace:  "default",
+		APIVersion: "edge.cattle.io/v1alpha1",
+		Kind:       "DeviceLink",
+		Name:       "discovery",
+		UID:        "fake-uid",
+	}
+	var changes [][]v1alpha1.MQTTDeviceDiscoveredStatus
+	var dis, err = newDiscoverer(logf.Log, owner, "site/+/sensor/+/telemetry", discovery, func(discovered []v1alpha1.MQTTDeviceDiscoveredStatus) {
+		changes = append(changes, discovered)
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// creates the DeviceLink for the newly seen device
+	dis.observe("site/S1/sensor/\"t1\"/telemetry")
+	dis.observe("site/S1/sensor/\"t1\"/telemetry")
+	assert.Len(t, changes, 1)
+	var links = listDiscovered(t, kubeCli, "discovery")
+	if link, exist := links["discovery-s1--t1"]; assert.True(t, exist) {
+		assert.Equal(t, "sensor", link.Labels["device"])
+		assert.Equal(t, "edge-worker", link.Spec.Adaptor.Node)
+		assert.Equal(t, apitypes.UID("fake-uid"), link.OwnerReferences[0].UID)
+		assert.JSONEq(t, `{"protocol":{"message":{"topic":"site/S1/sensor/\"t1\"/telemetry"}}}`, string(link.Spec.Template.Spec.Raw))
+	}
+
+	// renders the name via template
+	dis.template.Name = "sensor-{{ .sensor }}"
+	dis.observe("site/s2/sensor/t2/telemetry")
+	assert.Contains(t, listDiscovered(t, kubeCli, "discovery"), "sensor-t2")
+
+	// doesn't adopt the DeviceLink which isn't discovered by the owner
+	dis.observe("site/s9/sensor/t9/telemetry")
+	assert.NotContains(t, dis.devices, "sensor-t9")
+	assert.Contains(t, dis.failures, "sensor-t9")
+
+	// doesn't delete the DeviceLink which is replaced by the user
+	var replaced = &edgev1alpha1.DeviceLink{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sensor-t2"}}
+	assert.NoError(t, kubeCli.Delete(context.TODO(), replaced))
+	assert.NoError(t, kubeCli.Create(context.TODO(), replaced))
+
+	// deletes the inactive DeviceLinks
+	dis.collect(time.Now().Add(30 * time.Second))
+	assert.Len(t, listDiscovered(t, kubeCli, "discovery"), 1)
+	dis.collect(time.Now().Add(2 * time.Minute))
+	assert.Len(t, listDiscovered(t, kubeCli, "discovery"), 0)
+	assert.Len(t, changes[len(changes)-1], 0)
+	for _, name := range []string{"sensor-t2", "sensor-t9"} {
+		var link edgev1alpha1.DeviceLink
+		assert.NoError(t, kubeCli.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: name}, &link), "user's DeviceLink %q", name)
+	}
+
+	// drops the received topics instead of blocking if the queue is full
+	for i := 0; i <= discoveryQueueSize; i++ {
+		dis.enqueue("site/s3/sensor/t3/telemetry")
+	}
+	assert.Len(t, dis.queue, discoveryQueueSize)
+}
@@ -8,3 +8,10 @@ type OPCUADeviceExtension struct {
 	// +optional
 	MQTT *mqttapi.MQTTOptions `json:"mqtt,omitempty"`
 }
+
+// OPCUADeviceExtensionStatus defines the observed state of device extension.
+type OPCUADeviceExtensionStatus struct {
+	// Reports the connection status of MQTT extension.
+	// +optional
+	MQTT *mqttapi.MQTTConnectionStatus `json:"mqtt,omitempty"`
+}
@@ -7,6 +7,7 @@
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+	pollapi "github.com/rancher/octopus/pkg/adaptor/poll/api"
 )
 
 // OPCUADeviceParameters defines the desired parameters of OPCUADevice.
@@ -20,6 +21,11 @@ type OPCUADeviceParameters struct {
 	// The default value is "10s".
 	// +kubebuilder:default="10s"
 	Timeout v1.Duration `json:"timeout,omitempty"`
+
+	// Specifies how to synchronize the received values to limb,
+	// the values are synchronized once received if it is not specified.
+	// +optional
+	Sync *pollapi.PollSyncOptions `json:"sync,omitempty"`
 }
 
 func (in *OPCUADeviceParameters) GetSyncInterval() time.Duration {
@@ -143,6 +149,10 @@ type OPCUADeviceProperty struct {
 	// Specifies the value of property, only available in the writable property.
 	// +optional
 	Value string `json:"value,omitempty"`
+
+	// Specifies the polling of property,
+	// the poll interval is used as the sampling interval of the monitored node.
+	pollapi.PollPropertyOptions `json:",inline"`
 }
 
 // OPCUADevicePropertyType defines the type of property.
@@ -220,6 +230,10 @@ type OPCUADeviceStatus struct {
 	// Reports the properties of device.
 	// +optional
 	Properties []OPCUADeviceStatusProperty `json:"properties,omitempty"`
+
+	// Reports the status of device extension.
+	// +optional
+	Extension *OPCUADeviceExtensionStatus `json:"extension,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -21,6 +21,7 @@
 
 import (
 	apiv1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+	pollapi "github.com/rancher/octopus/pkg/adaptor/poll/api"
 	"github.com/rancher/octopus/pkg/mqtt/api"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -72,6 +73,26 @@ func (in *OPCUADeviceExtension) DeepCopy() *OPCUADeviceExtension {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OPCUADeviceExtensionStatus) DeepCopyInto(out *OPCUADeviceExtensionStatus) {
+	*out = *in
+	if in.MQTT != nil {
+		in, out := &in.MQTT, &out.MQTT
+		*out = new(api.MQTTConnectionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OPCUADeviceExtensionStatus.
+func (in *OPCUADeviceExtensionStatus) DeepCopy() *OPCUADeviceExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OPCUADeviceExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OPCUADeviceList) DeepCopyInto(out *OPCUADeviceList) {
 	*out = *in
@@ -109,6 +130,11 @@ func (in *OPCUADeviceParameters) DeepCopyInto(out *OPCUADeviceParameters) {
 	*out = *in
 	out.SyncInterval = in.SyncInterval
 	out.Timeout = in.Timeout
+	if in.Sync != nil {
+		in, out := &in.Sync, &out.Sync
+		*out = new(pollapi.PollSyncOptions)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OPCUADeviceParameters.
@@ -125,6 +151,7 @@ func (in *OPCUADeviceParameters) DeepCopy() *OPCUADeviceParameters {
 func (in *OPCUADeviceProperty) DeepCopyInto(out *OPCUADeviceProperty) {
 	*out = *in
 	out.Visitor = in.Visitor
+	in.PollPropertyOptions.DeepCopyInto(&out.PollPropertyOptions)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OPCUADeviceProperty.
@@ -238,13 +265,15 @@ func (in *OPCUADeviceSpec) DeepCopyInto(out *OPCUADeviceSpec) {
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
 		*out = new(OPCUADeviceParameters)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	in.Protocol.DeepCopyInto(&out.Protocol)
 	if in.Properties != nil {
 		in, out := &in.Properties, &out.Properties
 		*out = make([]OPCUADeviceProperty, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -268,6 +297,11 @@ func (in *OPCUADeviceStatus) DeepCopyInto(out *OPCUADeviceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Extension != nil {
+		in, out := &in.Extension, &out.Extension
+		*out = new(OPCUADeviceExtensionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OPCUADeviceStatus.
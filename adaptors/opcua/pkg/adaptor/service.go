@@ -1,6 +1,8 @@
 package adaptor
 
 import (
+	"sync"
+
 	jsoniter "github.com/json-iterator/go"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -44,6 +46,8 @@ func (s *Service) toJSON(in metav1.Object) []byte {
 
 func (s *Service) Connect(server api.Connection_ConnectServer) error {
 	var holder physical.Device
+	// it is not safe to call Send on the same stream in different goroutines.
+	var sendLock sync.Mutex
 	defer func() {
 		if holder != nil {
 			holder.Shutdown()
@@ -70,6 +74,26 @@ func (s *Service) Connect(server api.Connection_ConnectServer) error {
 			return status.Errorf(codes.InvalidArgument, "invalid model group: %s", modelGVK.Group)
 		}
 
+		// processes action
+		if action := req.GetAction(); action != nil {
+			var resp = &api.ConnectResponseAction{Id: action.GetId()}
+			if holder == nil {
+				resp.ErrorMessage = "device hasn't been connected"
+			} else if result, err := holder.Act(action.GetName(), action.GetArguments()); err != nil {
+				resp.ErrorMessage = err.Error()
+			} else {
+				resp.Result = result
+			}
+
+			sendLock.Lock()
+			err := server.Send(&api.ConnectResponse{Action: resp})
+			sendLock.Unlock()
+			if err != nil {
+				return status.Errorf(codes.Unknown, "failed to send action result to limb, %v", err)
+			}
+			continue
+		}
+
 		// processes device
 		switch modelGVK.Kind {
 		case "OPCUADevice":
@@ -102,6 +126,8 @@ func (s *Service) Connect(server api.Connection_ConnectServer) error {
 					var respBytes = s.toJSON(resp)
 
 					// send device to limb
+					sendLock.Lock()
+					defer sendLock.Unlock()
 					if err := server.Send(&api.ConnectResponse{Device: respBytes}); err != nil {
 						return status.Errorf(codes.Unknown, "failed to send device to limb, %v", err)
 					}
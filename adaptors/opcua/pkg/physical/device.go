@@ -2,6 +2,7 @@
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"reflect"
@@ -18,6 +19,9 @@
 	"github.com/rancher/octopus/adaptors/opcua/api/v1alpha1"
 	"github.com/rancher/octopus/adaptors/opcua/pkg/metadata"
 	api "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/adaptor/command"
+	"github.com/rancher/octopus/pkg/adaptor/poll"
+	pollapi "github.com/rancher/octopus/pkg/adaptor/poll/api"
 	"github.com/rancher/octopus/pkg/adaptor/socket/handler"
 	"github.com/rancher/octopus/pkg/mqtt"
 	"github.com/rancher/octopus/pkg/util/critical"
@@ -30,6 +34,32 @@ type Device interface {
 	Shutdown()
 	// Configure uses to set up the device.
 	Configure(references api.ReferencesHandler, obj *v1alpha1.OPCUADevice) error
+	// Act uses to execute an action on the device, and returns the result in form JSON bytes.
+	Act(name string, arguments []byte) ([]byte, error)
+}
+
+// OPCUADeviceActionArgument defines an input argument of the action.
+type OPCUADeviceActionArgument struct {
+	// Specifies the type of argument.
+	Type v1alpha1.OPCUADevicePropertyType `json:"type"`
+	// Specifies the value of argument.
+	Value string `json:"value"`
+}
+
+// OPCUADeviceActionArguments defines the arguments of the action.
+type OPCUADeviceActionArguments struct {
+	// Specifies the node ID of the object that owns the method.
+	ObjectID string `json:"objectID"`
+	// Specifies the node ID of the method, the name of action is used if it is blank.
+	MethodID string `json:"methodID,omitempty"`
+	// Specifies the input arguments of the method.
+	InputArguments []OPCUADeviceActionArgument `json:"inputArguments,omitempty"`
+}
+
+// OPCUADeviceActionResult defines the result of the action.
+type OPCUADeviceActionResult struct {
+	// Reports the output arguments of the method.
+	OutputArguments []string `json:"outputArguments,omitempty"`
 }
 
 // NewDevice creates a Device.
@@ -80,7 +110,10 @@ func (d *opcuaDevice) Configure(references api.ReferencesHandler, device *v1alph
 		}
 
 		if newExtension.MQTT != nil {
-			var cli, err = mqtt.NewClient(*newExtension.MQTT, object.GetControlledOwnerObjectReference(device), references)
+			var clientBuilder = mqtt.NewClientBuilder(*newExtension.MQTT, object.GetControlledOwnerObjectReference(device))
+			clientBuilder.Render(references)
+			clientBuilder.OnStatusChanged(d.receiveMQTTStatus)
+			var cli, err = clientBuilder.Build()
 			if err != nil {
 				return errors.Wrap(err, "failed to create MQTT opcuaClient")
 			}
@@ -89,12 +122,19 @@ func (d *opcuaDevice) Configure(references api.ReferencesHandler, device *v1alph
 			if err != nil {
 				return errors.Wrap(err, "failed to connect MQTT broker")
 			}
+			// receives the writing commands, e.g. the DCMD of Sparkplug B mode.
+			err = cli.Subscribe(nil, d.receiveCommand)
+			if err != nil {
+				return errors.Wrap(err, "failed to subscribe MQTT commands")
+			}
 			d.mqttClient = cli
 		}
 	}
 
 	// configures OPC-UA client
 	if !reflect.DeepEqual(staleSpec.Protocol, newSpec.Protocol) || !reflect.DeepEqual(staleSpec.Parameters, newSpec.Parameters) {
+		// the subscription is bound to the stale client and interval, so it's recreated along with the client.
+		d.stopSubscribe()
 		if d.opcuaClient != nil {
 			if err := d.opcuaClient.Close(); err != nil {
 				if err != io.EOF {
@@ -114,6 +154,109 @@ func (d *opcuaDevice) Configure(references api.ReferencesHandler, device *v1alph
 	return d.refresh(newSpec)
 }
 
+// Act calls the OPC-UA method named as the action.
+func (d *opcuaDevice) Act(name string, arguments []byte) ([]byte, error) {
+	defer runtime.HandleCrash(handler.NewPanicsCleanupSocketHandler(metadata.Endpoint))
+
+	d.Lock()
+	defer d.Unlock()
+
+	if d.opcuaClient == nil {
+		return nil, errors.New("device hasn't been configured")
+	}
+
+	var args OPCUADeviceActionArguments
+	if len(arguments) != 0 {
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal arguments")
+		}
+	}
+	if args.MethodID == "" {
+		args.MethodID = name
+	}
+
+	objectID, err := ua.ParseNodeID(args.ObjectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse object node ID %s", args.ObjectID)
+	}
+	methodID, err := ua.ParseNodeID(args.MethodID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse method node ID %s", args.MethodID)
+	}
+	var inputs = make([]*ua.Variant, 0, len(args.InputArguments))
+	for _, arg := range args.InputArguments {
+		var input, err = StringToVariant(arg.Type, arg.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert %s string to %s variant", arg.Value, arg.Type)
+		}
+		inputs = append(inputs, input)
+	}
+
+	resp, err := d.opcuaClient.Call(&ua.CallMethodRequest{
+		ObjectID:       objectID,
+		MethodID:       methodID,
+		InputArguments: inputs,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to call method %s", args.MethodID)
+	}
+	if resp.StatusCode != ua.StatusOK {
+		return nil, errors.Errorf("failed to call method %s: %v", args.MethodID, resp.StatusCode)
+	}
+	d.log.V(4).Info("Called method", "method", args.MethodID)
+
+	var result OPCUADeviceActionResult
+	for _, output := range resp.OutputArguments {
+		result.OutputArguments = append(result.OutputArguments, VariantToString(output.Type(), output))
+	}
+	return json.Marshal(result)
+}
+
+// receiveCommand writes the values of command to the writable properties, and replies the results.
+func (d *opcuaDevice) receiveCommand(msg mqtt.SubscribeMessage) {
+	command.Receive(d.log, metadata.Endpoint, msg, func(values map[string]interface{}) mqtt.CommandReply {
+		d.Lock()
+		defer d.Unlock()
+
+		var reply mqtt.CommandReply
+		for name, value := range values {
+			var result = mqtt.CommandResult{Name: name}
+			var formatted = command.FormatValue(value)
+			if err := d.write(name, formatted); err != nil {
+				d.log.Error(err, "Error executing command", "property", name)
+				result.Error = err.Error()
+			} else {
+				result.Value = formatted
+			}
+			reply.Results = append(reply.Results, result)
+		}
+		return reply
+	})
+}
+
+// write writes the value to the writable property,
+// the status is updated by the subscription of OPC-UA node.
+func (d *opcuaDevice) write(name string, value string) error {
+	if d.opcuaClient == nil {
+		return errors.New("device hasn't been configured")
+	}
+
+	for _, prop := range d.instance.Spec.Properties {
+		if prop.Name != name {
+			continue
+		}
+		if prop.ReadOnly {
+			return errors.Errorf("property %s is read-only", name)
+		}
+		if err := d.writeProperty(prop.Type, prop.Visitor, value); err != nil {
+			return errors.Wrapf(err, "failed to write property %s", name)
+		}
+		d.log.V(4).Info("Write property", "property", prop.Name, "type", prop.Type)
+		return nil
+	}
+	return errors.Errorf("cannot find property %s", name)
+}
+
 func (d *opcuaDevice) Shutdown() {
 	d.Lock()
 	defer d.Unlock()
@@ -138,7 +281,7 @@ func (d *opcuaDevice) Shutdown() {
 func (d *opcuaDevice) refresh(newSpec v1alpha1.OPCUADeviceSpec) error {
 	var status = d.instance.Status
 	var staleSpec = d.instance.Spec
-	if !reflect.DeepEqual(staleSpec.Properties, newSpec.Properties) {
+	if !reflect.DeepEqual(getWritingProperties(staleSpec.Properties), getWritingProperties(newSpec.Properties)) {
 		d.stopSubscribe()
 
 		// configures properties
@@ -159,10 +302,13 @@ func (d *opcuaDevice) refresh(newSpec v1alpha1.OPCUADeviceSpec) error {
 			})
 		}
 		status = v1alpha1.OPCUADeviceStatus{Properties: statusProps}
+	} else if !reflect.DeepEqual(staleSpec.Properties, newSpec.Properties) {
+		// resubscribes without writing, e.g. the poll interval of property is changed.
+		d.stopSubscribe()
 	}
 
 	// subscribed in backend
-	if err := d.startSubscribe(newSpec.Parameters.GetSyncInterval(), newSpec.Properties); err != nil {
+	if err := d.startSubscribe(newSpec.Parameters, newSpec.Properties); err != nil {
 		return errors.Wrap(err, "failed to subscribing")
 	}
 
@@ -209,7 +355,8 @@ func (d *opcuaDevice) writeProperty(dataType v1alpha1.OPCUADevicePropertyType, v
 
 // subscribe is blocked, it is used to watch the notification from OPC-UA server
 // and update the opcua device status.
-func (d *opcuaDevice) subscribe(ctx context.Context, notifyCh chan *opcua.PublishNotificationData) {
+// The status is synchronized once received or when any property changes according to the sync mode.
+func (d *opcuaDevice) subscribe(ctx context.Context, notifyCh chan *opcua.PublishNotificationData, interval time.Duration, options *pollapi.PollSyncOptions, properties []v1alpha1.OPCUADeviceProperty) {
 	defer runtime.HandleCrash(handler.NewPanicsCleanupSocketHandler(metadata.Endpoint))
 
 	d.log.Info("Subscribing")
@@ -217,10 +364,30 @@ func (d *opcuaDevice) subscribe(ctx context.Context, notifyCh chan *opcua.Publis
 		d.log.Info("Finished subscription")
 	}()
 
+	// the heartbeat checks if the max silence is reached in OnChange mode.
+	var heartbeat = time.NewTicker(interval)
+	defer heartbeat.Stop()
+
+	var filter = poll.NewFilter(options)
+	var syncIfNeeded = func(now time.Time) {
+		if !filter.ShouldSync(now) {
+			return
+		}
+		if err := d.sync(); err != nil {
+			d.log.Error(err, "failed to sync")
+			return
+		}
+		filter.Synced(now)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case tick := <-heartbeat.C:
+			d.Lock()
+			syncIfNeeded(tick)
+			d.Unlock()
 		case res := <-notifyCh:
 			if res.Error != nil {
 				// TODO give a way to feedback this to limb.
@@ -237,7 +404,7 @@ func() {
 					var statusProps = d.instance.Status.Properties
 					for _, item := range v.MonitoredItems {
 						var idx = int(item.ClientHandle)
-						if idx >= len(statusProps) {
+						if idx >= len(statusProps) || idx >= len(properties) {
 							continue
 						}
 						var prop = statusProps[idx]
@@ -251,11 +418,10 @@ func() {
 							Type:      propType,
 							UpdatedAt: now(),
 						}
+						filter.Observe(prop.Name, value, properties[idx].Deadband)
 					}
 					d.instance.Status.Properties = statusProps
-					if err := d.sync(); err != nil {
-						d.log.Error(err, "failed to sync")
-					}
+					syncIfNeeded(time.Now())
 				}()
 			default:
 				d.log.V(4).Info(fmt.Sprintf("Received unknown property %+v", res.Value))
@@ -271,10 +437,20 @@ func (d *opcuaDevice) stopSubscribe() {
 	}
 }
 
-func (d *opcuaDevice) startSubscribe(subscribeInterval time.Duration, properties []v1alpha1.OPCUADeviceProperty) error {
+func (d *opcuaDevice) startSubscribe(parameters *v1alpha1.OPCUADeviceParameters, properties []v1alpha1.OPCUADeviceProperty) error {
 	if d.stop == nil {
 		d.stop = make(chan struct{})
 
+		// the publishing interval of subscription is the min interval of all properties,
+		// so that the property polled in shorter interval is not delayed.
+		var syncInterval = parameters.GetSyncInterval()
+		var subscribeInterval = syncInterval
+		for _, prop := range properties {
+			if interval := prop.GetPollInterval(syncInterval); interval < subscribeInterval {
+				subscribeInterval = interval
+			}
+		}
+
 		// creates subscription
 		var notifyCh = make(chan *opcua.PublishNotificationData)
 		var sub, err = d.opcuaClient.Subscribe(&opcua.SubscriptionParameters{Interval: subscribeInterval}, notifyCh)
@@ -292,6 +468,9 @@ func (d *opcuaDevice) startSubscribe(subscribeInterval time.Duration, properties
 
 			var handle = uint32(idx)
 			var miCreateRequest = opcua.NewMonitoredItemCreateRequestWithDefaults(id, ua.AttributeIDValue, handle)
+			if prop.PollInterval != nil {
+				miCreateRequest.RequestedParameters.SamplingInterval = float64(prop.GetPollInterval(syncInterval) / time.Millisecond)
+			}
 			res, err := sub.Monitor(ua.TimestampsToReturnBoth, miCreateRequest)
 			if err != nil {
 				return errors.Wrapf(err, "error monitoring property %s", prop.Name)
@@ -314,7 +493,11 @@ func (d *opcuaDevice) startSubscribe(subscribeInterval time.Duration, properties
 		go sub.Run(ctx)
 		d.log.Info("Running subscription", "id", sub.SubscriptionID)
 
-		go d.subscribe(ctx, notifyCh)
+		var options *pollapi.PollSyncOptions
+		if parameters != nil {
+			options = parameters.Sync
+		}
+		go d.subscribe(ctx, notifyCh, subscribeInterval, options, properties)
 	}
 
 	return nil
@@ -322,13 +505,22 @@ func (d *opcuaDevice) startSubscribe(subscribeInterval time.Duration, properties
 
 // sync combines all synchronization operations.
 func (d *opcuaDevice) sync() error {
+	if d.mqttClient != nil {
+		var mqttStatus = d.mqttClient.Status()
+		d.instance.Status.Extension = &v1alpha1.OPCUADeviceExtensionStatus{MQTT: &mqttStatus}
+	} else {
+		d.instance.Status.Extension = nil
+	}
 	if d.toLimb != nil {
 		if err := d.toLimb(d.instance); err != nil {
 			return err
 		}
 	}
 	if d.mqttClient != nil {
-		if err := d.mqttClient.Publish(mqtt.PublishMessage{Payload: d.instance.Status}); err != nil {
+		// the status of extension is reported to limb only.
+		var status = d.instance.Status
+		status.Extension = nil
+		if err := d.mqttClient.Publish(mqtt.PublishMessage{Payload: status}); err != nil {
 			return err
 		}
 	}
@@ -336,6 +528,36 @@ func (d *opcuaDevice) sync() error {
 	return nil
 }
 
+// receiveMQTTStatus reports the changed connection status of MQTT extension to limb.
+func (d *opcuaDevice) receiveMQTTStatus() {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.mqttClient == nil || d.toLimb == nil {
+		return
+	}
+	var mqttStatus = d.mqttClient.Status()
+	d.instance.Status.Extension = &v1alpha1.OPCUADeviceExtensionStatus{MQTT: &mqttStatus}
+	if err := d.toLimb(d.instance); err != nil {
+		d.log.Error(err, "failed to report MQTT connection status")
+	}
+}
+
+// getWritingProperties returns the properties without the fields which don't affect the writing,
+// e.g. the description and the polling options.
+func getWritingProperties(props []v1alpha1.OPCUADeviceProperty) []v1alpha1.OPCUADeviceProperty {
+	if len(props) == 0 {
+		return nil
+	}
+	var ret = make([]v1alpha1.OPCUADeviceProperty, 0, len(props))
+	for _, prop := range props {
+		prop.Description = ""
+		prop.PollPropertyOptions = pollapi.PollPropertyOptions{}
+		ret = append(ret, prop)
+	}
+	return ret
+}
+
 func now() *metav1.Time {
 	var ret = metav1.Now()
 	return &ret
@@ -0,0 +1,140 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeviceActionSpec defines the desired state of DeviceAction
+type DeviceActionSpec struct {
+	// Specifies the name of the DeviceLink in the same Namespace to act on.
+	// +kubebuilder:validation:Required
+	DeviceLink string `json:"deviceLink"`
+
+	// Specifies the name of the action, it is interpreted by the adaptor.
+	// The Modbus adaptor writes the property named as the action,
+	// and the OPC-UA adaptor calls the method of the object given in the arguments,
+	// the method is named as the action if its ID is not given.
+	// The dummy, BLE and MQTT adaptors reply the action is unsupported.
+	// +kubebuilder:validation:Required
+	Action string `json:"action"`
+
+	// Specifies the arguments of the action, it is interpreted by the adaptor.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Arguments *runtime.RawExtension `json:"arguments,omitempty"`
+
+	// Specifies the amount of time that limb waits for the adaptor to respond the action.
+	// The default value is "30s".
+	// +kubebuilder:default="30s"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+func (in *DeviceActionSpec) GetTimeout() time.Duration {
+	if in != nil && in.Timeout != nil {
+		if duration := in.Timeout.Duration; duration > 0 {
+			return duration
+		}
+	}
+	return 30 * time.Second
+}
+
+// DeviceActionPhase is a label for the condition of an action at the current time.
+type DeviceActionPhase string
+
+// These are valid phases of an action
+const (
+	// DeviceActionPending means that the action has been accepted but hasn't been sent to the adaptor.
+	DeviceActionPending DeviceActionPhase = "Pending"
+
+	// DeviceActionRunning means that the action has been sent to the adaptor and is waiting for the result.
+	DeviceActionRunning DeviceActionPhase = "Running"
+
+	// DeviceActionSucceeded means that the adaptor has executed the action successfully.
+	DeviceActionSucceeded DeviceActionPhase = "Succeeded"
+
+	// DeviceActionFailed means that the action cannot be executed or the adaptor returns an error.
+	DeviceActionFailed DeviceActionPhase = "Failed"
+)
+
+// DeviceActionStatus defines the observed state of DeviceAction
+type DeviceActionStatus struct {
+	// Represents the current phase of the action.
+	// +optional
+	Phase DeviceActionPhase `json:"phase,omitempty"`
+
+	// Represents the ID used to correlate the action with the result of adaptor.
+	// +optional
+	CorrelationID string `json:"correlationID,omitempty"`
+
+	// Represents the ID of the latest attempt to send the action,
+	// a running action is failed if the attempt is lost, e.g. limb is restarted.
+	// +optional
+	AttemptID string `json:"attemptID,omitempty"`
+
+	// Represents the observed Node name of the action.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Represents the observed adaptor name of the action.
+	// +optional
+	AdaptorName string `json:"adaptorName,omitempty"`
+
+	// Represents the time when the action was sent to the adaptor.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Represents the time when the action was completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Represents the result of the action returned by the adaptor.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Result *runtime.RawExtension `json:"result,omitempty"`
+
+	// A human readable message indicating details about the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// IsCompleted returns true if the action has been completed.
+func (in *DeviceActionStatus) IsCompleted() bool {
+	if in == nil {
+		return false
+	}
+	return in.Phase == DeviceActionSucceeded || in.Phase == DeviceActionFailed
+}
+
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName=da
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="LINK",type=string,JSONPath=`.spec.deviceLink`
+// +kubebuilder:printcolumn:name="ACTION",type=string,JSONPath=`.spec.action`
+// +kubebuilder:printcolumn:name="PHASE",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// DeviceAction is the Schema for the deviceactions API
+type DeviceAction struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DeviceActionSpec   `json:"spec,omitempty"`
+	Status DeviceActionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// DeviceActionList contains a list of DeviceAction
+type DeviceActionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeviceAction `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DeviceAction{}, &DeviceActionList{})
+}
@@ -20,10 +20,22 @@ func (in *DeviceLink) SucceedOnNodeExisted(node *corev1.Node) {
 	if in == nil {
 		return
 	}
+	var nodeName = in.Spec.Adaptor.Node
+	if node != nil && node.Name != "" {
+		nodeName = node.Name
+	}
 	in.Status.Conditions = deviceLinkConditions(in.Status.Conditions).
-		did(DeviceLinkNodeExisted, metav1.ConditionTrue, "Found", "", in.Status.NodeName != in.Spec.Adaptor.Node).
+		did(DeviceLinkNodeExisted, metav1.ConditionTrue, "Found", "", in.Status.NodeName != nodeName).
 		next(DeviceLinkModelExisted, "Confirming", "verify if there is a suitable model as a template")
-	in.Status.NodeName = in.Spec.Adaptor.Node
+	if in.Status.NodeName != nodeName {
+		// clears the addresses of the previous node.
+		in.Status.NodeInternalDNS = ""
+		in.Status.NodeInternalIP = ""
+		in.Status.NodeExternalDNS = ""
+		in.Status.NodeExternalIP = ""
+		in.Status.NodeHostName = ""
+	}
+	in.Status.NodeName = nodeName
 	if node != nil {
 		for _, address := range node.Status.Addresses {
 			switch address.Type {
@@ -100,6 +112,15 @@ func (in *DeviceLink) FailOnAdaptorExisted(message string) {
 	in.Status.AdaptorName = ""
 }
 
+func (in *DeviceLink) FailOnAdaptorSupportedModel(message string) {
+	if in == nil {
+		return
+	}
+	in.Status.Conditions = deviceLinkConditions(in.Status.Conditions).
+		did(DeviceLinkAdaptorExisted, metav1.ConditionFalse, "UnsupportedModel", message, in.Status.AdaptorName != in.Spec.Adaptor.Name)
+	in.Status.AdaptorName = ""
+}
+
 func (in *DeviceLink) SucceedOnAdaptorExisted() {
 	if in == nil {
 		return
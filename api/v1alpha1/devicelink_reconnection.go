@@ -0,0 +1,77 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IsReconnectable returns true if the device can be reconnected automatically,
+// the closed flag indicates whether the connection is closed passively by the adaptor.
+func (in *DeviceLink) IsReconnectable(closed bool) bool {
+	if in == nil || in.Spec.ReconnectPolicy == nil {
+		return false
+	}
+	switch in.Spec.ReconnectPolicy.GetType() {
+	case DeviceLinkReconnectAlways:
+		return true
+	case DeviceLinkReconnectOnFailure:
+		return !closed
+	}
+	return false
+}
+
+// ScheduleReconnection records a new reconnection attempt and returns the backoff duration before reconnecting.
+func (in *DeviceLink) ScheduleReconnection(now time.Time) time.Duration {
+	if in == nil {
+		return 0
+	}
+
+	var reconnection = in.Status.Reconnection
+	if reconnection == nil {
+		reconnection = &DeviceLinkReconnectionStatus{}
+	}
+
+	var policy = in.Spec.ReconnectPolicy
+	var maxBackoff = policy.GetMaxBackoff()
+	var backoff = policy.GetBackoff()
+	for i := int32(0); i < reconnection.Attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	reconnection.Attempts++
+	reconnection.NextRetryTime = &metav1.Time{Time: now.Add(backoff)}
+	reconnection.ObservedGeneration = in.Generation
+	in.Status.Reconnection = reconnection
+	return backoff
+}
+
+// GetReconnectionBackoff returns the remaining duration before the next reconnection,
+// returns 0 if there is not any scheduled reconnection, the reconnection is due,
+// or the spec has been changed since the reconnection was scheduled.
+func (in *DeviceLink) GetReconnectionBackoff(now time.Time) time.Duration {
+	if in == nil || in.Status.Reconnection == nil || in.Status.Reconnection.NextRetryTime == nil {
+		return 0
+	}
+	if in.Status.Reconnection.ObservedGeneration != in.Generation {
+		return 0
+	}
+	var remaining = in.Status.Reconnection.NextRetryTime.Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// ResetReconnection clears the reconnection records,
+// returns true if there is anything cleared.
+func (in *DeviceLink) ResetReconnection() bool {
+	if in == nil || in.Status.Reconnection == nil {
+		return false
+	}
+	in.Status.Reconnection = nil
+	return true
+}
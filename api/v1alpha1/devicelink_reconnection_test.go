@@ -0,0 +1,169 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeviceLink_IsReconnectable(t *testing.T) {
+	var newLink = func(policyType DeviceLinkReconnectPolicyType) *DeviceLink {
+		return &DeviceLink{
+			Spec: DeviceLinkSpec{
+				ReconnectPolicy: &DeviceLinkReconnectPolicy{Type: policyType},
+			},
+		}
+	}
+
+	type input struct {
+		link   *DeviceLink
+		closed bool
+	}
+	var testCases = []struct {
+		name     string
+		given    input
+		expected bool
+	}{
+		{
+			name:     "nil link",
+			given:    input{link: nil},
+			expected: false,
+		},
+		{
+			name:     "without policy",
+			given:    input{link: &DeviceLink{}},
+			expected: false,
+		},
+		{
+			name:     "default policy on failure",
+			given:    input{link: newLink(""), closed: false},
+			expected: true,
+		},
+		{
+			name:     "default policy on closing",
+			given:    input{link: newLink(""), closed: true},
+			expected: false,
+		},
+		{
+			name:     "always policy on closing",
+			given:    input{link: newLink(DeviceLinkReconnectAlways), closed: true},
+			expected: true,
+		},
+		{
+			name:     "never policy on failure",
+			given:    input{link: newLink(DeviceLinkReconnectNever), closed: false},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = tc.given.link.IsReconnectable(tc.given.closed)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
+func TestDeviceLink_ScheduleReconnection(t *testing.T) {
+	var now = time.Now()
+	var policy = &DeviceLinkReconnectPolicy{
+		Backoff:    &metav1.Duration{Duration: time.Second},
+		MaxBackoff: &metav1.Duration{Duration: 3 * time.Second},
+	}
+
+	type output struct {
+		backoff  time.Duration
+		attempts int32
+	}
+	var testCases = []struct {
+		name     string
+		given    *DeviceLink
+		expected output
+	}{
+		{
+			name: "default backoff",
+			given: &DeviceLink{
+				Spec: DeviceLinkSpec{ReconnectPolicy: &DeviceLinkReconnectPolicy{}},
+			},
+			expected: output{backoff: 5 * time.Second, attempts: 1},
+		},
+		{
+			name: "first attempt",
+			given: &DeviceLink{
+				Spec: DeviceLinkSpec{ReconnectPolicy: policy},
+			},
+			expected: output{backoff: time.Second, attempts: 1},
+		},
+		{
+			name: "doubled backoff",
+			given: &DeviceLink{
+				Spec:   DeviceLinkSpec{ReconnectPolicy: policy},
+				Status: DeviceLinkStatus{Reconnection: &DeviceLinkReconnectionStatus{Attempts: 1}},
+			},
+			expected: output{backoff: 2 * time.Second, attempts: 2},
+		},
+		{
+			name: "limited backoff",
+			given: &DeviceLink{
+				Spec:   DeviceLinkSpec{ReconnectPolicy: policy},
+				Status: DeviceLinkStatus{Reconnection: &DeviceLinkReconnectionStatus{Attempts: 5}},
+			},
+			expected: output{backoff: 3 * time.Second, attempts: 6},
+		},
+	}
+
+	for _, tc := range testCases {
+		var backoff = tc.given.ScheduleReconnection(now)
+		var actual = output{backoff: backoff, attempts: tc.given.Status.Reconnection.Attempts}
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+		assert.Equal(t, now.Add(backoff), tc.given.Status.Reconnection.NextRetryTime.Time, "case %q", tc.name)
+	}
+}
+
+func TestDeviceLink_GetReconnectionBackoff(t *testing.T) {
+	var now = time.Now()
+	var newLink = func(generation, observedGeneration int64, nextRetryTime time.Time) *DeviceLink {
+		return &DeviceLink{
+			ObjectMeta: metav1.ObjectMeta{Generation: generation},
+			Status: DeviceLinkStatus{
+				Reconnection: &DeviceLinkReconnectionStatus{
+					Attempts:           1,
+					NextRetryTime:      &metav1.Time{Time: nextRetryTime},
+					ObservedGeneration: observedGeneration,
+				},
+			},
+		}
+	}
+
+	var testCases = []struct {
+		name     string
+		given    *DeviceLink
+		expected time.Duration
+	}{
+		{
+			name:     "not scheduled",
+			given:    &DeviceLink{},
+			expected: 0,
+		},
+		{
+			name:     "pending",
+			given:    newLink(1, 1, now.Add(time.Second)),
+			expected: time.Second,
+		},
+		{
+			name:     "due",
+			given:    newLink(1, 1, now.Add(-time.Second)),
+			expected: 0,
+		},
+		{
+			name:     "changed spec",
+			given:    newLink(2, 1, now.Add(time.Second)),
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = tc.given.GetReconnectionBackoff(now)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
@@ -0,0 +1,33 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// IsScheduledBySelector returns true if the link is scheduled by the node selector instead of the node name.
+func (in *DeviceLink) IsScheduledBySelector() bool {
+	if in == nil {
+		return false
+	}
+	return in.Spec.Adaptor.Node == "" && len(in.Spec.Adaptor.NodeSelector) != 0
+}
+
+// GetScheduledNodeName returns the name of the node which the link is scheduled on.
+func (in *DeviceLink) GetScheduledNodeName() string {
+	if in == nil {
+		return ""
+	}
+	if in.Spec.Adaptor.Node != "" {
+		return in.Spec.Adaptor.Node
+	}
+	return in.Status.NodeName
+}
+
+// MatchNodeSelector returns true if the labels of the node match the node selector of the link.
+func (in *DeviceLink) MatchNodeSelector(node *corev1.Node) bool {
+	if in == nil || node == nil {
+		return false
+	}
+	return labels.SelectorFromSet(in.Spec.Adaptor.NodeSelector).Matches(labels.Set(node.Labels))
+}
@@ -0,0 +1,45 @@
+package v1alpha1
+
+import (
+	"time"
+)
+
+// maxSendRetries is the upper limit of the sending retries.
+const maxSendRetries = 10
+
+// ScheduleSendRetry records a new sending retry and returns the backoff duration before retrying,
+// returns false if the retries are exhausted, and then the retries are reset for the next connecting.
+func (in *DeviceLink) ScheduleSendRetry() (time.Duration, bool) {
+	if in == nil {
+		return 0, false
+	}
+
+	var policy = in.Spec.SendPolicy
+	var retries = in.Status.SendRetries
+	if int(retries) >= policy.GetMaxRetries() {
+		in.Status.SendRetries = 0
+		return 0, false
+	}
+
+	var maxBackoff = policy.GetMaxBackoff()
+	var backoff = policy.GetBackoff()
+	for i := int32(0); i < retries && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	in.Status.SendRetries = retries + 1
+	return backoff, true
+}
+
+// ResetSendRetries clears the sending retries,
+// returns true if there is anything cleared.
+func (in *DeviceLink) ResetSendRetries() bool {
+	if in == nil || in.Status.SendRetries == 0 {
+		return false
+	}
+	in.Status.SendRetries = 0
+	return true
+}
@@ -0,0 +1,82 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeviceLink_ScheduleSendRetry(t *testing.T) {
+	var int32Ptr = func(i int32) *int32 {
+		return &i
+	}
+	var policy = &DeviceLinkSendPolicy{
+		MaxRetries: int32Ptr(3),
+		Backoff:    &metav1.Duration{Duration: time.Second},
+		MaxBackoff: &metav1.Duration{Duration: 3 * time.Second},
+	}
+
+	type output struct {
+		backoff time.Duration
+		retry   bool
+		retries int32
+	}
+	var testCases = []struct {
+		name     string
+		given    *DeviceLink
+		expected output
+	}{
+		{
+			name:     "no retry by default",
+			given:    &DeviceLink{},
+			expected: output{backoff: 0, retry: false, retries: 0},
+		},
+		{
+			name: "first retry",
+			given: &DeviceLink{
+				Spec: DeviceLinkSpec{SendPolicy: policy},
+			},
+			expected: output{backoff: time.Second, retry: true, retries: 1},
+		},
+		{
+			name: "doubled backoff",
+			given: &DeviceLink{
+				Spec:   DeviceLinkSpec{SendPolicy: policy},
+				Status: DeviceLinkStatus{SendRetries: 1},
+			},
+			expected: output{backoff: 2 * time.Second, retry: true, retries: 2},
+		},
+		{
+			name: "limited backoff",
+			given: &DeviceLink{
+				Spec:   DeviceLinkSpec{SendPolicy: policy},
+				Status: DeviceLinkStatus{SendRetries: 2},
+			},
+			expected: output{backoff: 3 * time.Second, retry: true, retries: 3},
+		},
+		{
+			name: "exhausted retries",
+			given: &DeviceLink{
+				Spec:   DeviceLinkSpec{SendPolicy: policy},
+				Status: DeviceLinkStatus{SendRetries: 3},
+			},
+			expected: output{backoff: 0, retry: false, retries: 0},
+		},
+		{
+			name: "limited retries",
+			given: &DeviceLink{
+				Spec:   DeviceLinkSpec{SendPolicy: &DeviceLinkSendPolicy{MaxRetries: int32Ptr(100)}},
+				Status: DeviceLinkStatus{SendRetries: maxSendRetries},
+			},
+			expected: output{backoff: 0, retry: false, retries: 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		var backoff, retry = tc.given.ScheduleSendRetry()
+		var actual = output{backoff: backoff, retry: retry, retries: tc.given.Status.SendRetries}
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
@@ -1,6 +1,8 @@
 package v1alpha1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -32,6 +34,20 @@ type DeviceLinkReferenceSecretSource struct {
 	// the connection will error unless it is marked optional.
 	// +optional
 	Items []string `json:"items,omitempty"`
+
+	// Specifies whether the Secret or its keys must be defined.
+	// +optional
+	Optional *bool `json:"optional,omitempty"`
+
+	// Specifies the default value of the key,
+	// which is returned to the adaptor if the optional key is not present.
+	// +optional
+	DefaultValues map[string]string `json:"defaultValues,omitempty"`
+}
+
+// IsOptional returns true if the Secret or its keys can be absent.
+func (in *DeviceLinkReferenceSecretSource) IsOptional() bool {
+	return in != nil && in.Optional != nil && *in.Optional
 }
 
 // DeviceLinkReferenceConfigMapSource defines the source of a same name ConfigMap instance.
@@ -47,6 +63,20 @@ type DeviceLinkReferenceConfigMapSource struct {
 	// the connection will error unless it is marked optional.
 	// +optional
 	Items []string `json:"items,omitempty"`
+
+	// Specifies whether the ConfigMap or its keys must be defined.
+	// +optional
+	Optional *bool `json:"optional,omitempty"`
+
+	// Specifies the default value of the key,
+	// which is returned to the adaptor if the optional key is not present.
+	// +optional
+	DefaultValues map[string]string `json:"defaultValues,omitempty"`
+}
+
+// IsOptional returns true if the ConfigMap or its keys can be absent.
+func (in *DeviceLinkReferenceConfigMapSource) IsOptional() bool {
+	return in != nil && in.Optional != nil && *in.Optional
 }
 
 // DeviceLinkReferenceDownwardAPISourceItem defines the downward API item for projecting the DeviceLink.
@@ -97,6 +127,12 @@ type DeviceAdaptor struct {
 	// +optional
 	Node string `json:"node,omitempty"`
 
+	// Specifies the labels of nodes to be matched, it's only used when the node is blank.
+	// Brain picks a ready node which has registered the adaptor from the matched nodes,
+	// and reschedules the link to another matched node if the picked node goes NotReady or is deleted.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
 	// Specifies the name of adaptor to be used.
 	// +kubebuilder:validation:Required
 	Name string `json:"name,omitempty"`
@@ -129,6 +165,142 @@ type DeviceTemplateSpec struct {
 	Spec *runtime.RawExtension `json:"spec,omitempty"`
 }
 
+// DeviceLinkSendPolicy defines the policy of sending the desired device to the adaptor.
+type DeviceLinkSendPolicy struct {
+	// Specifies the amount of time that limb waits for the adaptor to respond a sending.
+	// The default value is "90s".
+	// +kubebuilder:default="90s"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Specifies the maximum number of retries after a sending failed,
+	// limb marks the DeviceConnected condition as failed once the retries are exhausted.
+	// The default value is "0", which means no retry, and the maximum value is "10".
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +kubebuilder:default=0
+	// +optional
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+
+	// Specifies the amount of time that limb waits before the first retry,
+	// the waiting time is doubled on every next retry until reaching `MaxBackoff`.
+	// The default value is "1s".
+	// +kubebuilder:default="1s"
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+
+	// Specifies the maximum amount of time that limb waits between two retries.
+	// The default value is "30s".
+	// +kubebuilder:default="30s"
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+func (in *DeviceLinkSendPolicy) GetTimeout() time.Duration {
+	if in != nil && in.Timeout != nil {
+		if duration := in.Timeout.Duration; duration > 0 {
+			return duration
+		}
+	}
+	return 90 * time.Second
+}
+
+func (in *DeviceLinkSendPolicy) GetMaxRetries() int {
+	if in != nil && in.MaxRetries != nil {
+		if retries := int(*in.MaxRetries); retries > 0 {
+			if retries > maxSendRetries {
+				return maxSendRetries
+			}
+			return retries
+		}
+	}
+	return 0
+}
+
+func (in *DeviceLinkSendPolicy) GetBackoff() time.Duration {
+	if in != nil && in.Backoff != nil {
+		if duration := in.Backoff.Duration; duration > 0 {
+			return duration
+		}
+	}
+	return 1 * time.Second
+}
+
+func (in *DeviceLinkSendPolicy) GetMaxBackoff() time.Duration {
+	if in != nil && in.MaxBackoff != nil {
+		if duration := in.MaxBackoff.Duration; duration > 0 {
+			return duration
+		}
+	}
+	return 30 * time.Second
+}
+
+// DeviceLinkReconnectPolicyType describes when limb reconnects the device after the connection is broken.
+// +kubebuilder:validation:Enum=Never;OnFailure;Always
+type DeviceLinkReconnectPolicyType string
+
+const (
+	// DeviceLinkReconnectNever means that limb never reconnects the device automatically,
+	// the DeviceConnected condition is marked as failed once the connection is broken.
+	DeviceLinkReconnectNever DeviceLinkReconnectPolicyType = "Never"
+
+	// DeviceLinkReconnectOnFailure means that limb reconnects the device with backoff
+	// if the adaptor reports an error or the connecting fails,
+	// and reconnects immediately if the connection is closed by the adaptor.
+	DeviceLinkReconnectOnFailure DeviceLinkReconnectPolicyType = "OnFailure"
+
+	// DeviceLinkReconnectAlways means that limb reconnects the device with backoff
+	// whatever the connection is broken.
+	DeviceLinkReconnectAlways DeviceLinkReconnectPolicyType = "Always"
+)
+
+// DeviceLinkReconnectPolicy defines the policy of reconnecting the device after the connection is broken.
+type DeviceLinkReconnectPolicy struct {
+	// Specifies when to reconnect the device.
+	// The default value is "OnFailure".
+	// +kubebuilder:default=OnFailure
+	// +optional
+	Type DeviceLinkReconnectPolicyType `json:"type,omitempty"`
+
+	// Specifies the amount of time that limb waits before the first reconnection,
+	// the waiting time is doubled on every next reconnection until reaching `MaxBackoff`.
+	// The default value is "5s".
+	// +kubebuilder:default="5s"
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+
+	// Specifies the maximum amount of time that limb waits between two reconnections.
+	// The default value is "5m".
+	// +kubebuilder:default="5m"
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+func (in *DeviceLinkReconnectPolicy) GetType() DeviceLinkReconnectPolicyType {
+	if in != nil && in.Type != "" {
+		return in.Type
+	}
+	return DeviceLinkReconnectOnFailure
+}
+
+func (in *DeviceLinkReconnectPolicy) GetBackoff() time.Duration {
+	if in != nil && in.Backoff != nil {
+		if duration := in.Backoff.Duration; duration > 0 {
+			return duration
+		}
+	}
+	return 5 * time.Second
+}
+
+func (in *DeviceLinkReconnectPolicy) GetMaxBackoff() time.Duration {
+	if in != nil && in.MaxBackoff != nil {
+		if duration := in.MaxBackoff.Duration; duration > 0 {
+			return duration
+		}
+	}
+	return 5 * time.Minute
+}
+
 type DeviceLinkConditionType string
 
 // These are valid conditions of a device
@@ -188,6 +360,15 @@ type DeviceLinkSpec struct {
 	// +optional
 	References []DeviceLinkReference `json:"references,omitempty"`
 
+	// Specifies the policy of sending the desired device to the adaptor.
+	// +optional
+	SendPolicy *DeviceLinkSendPolicy `json:"sendPolicy,omitempty"`
+
+	// Specifies the policy of reconnecting the device after the connection is broken,
+	// limb keeps the legacy behavior if it is not specified.
+	// +optional
+	ReconnectPolicy *DeviceLinkReconnectPolicy `json:"reconnectPolicy,omitempty"`
+
 	// Describe the device that will be created.
 	// +kubebuilder:validation:Required
 	Template DeviceTemplateSpec `json:"template"`
@@ -230,6 +411,30 @@ type DeviceLinkStatus struct {
 	// Represents the observed adaptor name of the device.
 	// +optional
 	AdaptorName string `json:"adaptorName,omitempty"`
+
+	// Represents the observed reconnection of the device.
+	// +optional
+	Reconnection *DeviceLinkReconnectionStatus `json:"reconnection,omitempty"`
+
+	// Represents the number of sending retries since the last sending failed.
+	// +optional
+	SendRetries int32 `json:"sendRetries,omitempty"`
+}
+
+// DeviceLinkReconnectionStatus describes the reconnection of a device.
+type DeviceLinkReconnectionStatus struct {
+	// Represents the number of reconnections since the device was connected healthily.
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// Represents the time of the next reconnection.
+	// +optional
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+
+	// Represents the generation of the DeviceLink when scheduling the reconnection,
+	// the scheduled reconnection is skipped once the DeviceLink is changed.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -7,4 +7,10 @@
 var (
 	// GroupResourceDeviceLink is group resource represented to the DeviceLink
 	GroupResourceDeviceLink = schema.GroupResource{Group: GroupVersion.Group, Resource: "DeviceLink"}
+
+	// GroupResourceDeviceAction is group resource represented to the DeviceAction
+	GroupResourceDeviceAction = schema.GroupResource{Group: GroupVersion.Group, Resource: "DeviceAction"}
+
+	// GroupResourceNodeAdaptor is group resource represented to the NodeAdaptor
+	GroupResourceNodeAdaptor = schema.GroupResource{Group: GroupVersion.Group, Resource: "NodeAdaptor"}
 )
@@ -0,0 +1,83 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RegisteredAdaptor defines an adaptor registered on the node.
+type RegisteredAdaptor struct {
+	// Name of the adaptor in the form `adaptor-vendor.com/adaptor-name`.
+	Name string `json:"name"`
+
+	// Version of the API the adaptor was built against.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Name of the unix socket the adaptor is listening on.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Models served by the adaptor, the adaptor is treated as serving any model if it's empty.
+	// +optional
+	Models []metav1.TypeMeta `json:"models,omitempty"`
+
+	// Features supported by the adaptor.
+	// +optional
+	Features []string `json:"features,omitempty"`
+
+	// Number of active connections of the adaptor.
+	// +optional
+	Connections int32 `json:"connections,omitempty"`
+
+	// Time when the adaptor was registered.
+	// +optional
+	RegistrationTime metav1.Time `json:"registrationTime,omitempty"`
+}
+
+// NodeAdaptorStatus defines the observed state of NodeAdaptor
+type NodeAdaptorStatus struct {
+	// Represents the adaptors registered on the node.
+	// +optional
+	Adaptors []RegisteredAdaptor `json:"adaptors,omitempty"`
+}
+
+// GetAdaptor returns the registered adaptor of name, or nil if not found.
+func (in *NodeAdaptorStatus) GetAdaptor(name string) *RegisteredAdaptor {
+	if in == nil {
+		return nil
+	}
+	for i := range in.Adaptors {
+		if in.Adaptors[i].Name == name {
+			return &in.Adaptors[i]
+		}
+	}
+	return nil
+}
+
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:scope=Cluster,shortName=na;adaptors
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ADAPTORS",type=string,JSONPath=`.status.adaptors[*].name`
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// NodeAdaptor is the Schema for the nodeadaptors API,
+// limb publishes the registered adaptors of a node to the NodeAdaptor named as that node.
+type NodeAdaptor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status NodeAdaptorStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// NodeAdaptorList contains a list of NodeAdaptor
+type NodeAdaptorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeAdaptor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeAdaptor{}, &NodeAdaptorList{})
+}
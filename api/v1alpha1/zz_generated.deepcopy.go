@@ -20,14 +20,133 @@
 package v1alpha1
 
 import (
-	"k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceAction) DeepCopyInto(out *DeviceAction) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceAction.
+func (in *DeviceAction) DeepCopy() *DeviceAction {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceAction) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceActionList) DeepCopyInto(out *DeviceActionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeviceAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceActionList.
+func (in *DeviceActionList) DeepCopy() *DeviceActionList {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceActionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceActionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceActionSpec) DeepCopyInto(out *DeviceActionSpec) {
+	*out = *in
+	if in.Arguments != nil {
+		in, out := &in.Arguments, &out.Arguments
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceActionSpec.
+func (in *DeviceActionSpec) DeepCopy() *DeviceActionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceActionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceActionStatus) DeepCopyInto(out *DeviceActionStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Result != nil {
+		in, out := &in.Result, &out.Result
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceActionStatus.
+func (in *DeviceActionStatus) DeepCopy() *DeviceActionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceActionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceAdaptor) DeepCopyInto(out *DeviceAdaptor) {
 	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
 		*out = new(runtime.RawExtension)
@@ -121,6 +240,50 @@ func (in *DeviceLinkList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceLinkReconnectPolicy) DeepCopyInto(out *DeviceLinkReconnectPolicy) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceLinkReconnectPolicy.
+func (in *DeviceLinkReconnectPolicy) DeepCopy() *DeviceLinkReconnectPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceLinkReconnectPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceLinkReconnectionStatus) DeepCopyInto(out *DeviceLinkReconnectionStatus) {
+	*out = *in
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceLinkReconnectionStatus.
+func (in *DeviceLinkReconnectionStatus) DeepCopy() *DeviceLinkReconnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceLinkReconnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceLinkReference) DeepCopyInto(out *DeviceLinkReference) {
 	*out = *in
@@ -145,6 +308,18 @@ func (in *DeviceLinkReferenceConfigMapSource) DeepCopyInto(out *DeviceLinkRefere
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Optional != nil {
+		in, out := &in.Optional, &out.Optional
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultValues != nil {
+		in, out := &in.DefaultValues, &out.DefaultValues
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceLinkReferenceConfigMapSource.
@@ -184,7 +359,7 @@ func (in *DeviceLinkReferenceDownwardAPISourceItem) DeepCopyInto(out *DeviceLink
 	*out = *in
 	if in.FieldRef != nil {
 		in, out := &in.FieldRef, &out.FieldRef
-		*out = new(v1.ObjectFieldSelector)
+		*out = new(corev1.ObjectFieldSelector)
 		**out = **in
 	}
 }
@@ -222,6 +397,18 @@ func (in *DeviceLinkReferenceSecretSource) DeepCopyInto(out *DeviceLinkReference
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Optional != nil {
+		in, out := &in.Optional, &out.Optional
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultValues != nil {
+		in, out := &in.DefaultValues, &out.DefaultValues
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceLinkReferenceSecretSource.
@@ -264,6 +451,41 @@ func (in *DeviceLinkReferenceSource) DeepCopy() *DeviceLinkReferenceSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceLinkSendPolicy) DeepCopyInto(out *DeviceLinkSendPolicy) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceLinkSendPolicy.
+func (in *DeviceLinkSendPolicy) DeepCopy() *DeviceLinkSendPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceLinkSendPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceLinkSpec) DeepCopyInto(out *DeviceLinkSpec) {
 	*out = *in
@@ -276,6 +498,16 @@ func (in *DeviceLinkSpec) DeepCopyInto(out *DeviceLinkSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SendPolicy != nil {
+		in, out := &in.SendPolicy, &out.SendPolicy
+		*out = new(DeviceLinkSendPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReconnectPolicy != nil {
+		in, out := &in.ReconnectPolicy, &out.ReconnectPolicy
+		*out = new(DeviceLinkReconnectPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Template.DeepCopyInto(&out.Template)
 }
 
@@ -301,9 +533,14 @@ func (in *DeviceLinkStatus) DeepCopyInto(out *DeviceLinkStatus) {
 	}
 	if in.Model != nil {
 		in, out := &in.Model, &out.Model
-		*out = new(metav1.TypeMeta)
+		*out = new(v1.TypeMeta)
 		**out = **in
 	}
+	if in.Reconnection != nil {
+		in, out := &in.Reconnection, &out.Reconnection
+		*out = new(DeviceLinkReconnectionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceLinkStatus.
@@ -358,3 +595,109 @@ func (in *DeviceTemplateSpec) DeepCopy() *DeviceTemplateSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeAdaptor) DeepCopyInto(out *NodeAdaptor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAdaptor.
+func (in *NodeAdaptor) DeepCopy() *NodeAdaptor {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeAdaptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeAdaptor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeAdaptorList) DeepCopyInto(out *NodeAdaptorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeAdaptor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAdaptorList.
+func (in *NodeAdaptorList) DeepCopy() *NodeAdaptorList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeAdaptorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeAdaptorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeAdaptorStatus) DeepCopyInto(out *NodeAdaptorStatus) {
+	*out = *in
+	if in.Adaptors != nil {
+		in, out := &in.Adaptors, &out.Adaptors
+		*out = make([]RegisteredAdaptor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAdaptorStatus.
+func (in *NodeAdaptorStatus) DeepCopy() *NodeAdaptorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeAdaptorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegisteredAdaptor) DeepCopyInto(out *RegisteredAdaptor) {
+	*out = *in
+	if in.Models != nil {
+		in, out := &in.Models, &out.Models
+		*out = make([]v1.TypeMeta, len(*in))
+		copy(*out, *in)
+	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.RegistrationTime.DeepCopyInto(&out.RegistrationTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegisteredAdaptor.
+func (in *RegisteredAdaptor) DeepCopy() *RegisteredAdaptor {
+	if in == nil {
+		return nil
+	}
+	out := new(RegisteredAdaptor)
+	in.DeepCopyInto(out)
+	return out
+}
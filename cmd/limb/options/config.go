@@ -1,23 +1,36 @@
 package options
 
 import (
+	"time"
+
 	cliflag "k8s.io/component-base/cli/flag"
 )
 
 type Options struct {
-	MetricsAddr int
-	NodeName    string
+	MetricsAddr        int
+	NodeName           string
+	AdaptorGracePeriod time.Duration
+	HistorySize        int
+	HistoryAddr        string
+	HistoryFile        string
 }
 
 func (in *Options) Flags(fsName string) (nfs cliflag.NamedFlagSets) {
 	fs := nfs.FlagSet(fsName)
 	fs.IntVar(&in.MetricsAddr, "metrics-addr", in.MetricsAddr, "The port is used for serving prometheus metrics")
 	fs.StringVar(&in.NodeName, "node-name", in.NodeName, "The name of the node, using 'NODE_NAME' environment variable is the same")
+	fs.DurationVar(&in.AdaptorGracePeriod, "adaptor-grace-period", in.AdaptorGracePeriod, "The duration to wait for an adaptor to register again after its socket is removed, the related links are kept during this period")
+	fs.IntVar(&in.HistorySize, "history-size", in.HistorySize, "The number of status snapshots is kept for each device, 0 disables the status history")
+	fs.StringVar(&in.HistoryAddr, "history-addr", in.HistoryAddr, "The address is used for serving the status history query API, which is not authenticated, so it binds to the loopback interface by default")
+	fs.StringVar(&in.HistoryFile, "history-file", in.HistoryFile, "The file path is used for persisting the status history, keeps the history in memory only if blank")
 	return
 }
 
 func NewOptions() *Options {
 	return &Options{
-		MetricsAddr: 8080,
+		MetricsAddr:        8080,
+		AdaptorGracePeriod: 30 * time.Second,
+		HistorySize:        360,
+		HistoryAddr:        "127.0.0.1:8081",
 	}
 }
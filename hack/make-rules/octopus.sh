@@ -24,6 +24,9 @@ function generate() {
   octopus::controller_gen::generate \
     object:headerFile="${ROOT_DIR}/hack/boilerplate.go.txt" \
     paths="${CURR_DIR}/pkg/mqtt/api/..."
+  octopus::controller_gen::generate \
+    object:headerFile="${ROOT_DIR}/hack/boilerplate.go.txt" \
+    paths="${CURR_DIR}/pkg/adaptor/poll/api/..."
 
   octopus::log::info "generating protos"
   rm -f "${CURR_DIR}/pkg/adaptor/api/*/*.pb.go"
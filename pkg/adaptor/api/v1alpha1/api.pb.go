@@ -89,6 +89,10 @@ type RegisterRequest struct {
 	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
 	// Name of the unix socket the adaptor is listening on, it's in the form `*.sock`.
 	Endpoint string `protobuf:"bytes,3,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	// Models served by the adaptor, limb treats the adaptor as serving any model if it's empty.
+	Models []*v1.TypeMeta `protobuf:"bytes,4,rep,name=models,proto3" json:"models,omitempty"`
+	// Features supported by the adaptor, e.g. `action`.
+	Features []string `protobuf:"bytes,5,rep,name=features,proto3" json:"features,omitempty"`
 }
 
 func (m *RegisterRequest) Reset()      { *m = RegisterRequest{} }
@@ -144,8 +148,24 @@ func (m *RegisterRequest) GetEndpoint() string {
 	return ""
 }
 
+func (m *RegisterRequest) GetModels() []*v1.TypeMeta {
+	if m != nil {
+		return m.Models
+	}
+	return nil
+}
+
+func (m *RegisterRequest) GetFeatures() []string {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
 type ConnectRequestReferenceEntry struct {
 	Items map[string][]byte `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Default values of the items, which are used if the items are absent.
+	Defaults map[string][]byte `protobuf:"bytes,2,rep,name=defaults,proto3" json:"defaults,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *ConnectRequestReferenceEntry) Reset()      { *m = ConnectRequestReferenceEntry{} }
@@ -187,6 +207,13 @@ func (m *ConnectRequestReferenceEntry) GetItems() map[string][]byte {
 	return nil
 }
 
+func (m *ConnectRequestReferenceEntry) GetDefaults() map[string][]byte {
+	if m != nil {
+		return m.Defaults
+	}
+	return nil
+}
+
 // ConnectRequest is the request used during connection
 // and is used to send desired device data to an adaptor.
 type ConnectRequest struct {
@@ -196,6 +223,9 @@ type ConnectRequest struct {
 	Device []byte `protobuf:"bytes,2,opt,name=device,proto3" json:"device,omitempty"`
 	// References for the device, i.e: Secret, ConfigMap and Downward API.
 	References map[string]*ConnectRequestReferenceEntry `protobuf:"bytes,3,rep,name=references,proto3" json:"references,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Action for the device, the adaptor should execute the action on the connected device
+	// instead of configuring the device if it is specified.
+	Action *ConnectRequestAction `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
 }
 
 func (m *ConnectRequest) Reset()      { *m = ConnectRequest{} }
@@ -251,6 +281,77 @@ func (m *ConnectRequest) GetReferences() map[string]*ConnectRequestReferenceEntr
 	return nil
 }
 
+func (m *ConnectRequest) GetAction() *ConnectRequestAction {
+	if m != nil {
+		return m.Action
+	}
+	return nil
+}
+
+// ConnectRequestAction is used to call a one-shot action on the device,
+// i.e: resetting a counter, rebooting or running an OPC-UA method.
+type ConnectRequestAction struct {
+	// ID of the action, the adaptor must return it in the response for correlation.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Name of the action.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Arguments of the action, it's in form JSON bytes.
+	Arguments []byte `protobuf:"bytes,3,opt,name=arguments,proto3" json:"arguments,omitempty"`
+}
+
+func (m *ConnectRequestAction) Reset()      { *m = ConnectRequestAction{} }
+func (*ConnectRequestAction) ProtoMessage() {}
+func (*ConnectRequestAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
+}
+func (m *ConnectRequestAction) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ConnectRequestAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ConnectRequestAction.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ConnectRequestAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectRequestAction.Merge(m, src)
+}
+func (m *ConnectRequestAction) XXX_Size() int {
+	return m.Size()
+}
+func (m *ConnectRequestAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectRequestAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectRequestAction proto.InternalMessageInfo
+
+func (m *ConnectRequestAction) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ConnectRequestAction) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ConnectRequestAction) GetArguments() []byte {
+	if m != nil {
+		return m.Arguments
+	}
+	return nil
+}
+
 // ConnectResponse is the response used during connection
 // and is used to return observed device data to the limb.
 type ConnectResponse struct {
@@ -259,12 +360,14 @@ type ConnectResponse struct {
 	// The unhandled error message indicates that the connection cannot be interrupted
 	// and the user needs to choose to recreate or ignore it.
 	ErrorMessage string `protobuf:"bytes,2,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	// Result of the action, it's returned if the request specified an action.
+	Action *ConnectResponseAction `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
 }
 
 func (m *ConnectResponse) Reset()      { *m = ConnectResponse{} }
 func (*ConnectResponse) ProtoMessage() {}
 func (*ConnectResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{5}
 }
 func (m *ConnectResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -307,52 +410,136 @@ func (m *ConnectResponse) GetErrorMessage() string {
 	return ""
 }
 
+func (m *ConnectResponse) GetAction() *ConnectResponseAction {
+	if m != nil {
+		return m.Action
+	}
+	return nil
+}
+
+// ConnectResponseAction is used to return the result of an action to the limb.
+type ConnectResponseAction struct {
+	// ID of the requested action.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Result of the action, it's in form JSON bytes.
+	Result []byte `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	// The error message indicates that the action is failed,
+	// it doesn't interrupt the connection.
+	ErrorMessage string `protobuf:"bytes,3,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+}
+
+func (m *ConnectResponseAction) Reset()      { *m = ConnectResponseAction{} }
+func (*ConnectResponseAction) ProtoMessage() {}
+func (*ConnectResponseAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
+}
+func (m *ConnectResponseAction) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ConnectResponseAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ConnectResponseAction.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ConnectResponseAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectResponseAction.Merge(m, src)
+}
+func (m *ConnectResponseAction) XXX_Size() int {
+	return m.Size()
+}
+func (m *ConnectResponseAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectResponseAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectResponseAction proto.InternalMessageInfo
+
+func (m *ConnectResponseAction) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ConnectResponseAction) GetResult() []byte {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *ConnectResponseAction) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Empty)(nil), "v1alpha1.Empty")
 	proto.RegisterType((*RegisterRequest)(nil), "v1alpha1.RegisterRequest")
 	proto.RegisterType((*ConnectRequestReferenceEntry)(nil), "v1alpha1.ConnectRequestReferenceEntry")
+	proto.RegisterMapType((map[string][]byte)(nil), "v1alpha1.ConnectRequestReferenceEntry.DefaultsEntry")
 	proto.RegisterMapType((map[string][]byte)(nil), "v1alpha1.ConnectRequestReferenceEntry.ItemsEntry")
 	proto.RegisterType((*ConnectRequest)(nil), "v1alpha1.ConnectRequest")
 	proto.RegisterMapType((map[string]*ConnectRequestReferenceEntry)(nil), "v1alpha1.ConnectRequest.ReferencesEntry")
+	proto.RegisterType((*ConnectRequestAction)(nil), "v1alpha1.ConnectRequestAction")
 	proto.RegisterType((*ConnectResponse)(nil), "v1alpha1.ConnectResponse")
+	proto.RegisterType((*ConnectResponseAction)(nil), "v1alpha1.ConnectResponseAction")
 }
 
 func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
 
 var fileDescriptor_00212fb1f9d3bf1c = []byte{
-	// 512 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0xcf, 0x6e, 0xd3, 0x40,
-	0x10, 0xc6, 0xbd, 0x0d, 0x69, 0xd2, 0x49, 0x44, 0xd0, 0x0a, 0x21, 0xd7, 0x42, 0x56, 0x65, 0x21,
-	0x94, 0x0b, 0x6b, 0x12, 0x38, 0x44, 0x88, 0x13, 0xb4, 0xa2, 0x1c, 0x72, 0xb1, 0xb8, 0x71, 0xda,
-	0x24, 0x53, 0xc7, 0x4a, 0xbc, 0x6b, 0x76, 0x37, 0x96, 0x72, 0xe3, 0x11, 0x78, 0x03, 0xc4, 0xdb,
-	0xf4, 0xd8, 0x63, 0x8f, 0x34, 0x79, 0x11, 0xe4, 0xb5, 0xf3, 0x0f, 0xb5, 0x88, 0xdb, 0x7c, 0x33,
-	0xfb, 0xed, 0xce, 0x6f, 0x3c, 0x86, 0x13, 0x9e, 0x25, 0x2c, 0x53, 0xd2, 0x48, 0xda, 0xcc, 0x7b,
-	0x7c, 0x9e, 0x4d, 0x79, 0xcf, 0x7b, 0x15, 0x27, 0x66, 0xba, 0x18, 0xb1, 0xb1, 0x4c, 0xc3, 0x58,
-	0xc6, 0x32, 0xb4, 0x07, 0x46, 0x8b, 0x2b, 0xab, 0xac, 0xb0, 0x51, 0x69, 0xf4, 0xde, 0xce, 0x06,
-	0x9a, 0x25, 0x32, 0xe4, 0x59, 0x92, 0xf2, 0xf1, 0x34, 0x11, 0xa8, 0x96, 0x61, 0x36, 0x8b, 0x8b,
-	0x84, 0x0e, 0x53, 0x34, 0x3c, 0xcc, 0x7b, 0x61, 0x8c, 0x02, 0x15, 0x37, 0x38, 0x29, 0x5d, 0x41,
-	0x03, 0xea, 0x17, 0x69, 0x66, 0x96, 0xc1, 0x57, 0xe8, 0x44, 0x18, 0x27, 0xda, 0xa0, 0x8a, 0xf0,
-	0xdb, 0x02, 0xb5, 0xa1, 0x14, 0x1e, 0x09, 0x9e, 0xa2, 0x4b, 0xce, 0x48, 0xf7, 0x24, 0xb2, 0x31,
-	0x75, 0xa1, 0x91, 0xa3, 0xd2, 0x89, 0x14, 0xee, 0x91, 0x4d, 0x6f, 0x24, 0xf5, 0xa0, 0x89, 0x62,
-	0x92, 0xc9, 0x44, 0x18, 0xb7, 0x66, 0x4b, 0x5b, 0x1d, 0xfc, 0x22, 0xf0, 0xfc, 0xa3, 0x14, 0x02,
-	0xc7, 0xa6, 0xba, 0x3c, 0xc2, 0x2b, 0x54, 0x28, 0xc6, 0x78, 0x21, 0x8c, 0x5a, 0xd2, 0x4f, 0x50,
-	0x4f, 0x0c, 0xa6, 0xda, 0x25, 0x67, 0xb5, 0x6e, 0xab, 0xdf, 0x63, 0x9b, 0x29, 0xb0, 0x7f, 0xd9,
-	0xd8, 0xe7, 0xc2, 0x63, 0xc3, 0xa8, 0xf4, 0x7b, 0x03, 0x80, 0x5d, 0x92, 0x3e, 0x81, 0xda, 0x0c,
-	0x97, 0x15, 0x40, 0x11, 0xd2, 0xa7, 0x50, 0xcf, 0xf9, 0x7c, 0x81, 0xb6, 0xfb, 0x76, 0x54, 0x8a,
-	0x77, 0x47, 0x03, 0x12, 0xfc, 0x3c, 0x82, 0xc7, 0x87, 0x8f, 0xd1, 0x73, 0xa8, 0xa7, 0x72, 0x82,
-	0x73, 0x7b, 0x41, 0xab, 0xcf, 0x58, 0x39, 0x62, 0xb6, 0x3f, 0x62, 0x96, 0xcd, 0xe2, 0x22, 0xa1,
-	0x59, 0x31, 0x62, 0x96, 0xf7, 0xd8, 0x97, 0x65, 0x86, 0x43, 0x34, 0x3c, 0x2a, 0xcd, 0xf4, 0x19,
-	0x1c, 0x4f, 0x30, 0x4f, 0xc6, 0x9b, 0x37, 0x2b, 0x45, 0x2f, 0x01, 0xd4, 0x06, 0x47, 0xbb, 0x35,
-	0x0b, 0xde, 0x7d, 0x08, 0x9c, 0x6d, 0xc9, 0x2b, 0xde, 0x3d, 0xaf, 0x87, 0xc5, 0xb7, 0x3b, 0x28,
-	0xdf, 0x43, 0xfe, 0x7e, 0x9f, 0xbc, 0xd5, 0x7f, 0xf9, 0x7f, 0x23, 0xde, 0x9f, 0xd0, 0x10, 0x3a,
-	0xdb, 0xa3, 0x3a, 0x93, 0x42, 0xe3, 0x1e, 0x1b, 0x39, 0x60, 0x0b, 0xa0, 0x8d, 0x4a, 0x49, 0x35,
-	0x44, 0xad, 0x79, 0x8c, 0xd5, 0xae, 0x1c, 0xe4, 0xfa, 0x97, 0xd0, 0x2e, 0x37, 0x4e, 0x71, 0x53,
-	0x2c, 0xd0, 0x00, 0x9a, 0x9b, 0x0d, 0xa4, 0xa7, 0xbb, 0xee, 0xfe, 0xda, 0x4a, 0xaf, 0xb3, 0x2b,
-	0x95, 0x9b, 0xeb, 0xf4, 0x23, 0x80, 0xaa, 0xb1, 0xe2, 0x9e, 0x73, 0x68, 0x54, 0x8a, 0xba, 0x0f,
-	0x41, 0x7a, 0xa7, 0xf7, 0x54, 0x4a, 0xa6, 0xc0, 0xe9, 0x92, 0xd7, 0xe4, 0xc3, 0x8b, 0xeb, 0x3b,
-	0x9f, 0xdc, 0xde, 0xf9, 0xce, 0xf7, 0x95, 0x4f, 0xae, 0x57, 0x3e, 0xb9, 0x59, 0xf9, 0xe4, 0xf7,
-	0xca, 0x27, 0x3f, 0xd6, 0xbe, 0x73, 0xb3, 0xf6, 0x9d, 0xdb, 0xb5, 0xef, 0x8c, 0x8e, 0xed, 0x5f,
-	0xf4, 0xe6, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x55, 0x5a, 0xff, 0xb1, 0xc1, 0x03, 0x00, 0x00,
+	// 665 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x4d, 0x6f, 0xd3, 0x4a,
+	0x14, 0xcd, 0x38, 0x4d, 0x9a, 0xde, 0xe6, 0xb5, 0x4f, 0xa3, 0xbe, 0xca, 0xb5, 0x2a, 0xbf, 0xca,
+	0x42, 0x28, 0x1b, 0x26, 0x24, 0x54, 0x10, 0x01, 0x1b, 0xa0, 0x85, 0xb2, 0xa8, 0x84, 0x2c, 0x16,
+	0x6c, 0xa7, 0xc9, 0x8d, 0x6b, 0x25, 0xfe, 0x60, 0x66, 0x1c, 0x29, 0x3b, 0x56, 0xac, 0xf9, 0x29,
+	0xfc, 0x03, 0xb6, 0x5d, 0x76, 0x47, 0x97, 0x34, 0xfd, 0x23, 0xc8, 0x63, 0x3b, 0x1f, 0x34, 0x41,
+	0xed, 0x6e, 0xce, 0xf5, 0x9c, 0x73, 0xcf, 0x1c, 0xcf, 0x5c, 0xd8, 0xe0, 0xb1, 0xcf, 0x62, 0x11,
+	0xa9, 0x88, 0xd6, 0x46, 0x2d, 0x3e, 0x8c, 0xcf, 0x79, 0xcb, 0x7a, 0xe4, 0xf9, 0xea, 0x3c, 0x39,
+	0x63, 0xdd, 0x28, 0x68, 0x7a, 0x91, 0x17, 0x35, 0xf5, 0x86, 0xb3, 0xa4, 0xaf, 0x91, 0x06, 0x7a,
+	0x95, 0x11, 0xad, 0xc3, 0x41, 0x47, 0x32, 0x3f, 0x6a, 0xf2, 0xd8, 0x0f, 0x78, 0xf7, 0xdc, 0x0f,
+	0x51, 0x8c, 0x9b, 0xf1, 0xc0, 0x4b, 0x0b, 0xb2, 0x19, 0xa0, 0xe2, 0xcd, 0x51, 0xab, 0xe9, 0x61,
+	0x88, 0x82, 0x2b, 0xec, 0x65, 0x2c, 0x67, 0x1d, 0x2a, 0xc7, 0x41, 0xac, 0xc6, 0xce, 0x0f, 0x02,
+	0xdb, 0x2e, 0x7a, 0xbe, 0x54, 0x28, 0x5c, 0xfc, 0x9c, 0xa0, 0x54, 0x94, 0xc2, 0x5a, 0xc8, 0x03,
+	0x34, 0xc9, 0x01, 0x69, 0x6c, 0xb8, 0x7a, 0x4d, 0x4d, 0x58, 0x1f, 0xa1, 0x90, 0x7e, 0x14, 0x9a,
+	0x86, 0x2e, 0x17, 0x90, 0x5a, 0x50, 0xc3, 0xb0, 0x17, 0x47, 0x7e, 0xa8, 0xcc, 0xb2, 0xfe, 0x34,
+	0xc5, 0xf4, 0x2d, 0x54, 0x83, 0xa8, 0x87, 0x43, 0x69, 0xae, 0x1d, 0x94, 0x1b, 0x9b, 0x6d, 0xc6,
+	0x32, 0xb7, 0x6c, 0xde, 0x2d, 0x8b, 0x07, 0x5e, 0x5a, 0x90, 0x2c, 0x75, 0xcb, 0x46, 0x2d, 0xf6,
+	0x71, 0x1c, 0xe3, 0x29, 0x2a, 0xee, 0xe6, 0xec, 0xb4, 0x47, 0x1f, 0xb9, 0x4a, 0x04, 0x4a, 0xb3,
+	0x72, 0x50, 0x4e, 0x7b, 0x14, 0xd8, 0xf9, 0x6e, 0xc0, 0xfe, 0x9b, 0x28, 0x0c, 0xb1, 0xab, 0xf2,
+	0x03, 0xb8, 0xd8, 0x47, 0x81, 0x61, 0x17, 0x8f, 0x43, 0x25, 0xc6, 0xf4, 0x1d, 0x54, 0x7c, 0x85,
+	0x81, 0x34, 0x89, 0xf6, 0xd0, 0x62, 0x45, 0xd4, 0xec, 0x6f, 0x34, 0xf6, 0x3e, 0xe5, 0xe8, 0xa5,
+	0x9b, 0xf1, 0xe9, 0x07, 0xa8, 0xf5, 0xb0, 0xcf, 0x93, 0xa1, 0x92, 0xa6, 0xa1, 0xb5, 0x0e, 0xef,
+	0xa8, 0x75, 0x94, 0xd3, 0x32, 0xb9, 0xa9, 0x8a, 0xd5, 0x01, 0x98, 0xb5, 0xa1, 0xff, 0x42, 0x79,
+	0x80, 0xe3, 0x3c, 0xf6, 0x74, 0x49, 0x77, 0xa0, 0x32, 0xe2, 0xc3, 0x04, 0x75, 0xe6, 0x75, 0x37,
+	0x03, 0xcf, 0x8d, 0x0e, 0xb1, 0x5e, 0xc0, 0x3f, 0x0b, 0xa2, 0xf7, 0x21, 0x3b, 0x3f, 0x0d, 0xd8,
+	0x5a, 0xf4, 0x4b, 0x8f, 0xa0, 0xa2, 0xb3, 0xd6, 0x02, 0xf7, 0xff, 0x51, 0x19, 0x99, 0xee, 0x42,
+	0xb5, 0x87, 0x23, 0xbf, 0x5b, 0xf4, 0xcc, 0x11, 0x3d, 0x01, 0x10, 0x45, 0x22, 0xd2, 0x2c, 0xeb,
+	0xec, 0x1a, 0xab, 0xb2, 0x63, 0xd3, 0xf0, 0xf2, 0xbc, 0xe6, 0xb8, 0xf4, 0x29, 0x54, 0x79, 0x57,
+	0xa5, 0xd7, 0x70, 0x4d, 0x1b, 0xb5, 0x57, 0xa9, 0xbc, 0xd2, 0xbb, 0xdc, 0x7c, 0xb7, 0x85, 0xe9,
+	0x35, 0x5f, 0x90, 0x5d, 0x92, 0xd8, 0xcb, 0xf9, 0xc4, 0x36, 0xdb, 0x0f, 0xef, 0xf6, 0x77, 0xe7,
+	0x93, 0xfd, 0x04, 0x3b, 0xcb, 0x6c, 0xd0, 0x2d, 0x30, 0xfc, 0x5e, 0xde, 0xca, 0xf0, 0x7b, 0xd3,
+	0x27, 0x66, 0xcc, 0x3d, 0xb1, 0x7d, 0xd8, 0xe0, 0xc2, 0x4b, 0x02, 0x0c, 0x95, 0xd4, 0x2f, 0xa9,
+	0xee, 0xce, 0x0a, 0xce, 0x57, 0x02, 0xdb, 0x53, 0x69, 0x19, 0x47, 0xa1, 0xc4, 0xb9, 0xb8, 0xc9,
+	0x42, 0xdc, 0x0e, 0xd4, 0x51, 0x88, 0x48, 0x9c, 0xa2, 0x94, 0xdc, 0x2b, 0xba, 0x2c, 0xd4, 0xe8,
+	0xb3, 0x69, 0x90, 0x65, 0x7d, 0xd8, 0xff, 0x97, 0x1c, 0x36, 0x6b, 0xb3, 0x98, 0xa4, 0xd3, 0x85,
+	0xff, 0x96, 0x6e, 0xb8, 0x75, 0xc6, 0x5d, 0xa8, 0x0a, 0x94, 0xc9, 0x50, 0x15, 0x97, 0x21, 0x43,
+	0xb7, 0xdc, 0x95, 0x6f, 0xbb, 0x6b, 0x9f, 0x40, 0x3d, 0x9b, 0x4a, 0x82, 0x6b, 0xed, 0x0e, 0xd4,
+	0x8a, 0x29, 0x45, 0xf7, 0x66, 0x4e, 0xff, 0x98, 0x5c, 0xd6, 0xf6, 0xec, 0x53, 0x36, 0xde, 0x4a,
+	0x6d, 0x17, 0x20, 0xb7, 0x9b, 0xea, 0x1c, 0xc1, 0x7a, 0x8e, 0xa8, 0xb9, 0xea, 0xef, 0x5a, 0x7b,
+	0x2b, 0xa3, 0x70, 0x4a, 0x0d, 0xf2, 0x98, 0xbc, 0x7e, 0x70, 0x71, 0x6d, 0x93, 0xab, 0x6b, 0xbb,
+	0xf4, 0x65, 0x62, 0x93, 0x8b, 0x89, 0x4d, 0x2e, 0x27, 0x36, 0xf9, 0x35, 0xb1, 0xc9, 0xb7, 0x1b,
+	0xbb, 0x74, 0x79, 0x63, 0x97, 0xae, 0x6e, 0xec, 0xd2, 0x59, 0x55, 0x8f, 0xda, 0x27, 0xbf, 0x07,
+	0x00, 0xcd, 0x1a, 0xa0, 0x6b, 0xe6, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -586,6 +773,29 @@ func (m *RegisterRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Features) > 0 {
+		for iNdEx := len(m.Features) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Features[iNdEx])
+			copy(dAtA[i:], m.Features[iNdEx])
+			i = encodeVarintApi(dAtA, i, uint64(len(m.Features[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.Models) > 0 {
+		for iNdEx := len(m.Models) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Models[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if len(m.Endpoint) > 0 {
 		i -= len(m.Endpoint)
 		copy(dAtA[i:], m.Endpoint)
@@ -630,6 +840,27 @@ func (m *ConnectRequestReferenceEntry) MarshalToSizedBuffer(dAtA []byte) (int, e
 	_ = i
 	var l int
 	_ = l
+	if len(m.Defaults) > 0 {
+		for k := range m.Defaults {
+			v := m.Defaults[k]
+			baseI := i
+			if len(v) > 0 {
+				i -= len(v)
+				copy(dAtA[i:], v)
+				i = encodeVarintApi(dAtA, i, uint64(len(v)))
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintApi(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintApi(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
 	if len(m.Items) > 0 {
 		for k := range m.Items {
 			v := m.Items[k]
@@ -674,6 +905,18 @@ func (m *ConnectRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Action != nil {
+		{
+			size, err := m.Action.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
 	if len(m.References) > 0 {
 		for k := range m.References {
 			v := m.References[k]
@@ -722,6 +965,50 @@ func (m *ConnectRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ConnectRequestAction) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ConnectRequestAction) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ConnectRequestAction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Arguments) > 0 {
+		i -= len(m.Arguments)
+		copy(dAtA[i:], m.Arguments)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Arguments)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *ConnectResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -742,6 +1029,18 @@ func (m *ConnectResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Action != nil {
+		{
+			size, err := m.Action.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.ErrorMessage) > 0 {
 		i -= len(m.ErrorMessage)
 		copy(dAtA[i:], m.ErrorMessage)
@@ -759,6 +1058,50 @@ func (m *ConnectResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ConnectResponseAction) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ConnectResponseAction) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ConnectResponseAction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ErrorMessage) > 0 {
+		i -= len(m.ErrorMessage)
+		copy(dAtA[i:], m.ErrorMessage)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.ErrorMessage)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Result) > 0 {
+		i -= len(m.Result)
+		copy(dAtA[i:], m.Result)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Result)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintApi(dAtA []byte, offset int, v uint64) int {
 	offset -= sovApi(v)
 	base := offset
@@ -797,6 +1140,18 @@ func (m *RegisterRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovApi(uint64(l))
 	}
+	if len(m.Models) > 0 {
+		for _, e := range m.Models {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if len(m.Features) > 0 {
+		for _, s := range m.Features {
+			l = len(s)
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -818,6 +1173,18 @@ func (m *ConnectRequestReferenceEntry) Size() (n int) {
 			n += mapEntrySize + 1 + sovApi(uint64(mapEntrySize))
 		}
 	}
+	if len(m.Defaults) > 0 {
+		for k, v := range m.Defaults {
+			_ = k
+			_ = v
+			l = 0
+			if len(v) > 0 {
+				l = 1 + len(v) + sovApi(uint64(len(v)))
+			}
+			mapEntrySize := 1 + len(k) + sovApi(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovApi(uint64(mapEntrySize))
+		}
+	}
 	return n
 }
 
@@ -848,6 +1215,31 @@ func (m *ConnectRequest) Size() (n int) {
 			n += mapEntrySize + 1 + sovApi(uint64(mapEntrySize))
 		}
 	}
+	if m.Action != nil {
+		l = m.Action.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+
+func (m *ConnectRequestAction) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Arguments)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
 	return n
 }
 
@@ -865,18 +1257,43 @@ func (m *ConnectResponse) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovApi(uint64(l))
 	}
+	if m.Action != nil {
+		l = m.Action.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
 	return n
 }
 
-func sovApi(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozApi(x uint64) (n int) {
-	return sovApi(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *Empty) String() string {
-	if this == nil {
-		return "nil"
+func (m *ConnectResponseAction) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Result)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.ErrorMessage)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+
+func sovApi(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozApi(x uint64) (n int) {
+	return sovApi(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *Empty) String() string {
+	if this == nil {
+		return "nil"
 	}
 	s := strings.Join([]string{`&Empty{`,
 		`}`,
@@ -887,10 +1304,17 @@ func (this *RegisterRequest) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForModels := "[]*TypeMeta{"
+	for _, f := range this.Models {
+		repeatedStringForModels += strings.Replace(fmt.Sprintf("%v", f), "TypeMeta", "v1.TypeMeta", 1) + ","
+	}
+	repeatedStringForModels += "}"
 	s := strings.Join([]string{`&RegisterRequest{`,
 		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
 		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
 		`Endpoint:` + fmt.Sprintf("%v", this.Endpoint) + `,`,
+		`Models:` + repeatedStringForModels + `,`,
+		`Features:` + fmt.Sprintf("%v", this.Features) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -909,8 +1333,19 @@ func (this *ConnectRequestReferenceEntry) String() string {
 		mapStringForItems += fmt.Sprintf("%v: %v,", k, this.Items[k])
 	}
 	mapStringForItems += "}"
+	keysForDefaults := make([]string, 0, len(this.Defaults))
+	for k, _ := range this.Defaults {
+		keysForDefaults = append(keysForDefaults, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForDefaults)
+	mapStringForDefaults := "map[string][]byte{"
+	for _, k := range keysForDefaults {
+		mapStringForDefaults += fmt.Sprintf("%v: %v,", k, this.Defaults[k])
+	}
+	mapStringForDefaults += "}"
 	s := strings.Join([]string{`&ConnectRequestReferenceEntry{`,
 		`Items:` + mapStringForItems + `,`,
+		`Defaults:` + mapStringForDefaults + `,`,
 		`}`,
 	}, "")
 	return s
@@ -933,6 +1368,19 @@ func (this *ConnectRequest) String() string {
 		`Model:` + strings.Replace(fmt.Sprintf("%v", this.Model), "TypeMeta", "v1.TypeMeta", 1) + `,`,
 		`Device:` + fmt.Sprintf("%v", this.Device) + `,`,
 		`References:` + mapStringForReferences + `,`,
+		`Action:` + strings.Replace(this.Action.String(), "ConnectRequestAction", "ConnectRequestAction", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ConnectRequestAction) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ConnectRequestAction{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Arguments:` + fmt.Sprintf("%v", this.Arguments) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -944,6 +1392,19 @@ func (this *ConnectResponse) String() string {
 	s := strings.Join([]string{`&ConnectResponse{`,
 		`Device:` + fmt.Sprintf("%v", this.Device) + `,`,
 		`ErrorMessage:` + fmt.Sprintf("%v", this.ErrorMessage) + `,`,
+		`Action:` + strings.Replace(this.Action.String(), "ConnectResponseAction", "ConnectResponseAction", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ConnectResponseAction) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ConnectResponseAction{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Result:` + fmt.Sprintf("%v", this.Result) + `,`,
+		`ErrorMessage:` + fmt.Sprintf("%v", this.ErrorMessage) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1134,6 +1595,72 @@ func (m *RegisterRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.Endpoint = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Models", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Models = append(m.Models, &v1.TypeMeta{})
+			if err := m.Models[len(m.Models)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Features", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Features = append(m.Features, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApi(dAtA[iNdEx:])
@@ -1315,6 +1842,134 @@ func (m *ConnectRequestReferenceEntry) Unmarshal(dAtA []byte) error {
 			}
 			m.Items[mapkey] = mapvalue
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Defaults", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Defaults == nil {
+				m.Defaults = make(map[string][]byte)
+			}
+			var mapkey string
+			mapvalue := []byte{}
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowApi
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowApi
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthApi
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthApi
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapbyteLen uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowApi
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapbyteLen |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intMapbyteLen := int(mapbyteLen)
+					if intMapbyteLen < 0 {
+						return ErrInvalidLengthApi
+					}
+					postbytesIndex := iNdEx + intMapbyteLen
+					if postbytesIndex < 0 {
+						return ErrInvalidLengthApi
+					}
+					if postbytesIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = make([]byte, mapbyteLen)
+					copy(mapvalue, dAtA[iNdEx:postbytesIndex])
+					iNdEx = postbytesIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipApi(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthApi
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Defaults[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApi(dAtA[iNdEx:])
@@ -1567,16 +2222,203 @@ func (m *ConnectRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.References[mapkey] = mapvalue
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipApi(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Action", wireType)
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthApi
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) < 0 {
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Action == nil {
+				m.Action = &ConnectRequestAction{}
+			}
+			if err := m.Action.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ConnectRequestAction) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConnectRequestAction: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConnectRequestAction: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Arguments", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Arguments = append(m.Arguments[:0], dAtA[iNdEx:postIndex]...)
+			if m.Arguments == nil {
+				m.Arguments = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) < 0 {
 				return ErrInvalidLengthApi
 			}
 			if (iNdEx + skippy) > l {
@@ -1686,6 +2528,193 @@ func (m *ConnectResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.ErrorMessage = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Action", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Action == nil {
+				m.Action = &ConnectResponseAction{}
+			}
+			if err := m.Action.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ConnectResponseAction) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConnectResponseAction: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConnectResponseAction: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Result", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Result = append(m.Result[:0], dAtA[iNdEx:postIndex]...)
+			if m.Result == nil {
+				m.Result = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorMessage", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ErrorMessage = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApi(dAtA[iNdEx:])
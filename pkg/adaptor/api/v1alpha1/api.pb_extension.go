@@ -4,20 +4,21 @@
 type ReferencesHandler map[string]*ConnectRequestReferenceEntry
 
 // GetData returns the data of specified name and itemName,
-// it's always return nil if the data bytes is not existed or empty.
+// it returns the default value if the data bytes is not existed or empty,
+// and it's always return nil if the default value is not existed either.
 func (h ReferencesHandler) GetData(name, itemName string) []byte {
 	if len(h) == 0 {
 		return nil
 	}
 
 	var refItems, refExist = h[name]
-	if !refExist || len(refItems.Items) == 0 {
+	if !refExist || refItems == nil {
 		return nil
 	}
 
-	var refItem, refItemExist = refItems.Items[itemName]
-	if !refItemExist {
-		return nil
+	var refItem = refItems.Items[itemName]
+	if len(refItem) == 0 {
+		refItem = refItems.Defaults[itemName]
 	}
 
 	if len(refItem) == 0 {
@@ -35,11 +36,18 @@ func (h ReferencesHandler) ToDataMap() map[string]map[string][]byte {
 
 	var refMap = make(map[string]map[string][]byte, len(h))
 	for refKey, refValue := range h {
-		if refValue == nil || len(refValue.Items) == 0 {
+		if refValue == nil || (len(refValue.Items) == 0 && len(refValue.Defaults) == 0) {
 			continue
 		}
-		var refItems = make(map[string][]byte, len(refValue.Items))
+		var refItems = make(map[string][]byte, len(refValue.Items)+len(refValue.Defaults))
+		for refItemKey, refItemValue := range refValue.Defaults {
+			refItems[refItemKey] = refItemValue
+		}
 		for refItemKey, refItemValue := range refValue.Items {
+			// keeps the default value if the item is empty.
+			if _, hasDefault := refItems[refItemKey]; hasDefault && len(refItemValue) == 0 {
+				continue
+			}
 			refItems[refItemKey] = refItemValue
 		}
 		refMap[refKey] = refItems
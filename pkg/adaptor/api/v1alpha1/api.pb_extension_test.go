@@ -0,0 +1,93 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferencesHandler_GetData(t *testing.T) {
+	var handler = ReferencesHandler{
+		"credential": {
+			Items: map[string][]byte{
+				"username": []byte("admin"),
+				"password": {},
+			},
+			Defaults: map[string][]byte{
+				"password": []byte("default"),
+				"token":    []byte("token"),
+			},
+		},
+		"absent": {
+			Defaults: map[string][]byte{
+				"username": []byte("guest"),
+			},
+		},
+	}
+
+	var testCases = []struct {
+		name     string
+		given    [2]string
+		expected []byte
+	}{
+		{
+			name:     "existing item",
+			given:    [2]string{"credential", "username"},
+			expected: []byte("admin"),
+		},
+		{
+			name:     "empty item falls back to default",
+			given:    [2]string{"credential", "password"},
+			expected: []byte("default"),
+		},
+		{
+			name:     "absent item falls back to default",
+			given:    [2]string{"credential", "token"},
+			expected: []byte("token"),
+		},
+		{
+			name:     "absent optional reference falls back to default",
+			given:    [2]string{"absent", "username"},
+			expected: []byte("guest"),
+		},
+		{
+			name:     "absent item without default",
+			given:    [2]string{"credential", "unknown"},
+			expected: nil,
+		},
+		{
+			name:     "absent reference",
+			given:    [2]string{"unknown", "username"},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = handler.GetData(tc.given[0], tc.given[1])
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
+func TestReferencesHandler_ToDataMap(t *testing.T) {
+	var handler = ReferencesHandler{
+		"credential": {
+			Items: map[string][]byte{
+				"username": []byte("admin"),
+				"password": {},
+			},
+			Defaults: map[string][]byte{
+				"username": []byte("guest"),
+				"password": []byte("default"),
+			},
+		},
+		"empty": {},
+	}
+
+	var expected = map[string]map[string][]byte{
+		"credential": {
+			"username": []byte("admin"),
+			"password": []byte("default"),
+		},
+	}
+	assert.Equal(t, expected, handler.ToDataMap())
+}
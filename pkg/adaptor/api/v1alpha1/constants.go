@@ -12,6 +12,9 @@
 
 	// LimbSocket is the path of the Limb registry socket
 	LimbSocket = AdaptorPath + "limb" + SocketSuffix
+
+	// FeatureAction is the feature of adaptor which is able to execute the actions of device
+	FeatureAction = "action"
 )
 
 var SupportedVersions = []string{Version}
@@ -0,0 +1,56 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/rancher/octopus/pkg/adaptor/socket/handler"
+	"github.com/rancher/octopus/pkg/mqtt"
+)
+
+// Executor executes the values of command, and returns the results of properties.
+type Executor func(values map[string]interface{}) mqtt.CommandReply
+
+// Receive decodes the values of command message, and executes them in another goroutine to avoid blocking the MQTT client,
+// the results are published as the reply of message.
+// The endpoint is the socket endpoint of adaptor, which is cleaned up if the executor panics.
+func Receive(log logr.Logger, endpoint string, msg mqtt.SubscribeMessage, execute Executor) {
+	var values map[string]interface{}
+	if err := msg.Decode(&values); err != nil {
+		log.Error(err, "Error decoding command", "topic", msg.Topic)
+		return
+	}
+
+	go func() {
+		defer runtime.HandleCrash(handler.NewPanicsCleanupSocketHandler(endpoint))
+
+		var reply = execute(values)
+		if err := msg.Reply(reply); err != nil {
+			log.Error(err, "Error replying command", "topic", msg.Topic)
+		}
+	}()
+}
+
+// FormatValue formats the value of command as the string value of property,
+// the numbers are formatted without exponent, e.g. 1000000 rather than 1e+06.
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		return v.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,57 @@
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatValue(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		given    interface{}
+		expected string
+	}{
+		{
+			name:     "string",
+			given:    "on",
+			expected: "on",
+		},
+		{
+			name:     "large integer",
+			given:    float64(1000000),
+			expected: "1000000",
+		},
+		{
+			name:     "small float",
+			given:    0.0000125,
+			expected: "0.0000125",
+		},
+		{
+			name:     "negative float",
+			given:    -23.5,
+			expected: "-23.5",
+		},
+		{
+			name:     "float32",
+			given:    float32(0.1),
+			expected: "0.1",
+		},
+		{
+			name:     "JSON number",
+			given:    json.Number("1e6"),
+			expected: "1000000",
+		},
+		{
+			name:     "boolean",
+			given:    true,
+			expected: "true",
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = FormatValue(tc.given)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
@@ -0,0 +1,22 @@
+package metricsflag
+
+import (
+	flag "github.com/spf13/pflag"
+)
+
+type metricsT struct {
+	address string
+}
+
+var metrics = metricsT{}
+
+// AddFlags registers this package's flags on arbitrary FlagSets, such that they point to the
+// same value as the global flags.
+func AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&metrics.address, "metrics-addr", metrics.address, "The address the metrics endpoint binds to, the endpoint is disabled if blank.")
+}
+
+// GetAddress returns the address of metrics endpoint.
+func GetAddress() string {
+	return metrics.address
+}
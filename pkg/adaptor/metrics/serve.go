@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/rancher/octopus/pkg/adaptor/log"
+	"github.com/rancher/octopus/pkg/metrics"
+)
+
+// Serve provides the metrics of adaptor on `address`, and is affected by `stop` chan,
+// it does nothing if the `address` is blank.
+func Serve(address string, stop <-chan struct{}) error {
+	if address == "" {
+		return nil
+	}
+
+	var registry = prometheus.NewRegistry()
+	if err := metrics.RegisterMQTTMetrics(registry); err != nil {
+		return errors.Wrap(err, "failed to register MQTT metrics")
+	}
+
+	var mux = http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	var srv = &http.Server{Addr: address, Handler: mux}
+	go func() {
+		<-stop
+		var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}()
+
+	log.Info("Serving metrics", "address", address)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return errors.Wrap(err, "failed to serve metrics")
+	}
+	return nil
+}
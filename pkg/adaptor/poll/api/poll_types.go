@@ -0,0 +1,96 @@
+package api
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PollSyncMode defines the mode of synchronizing the polled values to limb.
+// Interval: Synchronizes after each polling.
+// OnChange: Synchronizes only when any value changes beyond its deadband, or the max silence is reached.
+// +kubebuilder:validation:Enum=Interval;OnChange
+type PollSyncMode string
+
+const (
+	PollSyncModeInterval PollSyncMode = "Interval"
+	PollSyncModeOnChange PollSyncMode = "OnChange"
+)
+
+// PollSyncOptions defines how to synchronize the polled values to limb.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type PollSyncOptions struct {
+	// Specifies the mode of synchronizing.
+	// The default value is "Interval".
+	// +kubebuilder:default="Interval"
+	// +optional
+	Mode PollSyncMode `json:"mode,omitempty"`
+
+	// Specifies the max amount of silence in OnChange mode,
+	// the values are synchronized as a heartbeat if nothing changes within the max silence.
+	// The default value is "5m".
+	// +kubebuilder:default="5m"
+	// +optional
+	MaxSilence *metav1.Duration `json:"maxSilence,omitempty"`
+}
+
+// GetMode returns the mode of synchronizing.
+func (in *PollSyncOptions) GetMode() PollSyncMode {
+	if in != nil && in.Mode != "" {
+		return in.Mode
+	}
+	return PollSyncModeInterval
+}
+
+// GetMaxSilence returns the max amount of silence in OnChange mode.
+func (in *PollSyncOptions) GetMaxSilence() time.Duration {
+	if in != nil && in.MaxSilence != nil {
+		if duration := in.MaxSilence.Duration; duration > 0 {
+			return duration
+		}
+	}
+	return 5 * time.Minute
+}
+
+// PollDeadband defines the deadband of a numeric value,
+// the value is treated as changed if it exceeds any of the absolute and percent deadband,
+// the non-numeric value is treated as changed if it is different.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type PollDeadband struct {
+	// Specifies the absolute deadband, which is in form of float string.
+	// +kubebuilder:validation:Pattern="^[0-9]+(\\.[0-9]+)?$"
+	// +optional
+	Absolute string `json:"absolute,omitempty"`
+
+	// Specifies the percent deadband relative to the last synchronized value, which is in form of float string,
+	// e.g. "5" means 5%.
+	// +kubebuilder:validation:Pattern="^[0-9]+(\\.[0-9]+)?$"
+	// +optional
+	Percent string `json:"percent,omitempty"`
+}
+
+// PollPropertyOptions defines the polling of a property.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type PollPropertyOptions struct {
+	// Specifies the interval of polling the property,
+	// the sync interval of device is used if it is not specified.
+	// +optional
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+
+	// Specifies the deadband of the property value in OnChange sync mode.
+	// +optional
+	Deadband *PollDeadband `json:"deadband,omitempty"`
+}
+
+// GetPollInterval returns the interval of polling the property, or the given default interval.
+func (in *PollPropertyOptions) GetPollInterval(defaultInterval time.Duration) time.Duration {
+	if in != nil && in.PollInterval != nil {
+		if duration := in.PollInterval.Duration; duration > 0 {
+			return duration
+		}
+	}
+	return defaultInterval
+}
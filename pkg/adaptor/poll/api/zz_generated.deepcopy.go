@@ -0,0 +1,84 @@
+// +build !ignore_autogenerated
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package api
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PollDeadband) DeepCopyInto(out *PollDeadband) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PollDeadband.
+func (in *PollDeadband) DeepCopy() *PollDeadband {
+	if in == nil {
+		return nil
+	}
+	out := new(PollDeadband)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PollPropertyOptions) DeepCopyInto(out *PollPropertyOptions) {
+	*out = *in
+	if in.PollInterval != nil {
+		in, out := &in.PollInterval, &out.PollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Deadband != nil {
+		in, out := &in.Deadband, &out.Deadband
+		*out = new(PollDeadband)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PollPropertyOptions.
+func (in *PollPropertyOptions) DeepCopy() *PollPropertyOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(PollPropertyOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PollSyncOptions) DeepCopyInto(out *PollSyncOptions) {
+	*out = *in
+	if in.MaxSilence != nil {
+		in, out := &in.MaxSilence, &out.MaxSilence
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PollSyncOptions.
+func (in *PollSyncOptions) DeepCopy() *PollSyncOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(PollSyncOptions)
+	in.DeepCopyInto(out)
+	return out
+}
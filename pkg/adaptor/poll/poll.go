@@ -0,0 +1,146 @@
+package poll
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/rancher/octopus/pkg/adaptor/poll/api"
+)
+
+// minTickInterval is the min interval of ticking.
+const minTickInterval = 100 * time.Millisecond
+
+// TickInterval returns the interval of ticking which can serve all the given polling intervals,
+// it's the greatest common divisor of the intervals, but not less than 100ms.
+func TickInterval(intervals ...time.Duration) time.Duration {
+	var ret time.Duration
+	for _, interval := range intervals {
+		if interval <= 0 {
+			continue
+		}
+		if ret == 0 {
+			ret = interval
+			continue
+		}
+		var a, b = ret, interval
+		for b != 0 {
+			a, b = b, a%b
+		}
+		ret = a
+	}
+	if ret < minTickInterval {
+		ret = minTickInterval
+	}
+	return ret
+}
+
+// Schedule records the next polling time of each property.
+type Schedule struct {
+	tolerance time.Duration
+	next      map[string]time.Time
+}
+
+// Due returns true if the property should be polled at the given time,
+// and then schedules the next polling of the property.
+func (s *Schedule) Due(name string, interval time.Duration, now time.Time) bool {
+	// tolerates the deviation of ticker.
+	if next, exist := s.next[name]; exist && now.Add(s.tolerance).Before(next) {
+		return false
+	}
+	s.next[name] = now.Add(interval)
+	return true
+}
+
+// NewSchedule creates a Schedule with the interval of ticking.
+func NewSchedule(tick time.Duration) *Schedule {
+	return &Schedule{
+		tolerance: tick / 2,
+		next:      make(map[string]time.Time),
+	}
+}
+
+// Filter decides whether to synchronize the polled values to limb.
+type Filter struct {
+	mode       api.PollSyncMode
+	maxSilence time.Duration
+	current    map[string]string
+	synced     map[string]string
+	observed   bool
+	changed    bool
+	lastSync   time.Time
+}
+
+// Observe records the polled value of the property,
+// and returns true if the value changes beyond the deadband since the last synchronization.
+func (f *Filter) Observe(name, value string, deadband *api.PollDeadband) bool {
+	f.current[name] = value
+	f.observed = true
+	var synced, exist = f.synced[name]
+	if exist && !exceeds(synced, value, deadband) {
+		return false
+	}
+	f.changed = true
+	return true
+}
+
+// ShouldSync returns true if the values should be synchronized at the given time,
+// it's true if any value has been observed since the last synchronization in Interval mode.
+func (f *Filter) ShouldSync(now time.Time) bool {
+	if f.mode != api.PollSyncModeOnChange {
+		return f.observed
+	}
+	return f.changed || f.lastSync.IsZero() || now.Sub(f.lastSync) >= f.maxSilence
+}
+
+// Synced records the values have been synchronized at the given time.
+func (f *Filter) Synced(now time.Time) {
+	for name, value := range f.current {
+		f.synced[name] = value
+	}
+	f.observed = false
+	f.changed = false
+	f.lastSync = now
+}
+
+// NewFilter creates a Filter with the synchronizing options.
+func NewFilter(options *api.PollSyncOptions) *Filter {
+	return &Filter{
+		mode:       options.GetMode(),
+		maxSilence: options.GetMaxSilence(),
+		current:    make(map[string]string),
+		synced:     make(map[string]string),
+	}
+}
+
+// exceeds returns true if the value changes beyond the deadband.
+func exceeds(last, value string, deadband *api.PollDeadband) bool {
+	if last == value {
+		return false
+	}
+	if deadband == nil || (deadband.Absolute == "" && deadband.Percent == "") {
+		return true
+	}
+
+	var lastFloat, lerr = strconv.ParseFloat(last, 64)
+	var valueFloat, verr = strconv.ParseFloat(value, 64)
+	if lerr != nil || verr != nil {
+		return true
+	}
+	var diff = math.Abs(valueFloat - lastFloat)
+
+	if deadband.Absolute != "" {
+		if absolute, err := strconv.ParseFloat(deadband.Absolute, 64); err != nil || diff > absolute {
+			return true
+		}
+	}
+	if deadband.Percent != "" {
+		if lastFloat == 0 {
+			return diff != 0
+		}
+		if percent, err := strconv.ParseFloat(deadband.Percent, 64); err != nil || diff*100/math.Abs(lastFloat) > percent {
+			return true
+		}
+	}
+	return false
+}
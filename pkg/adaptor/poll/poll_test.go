@@ -0,0 +1,94 @@
+package poll
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/octopus/pkg/adaptor/poll/api"
+)
+
+func TestTickInterval(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		given    []time.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "single",
+			given:    []time.Duration{15 * time.Second},
+			expected: 15 * time.Second,
+		},
+		{
+			name:     "greatest common divisor",
+			given:    []time.Duration{500 * time.Millisecond, 750 * time.Millisecond, 60 * time.Second},
+			expected: 250 * time.Millisecond,
+		},
+		{
+			name:     "not less than min interval",
+			given:    []time.Duration{time.Second, time.Second + time.Millisecond},
+			expected: 100 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = TickInterval(tc.given...)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	var start = time.Now()
+	var s = NewSchedule(500 * time.Millisecond)
+
+	assert.True(t, s.Due("alarm", 500*time.Millisecond, start))
+	assert.True(t, s.Due("temperature", time.Minute, start))
+
+	var tick = start.Add(499 * time.Millisecond)
+	assert.True(t, s.Due("alarm", 500*time.Millisecond, tick))
+	assert.False(t, s.Due("temperature", time.Minute, tick))
+
+	tick = start.Add(time.Minute - time.Millisecond)
+	assert.True(t, s.Due("temperature", time.Minute, tick))
+}
+
+func TestFilter(t *testing.T) {
+	var start = time.Now()
+
+	// syncs after each polling in Interval mode
+	var f = NewFilter(nil)
+	f.Observe("temperature", "20", nil)
+	f.Synced(start)
+	assert.False(t, f.ShouldSync(start.Add(time.Second)))
+	f.Observe("temperature", "20", nil)
+	assert.True(t, f.ShouldSync(start.Add(time.Second)))
+
+	// syncs on change in OnChange mode
+	f = NewFilter(&api.PollSyncOptions{
+		Mode:       api.PollSyncModeOnChange,
+		MaxSilence: &metav1.Duration{Duration: time.Minute},
+	})
+	var deadband = &api.PollDeadband{Absolute: "0.5", Percent: "10"}
+	assert.True(t, f.Observe("temperature", "20", deadband))
+	assert.True(t, f.ShouldSync(start))
+	f.Synced(start)
+
+	assert.False(t, f.Observe("temperature", "20.4", deadband))
+	assert.False(t, f.ShouldSync(start.Add(time.Second)))
+	assert.True(t, f.Observe("temperature", "20.6", deadband))
+	assert.True(t, f.ShouldSync(start.Add(time.Second)))
+	f.Synced(start.Add(time.Second))
+
+	assert.False(t, f.Observe("temperature", "20.3", &api.PollDeadband{Percent: "10"}))
+	assert.True(t, f.Observe("state", "on", nil))
+	f.Synced(start.Add(2 * time.Second))
+	assert.False(t, f.Observe("state", "on", nil))
+	assert.True(t, f.Observe("state", "off", nil))
+	f.Synced(start.Add(3 * time.Second))
+
+	// syncs as a heartbeat
+	assert.False(t, f.ShouldSync(start.Add(time.Minute)))
+	assert.True(t, f.ShouldSync(start.Add(3*time.Second+time.Minute)))
+}
@@ -2,6 +2,7 @@
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -13,6 +14,7 @@
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/brain/index"
 	"github.com/rancher/octopus/pkg/brain/predicate"
 	limbctrl "github.com/rancher/octopus/pkg/limb/controller"
 	"github.com/rancher/octopus/pkg/util/collection"
@@ -30,7 +32,8 @@ type DeviceLinkReconciler struct {
 
 // +kubebuilder:rbac:groups=edge.cattle.io,resources=devicelinks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=edge.cattle.io,resources=devicelinks/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups="",resources=nodes,verbs=get
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=edge.cattle.io,resources=nodeadaptors,verbs=get;list;watch
 // +kubebuilder:rbac:groups="apiextensions.k8s.io",resources=customresourcedefinitions,verbs=get
 
 func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -57,7 +60,7 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		var isControlledByLimb bool
 		if link.GetNodeExistedStatus() != metav1.ConditionFalse {
 			var node corev1.Node
-			if err := r.Get(ctx, types.NamespacedName{Name: link.Spec.Adaptor.Node}, &node); err != nil {
+			if err := r.Get(ctx, types.NamespacedName{Name: link.GetScheduledNodeName()}, &node); err != nil {
 				if !apierrs.IsNotFound(err) {
 					log.Error(err, "Unable to fetch the adaptor node of DeviceLink")
 					return ctrl.Result{Requeue: true}, nil
@@ -77,19 +80,36 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 
 	// verifies Node
 	var node corev1.Node
-	if err := r.Get(ctx, types.NamespacedName{Name: link.Spec.Adaptor.Node}, &node); err != nil {
-		if !apierrs.IsNotFound(err) {
-			log.Error(err, "Unable to fetch the adaptor node of DeviceLink")
+	if link.IsScheduledBySelector() {
+		var selected, err = r.selectNode(ctx, &link)
+		if err != nil {
+			log.Error(err, "Unable to select the adaptor node of DeviceLink")
 			return ctrl.Result{Requeue: true}, nil
 		}
-	}
-	if !object.IsActivating(&node) {
-		link.FailOnNodeExisted("adaptor node isn't existed")
-		if err := r.Status().Update(ctx, &link); err != nil {
-			log.Error(err, "Unable to change the status of DeviceLink")
-			return ctrl.Result{Requeue: true}, nil
+		if selected == nil {
+			link.FailOnNodeExisted("there isn't any ready node matching the selector with the adaptor")
+			if err := r.Status().Update(ctx, &link); err != nil {
+				log.Error(err, "Unable to change the status of DeviceLink")
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, nil
+		}
+		node = *selected
+	} else {
+		if err := r.Get(ctx, types.NamespacedName{Name: link.Spec.Adaptor.Node}, &node); err != nil {
+			if !apierrs.IsNotFound(err) {
+				log.Error(err, "Unable to fetch the adaptor node of DeviceLink")
+				return ctrl.Result{Requeue: true}, nil
+			}
+		}
+		if !object.IsActivating(&node) {
+			link.FailOnNodeExisted("adaptor node isn't existed")
+			if err := r.Status().Update(ctx, &link); err != nil {
+				log.Error(err, "Unable to change the status of DeviceLink")
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, nil
 		}
-		return ctrl.Result{}, nil
 	}
 	link.SucceedOnNodeExisted(&node)
 
@@ -119,6 +139,28 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 	}
 	link.SucceedOnModelExisted()
 
+	// verifies adaptor
+	if link.GetAdaptorExistedStatus() != metav1.ConditionTrue {
+		var nodeAdaptor edgev1alpha1.NodeAdaptor
+		if err := r.Get(ctx, types.NamespacedName{Name: link.Status.NodeName}, &nodeAdaptor); err != nil {
+			if !apierrs.IsNotFound(err) {
+				log.Error(err, "Unable to fetch the NodeAdaptor of DeviceLink")
+				return ctrl.Result{Requeue: true}, nil
+			}
+		}
+		// the NodeAdaptor is published by the limb of the node,
+		// we can fail the link early if the adaptor isn't registered on that node,
+		// and the limb will confirm the link again once the adaptor is registered.
+		if object.IsActivating(&nodeAdaptor) && nodeAdaptor.Status.GetAdaptor(link.Spec.Adaptor.Name) == nil {
+			link.FailOnAdaptorExisted(fmt.Sprintf("the adaptor isn't registered on node %s", link.Status.NodeName))
+			if err := r.Status().Update(ctx, &link); err != nil {
+				log.Error(err, "Unable to change the status of DeviceLink")
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, nil
+		}
+	}
+
 	if err := r.Status().Update(ctx, &link); err != nil {
 		log.Error(err, "Unable to change the status of DeviceLink")
 		return ctrl.Result{Requeue: true}, nil
@@ -126,6 +168,74 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 	return ctrl.Result{}, nil
 }
 
+// selectNode returns the node which the link scheduled by node selector should be scheduled on,
+// it keeps the scheduled node if that node is still suitable,
+// otherwise picks the suitable node with the fewest links, returns nil if there isn't any suitable node.
+func (r *DeviceLinkReconciler) selectNode(ctx context.Context, link *edgev1alpha1.DeviceLink) (*corev1.Node, error) {
+	if link.Status.NodeName != "" {
+		var node corev1.Node
+		if err := r.Get(ctx, types.NamespacedName{Name: link.Status.NodeName}, &node); err != nil {
+			if !apierrs.IsNotFound(err) {
+				return nil, err
+			}
+		} else {
+			var suitable, err = r.isNodeSuitable(ctx, link, &node)
+			if err != nil {
+				return nil, err
+			}
+			if suitable {
+				return &node, nil
+			}
+		}
+	}
+
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, client.MatchingLabels(link.Spec.Adaptor.NodeSelector)); err != nil {
+		return nil, err
+	}
+	var (
+		selected      *corev1.Node
+		selectedLinks int
+	)
+	for i := range nodes.Items {
+		var node = &nodes.Items[i]
+		var suitable, err = r.isNodeSuitable(ctx, link, node)
+		if err != nil {
+			return nil, err
+		}
+		if !suitable {
+			continue
+		}
+
+		var links edgev1alpha1.DeviceLinkList
+		if err := r.List(ctx, &links, client.MatchingFields{index.DeviceLinkByNodeField: node.Name}); err != nil {
+			return nil, err
+		}
+		if selected == nil || len(links.Items) < selectedLinks ||
+			(len(links.Items) == selectedLinks && node.Name < selected.Name) {
+			selected = node
+			selectedLinks = len(links.Items)
+		}
+	}
+	return selected, nil
+}
+
+// isNodeSuitable returns true if the node is ready, matches the node selector and has registered the adaptor of link.
+func (r *DeviceLinkReconciler) isNodeSuitable(ctx context.Context, link *edgev1alpha1.DeviceLink, node *corev1.Node) (bool, error) {
+	if !object.IsNodeReady(node) || !link.MatchNodeSelector(node) {
+		return false, nil
+	}
+
+	var nodeAdaptor edgev1alpha1.NodeAdaptor
+	if err := r.Get(ctx, types.NamespacedName{Name: node.Name}, &nodeAdaptor); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return false, err
+		}
+		return false, nil
+	}
+	return nodeAdaptor.Status.GetAdaptor(link.Spec.Adaptor.Name) != nil, nil
+}
+
 func (r *DeviceLinkReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("brain_dl").
@@ -9,6 +9,8 @@
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
 	"github.com/rancher/octopus/pkg/brain/index"
@@ -24,8 +26,10 @@ type NodeReconciler struct {
 	Log logr.Logger
 }
 
+// +kubebuilder:rbac:groups=edge.cattle.io,resources=devicelinks,verbs=list
 // +kubebuilder:rbac:groups=edge.cattle.io,resources=devicelinks/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=edge.cattle.io,resources=nodeadaptors,verbs=get;list;watch;delete
 
 func (r *NodeReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	var ctx = r.Ctx
@@ -54,13 +58,27 @@ func (r *NodeReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			if link.GetNodeExistedStatus() != metav1.ConditionTrue {
 				continue
 			}
-			link.FailOnNodeExisted("adaptor node isn't existed")
+			if link.IsScheduledBySelector() {
+				// reschedules the link to another matched node.
+				link.ToCheckNodeExisted()
+			} else {
+				link.FailOnNodeExisted("adaptor node isn't existed")
+			}
 			if err := r.Status().Update(ctx, &link); err != nil {
 				log.Error(err, "Unable to change the status of DeviceLink")
 				return ctrl.Result{Requeue: true}, nil
 			}
 		}
 
+		// the NodeAdaptor is published by the limb of the Node,
+		// so it should be removed along with the Node.
+		var nodeAdaptor edgev1alpha1.NodeAdaptor
+		nodeAdaptor.Name = node.Name
+		if err := r.Delete(ctx, &nodeAdaptor); err != nil && !apierrs.IsNotFound(err) {
+			log.Error(err, "Unable to delete the NodeAdaptor of Node")
+			return ctrl.Result{Requeue: true}, nil
+		}
+
 		log.V(5).Info("Node has been removed")
 		return ctrl.Result{}, nil
 	}
@@ -71,17 +89,49 @@ func (r *NodeReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		log.Error(err, "Unable to list related DeviceLink of Node")
 		return ctrl.Result{Requeue: true}, nil
 	}
+	var isReady = object.IsNodeReady(&node)
 	for _, link := range links.Items {
-		if link.GetNodeExistedStatus() != metav1.ConditionFalse {
-			continue
+		if link.IsScheduledBySelector() {
+			// reschedules the link to another matched node if the Node isn't ready or doesn't match anymore.
+			if link.GetNodeExistedStatus() != metav1.ConditionTrue || (isReady && link.MatchNodeSelector(&node)) {
+				continue
+			}
+			link.ToCheckNodeExisted()
+		} else {
+			if link.GetNodeExistedStatus() != metav1.ConditionFalse {
+				continue
+			}
+			link.SucceedOnNodeExisted(&node)
 		}
-		link.SucceedOnNodeExisted(&node)
 		if err := r.Status().Update(ctx, &link); err != nil {
 			log.Error(err, "Unable to change the status of DeviceLink")
 			return ctrl.Result{Requeue: true}, nil
 		}
 	}
 
+	// confirms the unscheduled links which match the Node
+	if isReady {
+		// the links scheduled by node selector don't specify the node.
+		var selectorLinks edgev1alpha1.DeviceLinkList
+		if err := r.List(ctx, &selectorLinks, client.MatchingFields{index.DeviceLinkByAdaptorNodeField: ""}); err != nil {
+			log.Error(err, "Unable to list DeviceLink scheduled by node selector")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		for _, link := range selectorLinks.Items {
+			if !link.IsScheduledBySelector() || link.GetNodeExistedStatus() != metav1.ConditionFalse {
+				continue
+			}
+			if !link.MatchNodeSelector(&node) {
+				continue
+			}
+			link.ToCheckNodeExisted()
+			if err := r.Status().Update(ctx, &link); err != nil {
+				log.Error(err, "Unable to change the status of DeviceLink")
+				return ctrl.Result{Requeue: true}, nil
+			}
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -94,10 +144,24 @@ func (r *NodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	); err != nil {
 		return err
 	}
+	if err := mgr.GetFieldIndexer().IndexField(
+		r.Ctx,
+		&edgev1alpha1.DeviceLink{},
+		index.DeviceLinkByAdaptorNodeField,
+		index.DeviceLinkByAdaptorNodeFunc,
+	); err != nil {
+		return err
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("brain_node").
 		For(&corev1.Node{}).
+		// the NodeAdaptor is named as the Node,
+		// the unscheduled links should be confirmed once the adaptor is registered on the Node.
+		Watches(
+			&source.Kind{Type: &edgev1alpha1.NodeAdaptor{}},
+			&handler.EnqueueRequestForObject{},
+		).
 		WithEventFilter(predicate.NodeChangedPredicate{}).
 		Complete(r)
 }
@@ -0,0 +1,25 @@
+package index
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/rancher/octopus/pkg/util/object"
+)
+
+const DeviceLinkByAdaptorNodeField = "deviceLinkByAdaptorNode"
+
+var deviceLinkByAdaptorNodeIndexLog = ctrl.Log.WithName("index").WithName(DeviceLinkByAdaptorNodeField)
+
+// DeviceLinkByAdaptorNodeFunc indexes the link by `spec.adaptor.node`,
+// the links scheduled by node selector are indexed by the blank node name.
+func DeviceLinkByAdaptorNodeFunc(rawObj runtime.Object) []string {
+	var link = object.ToDeviceLinkObject(rawObj)
+	if link == nil {
+		return nil
+	}
+
+	var nodeName = link.Spec.Adaptor.Node
+	deviceLinkByAdaptorNodeIndexLog.V(6).Info("Indexed", "nodeName", nodeName, "object", object.GetNamespacedName(link))
+	return []string{nodeName}
+}
@@ -0,0 +1,57 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+)
+
+func TestDeviceLinkByAdaptorNodeFunc(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		given    runtime.Object
+		expected []string
+	}{
+		{
+			name: "non-blank node name",
+			given: &edgev1alpha1.DeviceLink{
+				Spec: edgev1alpha1.DeviceLinkSpec{
+					Adaptor: edgev1alpha1.DeviceAdaptor{
+						Node: "edge-worker",
+					},
+				},
+			},
+			expected: []string{"edge-worker"},
+		},
+		{
+			name: "scheduled by node selector",
+			given: &edgev1alpha1.DeviceLink{
+				Spec: edgev1alpha1.DeviceLinkSpec{
+					Adaptor: edgev1alpha1.DeviceAdaptor{
+						NodeSelector: map[string]string{
+							"edge.cattle.io/gateway": "true",
+						},
+					},
+				},
+				Status: edgev1alpha1.DeviceLinkStatus{
+					NodeName: "edge-worker",
+				},
+			},
+			expected: []string{""},
+		},
+		{
+			name:     "non-DeviceLink instance",
+			given:    &corev1.Node{},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = DeviceLinkByAdaptorNodeFunc(tc.given)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
@@ -17,7 +17,8 @@ func DeviceLinkByNodeFunc(rawObj runtime.Object) []string {
 		return nil
 	}
 
-	var nodeName = link.Spec.Adaptor.Node
+	// the link scheduled by node selector is indexed by the scheduled node.
+	var nodeName = link.GetScheduledNodeName()
 	if nodeName != "" {
 		deviceLinkByNodeIndexLog.V(6).Info("Indexed", "nodeName", nodeName, "object", object.GetNamespacedName(link))
 		return []string{nodeName}
@@ -27,6 +27,35 @@ func TestDeviceLinkByNodeFunc(t *testing.T) {
 			},
 			expected: []string{"edge-worker"},
 		},
+		{
+			name: "scheduled by node selector",
+			given: &edgev1alpha1.DeviceLink{
+				Spec: edgev1alpha1.DeviceLinkSpec{
+					Adaptor: edgev1alpha1.DeviceAdaptor{
+						NodeSelector: map[string]string{
+							"edge.cattle.io/gateway": "true",
+						},
+					},
+				},
+				Status: edgev1alpha1.DeviceLinkStatus{
+					NodeName: "edge-worker",
+				},
+			},
+			expected: []string{"edge-worker"},
+		},
+		{
+			name: "unscheduled by node selector",
+			given: &edgev1alpha1.DeviceLink{
+				Spec: edgev1alpha1.DeviceLinkSpec{
+					Adaptor: edgev1alpha1.DeviceAdaptor{
+						NodeSelector: map[string]string{
+							"edge.cattle.io/gateway": "true",
+						},
+					},
+				},
+			},
+			expected: nil,
+		},
 		{
 			name: "blank node name",
 			given: &edgev1alpha1.DeviceLink{
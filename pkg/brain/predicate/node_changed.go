@@ -1,11 +1,15 @@
 package predicate
 
 import (
+	"reflect"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
 	"github.com/rancher/octopus/pkg/util/object"
 )
 
@@ -20,6 +24,17 @@ func (NodeChangedPredicate) Update(e event.UpdateEvent) bool {
 		return false
 	}
 
+	// handles when changing the registered adaptors,
+	// the changes of connections are ignored as they don't affect the scheduling.
+	if nodeAdaptorOld := object.ToNodeAdaptorObject(e.ObjectOld); nodeAdaptorOld != nil {
+		var nodeAdaptorNew = object.ToNodeAdaptorObject(e.ObjectNew)
+		if nodeAdaptorNew == nil || diffRegisteredAdaptors(nodeAdaptorOld.Status.Adaptors, nodeAdaptorNew.Status.Adaptors) {
+			nodeChangedPredicateLog.V(5).Info("Accept UpdateEvent as changed adaptors", "object", object.GetNamespacedName(e.MetaOld))
+			return true
+		}
+		return false
+	}
+
 	// doesn't handle non-Node object
 	if !object.IsNodeObject(e.ObjectOld) {
 		return true
@@ -34,6 +49,17 @@ func (NodeChangedPredicate) Update(e event.UpdateEvent) bool {
 		return true
 	}
 
+	// handles when changing readiness or labels,
+	// so that the links scheduled by node selector can be rescheduled.
+	if object.IsNodeReady(nodeOld) != object.IsNodeReady(nodeNew) {
+		nodeChangedPredicateLog.V(5).Info("Accept UpdateEvent as changed readiness", "object", object.GetNamespacedName(e.MetaOld))
+		return true
+	}
+	if !reflect.DeepEqual(nodeOld.Labels, nodeNew.Labels) {
+		nodeChangedPredicateLog.V(5).Info("Accept UpdateEvent as changed labels", "object", object.GetNamespacedName(e.MetaOld))
+		return true
+	}
+
 	return false
 }
 
@@ -54,3 +80,21 @@ func diffNodeAddresses(oldAddresses, newAddresses []corev1.NodeAddress) bool {
 	}
 	return false
 }
+
+// diffRegisteredAdaptors compares the differences between two NodeAdaptor object's adaptors without the connections.
+// If there is a difference, it returns true.
+func diffRegisteredAdaptors(oldAdaptors, newAdaptors []edgev1alpha1.RegisteredAdaptor) bool {
+	if len(oldAdaptors) != len(newAdaptors) {
+		return true
+	}
+
+	for i := range oldAdaptors {
+		var oldAdaptor = oldAdaptors[i]
+		var newAdaptor = newAdaptors[i]
+		oldAdaptor.Connections, newAdaptor.Connections = 0, 0
+		if !equality.Semantic.DeepEqual(oldAdaptor, newAdaptor) {
+			return true
+		}
+	}
+	return false
+}
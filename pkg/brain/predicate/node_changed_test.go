@@ -91,6 +91,122 @@ func TestNodeChangedPredicate_Update(t *testing.T) {
 			),
 			expected: true,
 		},
+		{
+			name: "changed Node instance's readiness",
+			given: generateUpdateEvent(
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "edge-worker",
+					},
+					Status: corev1.NodeStatus{
+						Conditions: []corev1.NodeCondition{
+							{
+								Type:   corev1.NodeReady,
+								Status: corev1.ConditionTrue,
+							},
+						},
+					},
+				},
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "edge-worker",
+					},
+					Status: corev1.NodeStatus{
+						Conditions: []corev1.NodeCondition{
+							{
+								Type:   corev1.NodeReady,
+								Status: corev1.ConditionUnknown,
+							},
+						},
+					},
+				},
+			),
+			expected: true,
+		},
+		{
+			name: "changed Node instance's labels",
+			given: generateUpdateEvent(
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "edge-worker",
+					},
+				},
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "edge-worker",
+						Labels: map[string]string{
+							"edge.cattle.io/gateway": "true",
+						},
+					},
+				},
+			),
+			expected: true,
+		},
+		{
+			name: "unchanged Node instance",
+			given: generateUpdateEvent(
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "edge-worker",
+						ResourceVersion: "1",
+					},
+				},
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "edge-worker",
+						ResourceVersion: "2",
+					},
+				},
+			),
+			expected: false,
+		},
+		{
+			name: "changed NodeAdaptor instance's adaptors",
+			given: generateUpdateEvent(
+				&edgev1alpha1.NodeAdaptor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "edge-worker",
+					},
+				},
+				&edgev1alpha1.NodeAdaptor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "edge-worker",
+					},
+					Status: edgev1alpha1.NodeAdaptorStatus{
+						Adaptors: []edgev1alpha1.RegisteredAdaptor{
+							{Name: "adaptors.edge.cattle.io/dummy"},
+						},
+					},
+				},
+			),
+			expected: true,
+		},
+		{
+			name: "changed NodeAdaptor instance's connections",
+			given: generateUpdateEvent(
+				&edgev1alpha1.NodeAdaptor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "edge-worker",
+					},
+					Status: edgev1alpha1.NodeAdaptorStatus{
+						Adaptors: []edgev1alpha1.RegisteredAdaptor{
+							{Name: "adaptors.edge.cattle.io/dummy", Connections: 1},
+						},
+					},
+				},
+				&edgev1alpha1.NodeAdaptor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "edge-worker",
+					},
+					Status: edgev1alpha1.NodeAdaptorStatus{
+						Adaptors: []edgev1alpha1.RegisteredAdaptor{
+							{Name: "adaptors.edge.cattle.io/dummy", Connections: 2},
+						},
+					},
+				},
+			),
+			expected: false,
+		},
 	}
 
 	var predication = NodeChangedPredicate{}
@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+	api "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/limb/index"
+	"github.com/rancher/octopus/pkg/suctioncup"
+	"github.com/rancher/octopus/pkg/util/object"
+)
+
+// DeviceActionReconciler reconciles a DeviceAction object
+type DeviceActionReconciler struct {
+	client.Client
+	record.EventRecorder
+
+	Ctx context.Context
+	Log logr.Logger
+
+	SuctionCup suctioncup.Neurons
+	NodeName   string
+
+	// attempts holds the IDs of the attempts which are running in this process.
+	attempts sync.Map
+}
+
+// +kubebuilder:rbac:groups=edge.cattle.io,resources=deviceactions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=edge.cattle.io,resources=deviceactions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=edge.cattle.io,resources=devicelinks,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *DeviceActionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	var ctx = r.Ctx
+	var log = r.Log.WithValues("deviceAction", req.NamespacedName)
+
+	// fetches action
+	var action edgev1alpha1.DeviceAction
+	if err := r.Get(ctx, req.NamespacedName, &action); err != nil {
+		if !apierrs.IsNotFound(err) {
+			log.Error(err, "Unable to fetch DeviceAction")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, nil
+	}
+	if object.IsDeleted(&action) || action.Status.IsCompleted() {
+		return ctrl.Result{}, nil
+	}
+
+	// fetches link
+	var link edgev1alpha1.DeviceLink
+	if err := r.Get(ctx, types.NamespacedName{Namespace: action.Namespace, Name: action.Spec.DeviceLink}, &link); err != nil {
+		if !apierrs.IsNotFound(err) {
+			log.Error(err, "Unable to fetch the DeviceLink of DeviceAction")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		// we cannot judge which node should take this action if the link is not found,
+		// so just leave it until the link is created.
+		return ctrl.Result{}, nil
+	}
+
+	// only the limb of the scheduled node can take the action.
+	if link.Status.NodeName != r.NodeName {
+		return ctrl.Result{}, nil
+	}
+
+	// a running action is owned by the attempt which sent it,
+	// the attempt cannot be resumed if it doesn't belong to this process, e.g. limb is restarted,
+	// since the adaptor result has been lost.
+	if action.Status.Phase == edgev1alpha1.DeviceActionRunning {
+		if _, running := r.attempts.Load(action.Status.AttemptID); running {
+			return ctrl.Result{}, nil
+		}
+		return r.complete(&action, edgev1alpha1.DeviceActionFailed, nil, fmt.Sprintf("interrupted as the attempt %s is lost", action.Status.AttemptID))
+	}
+
+	// waits for the device connected
+	if link.GetDeviceConnectedStatus() != metav1.ConditionTrue {
+		var timeout = action.CreationTimestamp.Add(action.Spec.GetTimeout()).Sub(time.Now())
+		if timeout <= 0 {
+			return r.complete(&action, edgev1alpha1.DeviceActionFailed, nil, "timeout to wait for the device connected")
+		}
+		if action.Status.Phase != edgev1alpha1.DeviceActionPending {
+			action.Status.Phase = edgev1alpha1.DeviceActionPending
+			action.Status.NodeName = link.Status.NodeName
+			action.Status.Message = "waiting for the device connected"
+			if err := r.Status().Update(ctx, &action); err != nil {
+				log.Error(err, "Unable to change the status of DeviceAction")
+				return ctrl.Result{Requeue: true}, nil
+			}
+		}
+		return ctrl.Result{RequeueAfter: timeout}, nil
+	}
+
+	// runs
+	var attemptID = string(uuid.NewUUID())
+	r.attempts.Store(attemptID, struct{}{})
+	defer r.attempts.Delete(attemptID)
+	action.Status.Phase = edgev1alpha1.DeviceActionRunning
+	action.Status.CorrelationID = string(action.UID)
+	action.Status.AttemptID = attemptID
+	action.Status.NodeName = link.Status.NodeName
+	action.Status.AdaptorName = link.Status.AdaptorName
+	action.Status.StartTime = now()
+	action.Status.Message = ""
+	if err := r.Status().Update(ctx, &action); err != nil {
+		log.Error(err, "Unable to change the status of DeviceAction")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	var sendAction = &api.ConnectRequestAction{
+		Id:   action.Status.CorrelationID,
+		Name: action.Spec.Action,
+	}
+	if action.Spec.Arguments != nil {
+		sendAction.Arguments = action.Spec.Arguments.Raw
+	}
+	var result, err = r.SuctionCup.Act(sendAction, action.Spec.GetTimeout(), &link)
+	if err != nil {
+		return r.complete(&action, edgev1alpha1.DeviceActionFailed, nil, err.Error())
+	}
+	if result.GetErrorMessage() != "" {
+		return r.complete(&action, edgev1alpha1.DeviceActionFailed, result.GetResult(), result.GetErrorMessage())
+	}
+	return r.complete(&action, edgev1alpha1.DeviceActionSucceeded, result.GetResult(), "")
+}
+
+// complete records the final phase of the action.
+func (r *DeviceActionReconciler) complete(action *edgev1alpha1.DeviceAction, phase edgev1alpha1.DeviceActionPhase, result []byte, message string) (ctrl.Result, error) {
+	action.Status.Phase = phase
+	action.Status.CompletionTime = now()
+	action.Status.Message = message
+	if len(result) != 0 && json.Valid(result) {
+		action.Status.Result = &k8sruntime.RawExtension{Raw: result}
+	}
+	if err := r.Status().Update(r.Ctx, action); err != nil {
+		r.Log.Error(err, "Unable to change the status of DeviceAction", "deviceAction", object.GetNamespacedName(action))
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if phase == edgev1alpha1.DeviceActionSucceeded {
+		r.Eventf(action, "Normal", "Succeeded", "executed %s on device", action.Spec.Action)
+	} else {
+		r.Eventf(action, "Warning", "Failed", "cannot execute %s on device: %s", action.Spec.Action, message)
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *DeviceActionReconciler) SetupWithManager(ctrlMgr ctrl.Manager) error {
+	if err := ctrlMgr.GetFieldIndexer().IndexField(
+		r.Ctx,
+		&edgev1alpha1.DeviceAction{},
+		index.DeviceActionByDeviceLinkField,
+		index.DeviceActionByDeviceLinkFunc,
+	); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(ctrlMgr).
+		Named("limb_da").
+		For(&edgev1alpha1.DeviceAction{}).
+		// the pending actions should be reconciled once the link is connected.
+		Watches(
+			&source.Kind{Type: &edgev1alpha1.DeviceLink{}},
+			&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.mapDeviceLinkToActions)},
+		).
+		// an action blocks the reconciling until the adaptor responds,
+		// so we allow multiple actions to be executed at the same time.
+		WithOptions(controller.Options{MaxConcurrentReconciles: runtime.NumCPU()}).
+		Complete(r)
+}
+
+// mapDeviceLinkToActions returns the uncompleted actions of the link.
+func (r *DeviceActionReconciler) mapDeviceLinkToActions(obj handler.MapObject) []reconcile.Request {
+	var link = object.ToDeviceLinkObject(obj.Object)
+	if link == nil || link.Status.NodeName != r.NodeName {
+		return nil
+	}
+	if link.GetDeviceConnectedStatus() != metav1.ConditionTrue {
+		return nil
+	}
+
+	var actions edgev1alpha1.DeviceActionList
+	if err := r.List(r.Ctx, &actions, client.InNamespace(link.Namespace), client.MatchingFields{index.DeviceActionByDeviceLinkField: link.Name}); err != nil {
+		r.Log.Error(err, "Unable to list related DeviceAction of DeviceLink", "deviceLink", object.GetNamespacedName(link))
+		return nil
+	}
+	var reqs = make([]reconcile.Request, 0, len(actions.Items))
+	for _, action := range actions.Items {
+		reqs = append(reqs, reconcile.Request{NamespacedName: object.GetNamespacedName(&action)})
+	}
+	return reqs
+}
+
+func now() *metav1.Time {
+	var ret = metav1.Now()
+	return &ret
+}
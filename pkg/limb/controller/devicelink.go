@@ -15,10 +15,16 @@
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+	api "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/limb/history"
 	"github.com/rancher/octopus/pkg/limb/index"
 	"github.com/rancher/octopus/pkg/limb/predicate"
+	"github.com/rancher/octopus/pkg/metrics"
 	"github.com/rancher/octopus/pkg/suctioncup"
 	"github.com/rancher/octopus/pkg/util/collection"
 	"github.com/rancher/octopus/pkg/util/converter"
@@ -39,8 +45,9 @@ type DeviceLinkReconciler struct {
 	Ctx context.Context
 	Log logr.Logger
 
-	SuctionCup suctioncup.Neurons
-	NodeName   string
+	SuctionCup    suctioncup.Neurons
+	NodeName      string
+	StatusHistory *history.History
 }
 
 // +kubebuilder:rbac:groups=edge.cattle.io,resources=devicelinks,verbs=get;list;watch;create;update;patch;delete
@@ -71,6 +78,7 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 
 		// disconnects
 		r.SuctionCup.Disconnect(&link)
+		r.StatusHistory.Remove(req.NamespacedName)
 
 		// removes finalizer
 		link.Finalizers = collection.StringSliceRemove(link.Finalizers, ReconcilingDeviceLink)
@@ -93,9 +101,10 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 	}
 
 	// NB(thxCode) we might see this as the `spec.adaptor.node` has been changed,
+	// or the link has been rescheduled to another node by brain,
 	// so we need to disconnect the previous connection and
 	// wait for brain to confirm the next step.
-	if link.Status.NodeName != link.Spec.Adaptor.Node {
+	if link.Status.NodeName != r.NodeName || (link.Spec.Adaptor.Node != "" && link.Status.NodeName != link.Spec.Adaptor.Node) {
 		r.SuctionCup.Disconnect(&link)
 		return ctrl.Result{}, nil
 	}
@@ -124,6 +133,16 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		}
 		return ctrl.Result{}, nil
 	}
+	var isModelSupported = r.SuctionCup.SupportModel(link.Spec.Adaptor.Name, *link.Status.Model)
+	if !isModelSupported {
+		link.FailOnAdaptorSupportedModel(fmt.Sprintf("the adaptor doesn't serve the model %s", link.Status.Model.GroupVersionKind()))
+		if err := r.Status().Update(ctx, &link); err != nil {
+			log.Error(err, "Unable to change the status of DeviceLink")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		r.Eventf(&link, "Warning", "UnsupportedModel", "the adaptor %s doesn't serve the model %s", link.Spec.Adaptor.Name, link.Status.Model.GroupVersionKind())
+		return ctrl.Result{}, nil
+	}
 	link.SucceedOnAdaptorExisted()
 
 	// validates device
@@ -167,6 +186,13 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 	}
 	link.SucceedOnDeviceCreated()
 
+	// waits for the scheduled reconnection if the connection was broken.
+	if link.GetDeviceConnectedStatus() == metav1.ConditionFalse {
+		if backoff := link.GetReconnectionBackoff(time.Now()); backoff > 0 {
+			return ctrl.Result{RequeueAfter: backoff}, nil
+		}
+	}
+
 	// fetches the references
 	var references, err = r.fetchReferences(&link)
 	if err != nil {
@@ -175,9 +201,13 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 			log.Error(err, "Unable to change the status of DeviceLink")
 			return ctrl.Result{Requeue: true}, nil
 		}
-		r.Eventf(&link, "Warning", "FailedFetched", "cannot fetch the reference parameters: %v, retry in 10 seconds", err)
-		// NB(thxCode) since we don't list-watch the ConfigMap/Secret resources, we have to give a retry mechanism to obtain these resources.
-		return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
+		r.Eventf(&link, "Warning", "FailedFetched", "cannot fetch the reference parameters: %v", err)
+		// the link is reconciled again once the referred ConfigMap/Secret is changed,
+		// so we only need to retry on the unexpected errors.
+		if !apierrs.IsNotFound(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, nil
 	}
 
 	// updates device if need
@@ -200,7 +230,26 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 
 	// connects to device
 	if err := r.SuctionCup.Connect(references, &device, &link); err != nil {
+		// the condition is kept during retrying, and is marked as failed once the retries are exhausted.
+		if backoff, ok := link.ScheduleSendRetry(); ok {
+			if err := r.Status().Update(ctx, &link); err != nil {
+				log.Error(err, "Unable to change the status of DeviceLink")
+				return ctrl.Result{Requeue: true}, nil
+			}
+			metrics.GetLimbMetricsRecorder().IncreaseSendRetries(link.Status.AdaptorName)
+			r.Eventf(&link, "Warning", "RetrySending", "cannot connect to device: %v, retry in %v", err, backoff)
+			return ctrl.Result{RequeueAfter: backoff}, nil
+		}
 		link.FailOnDeviceConnected("unable to connect to device")
+		if link.IsReconnectable(false) {
+			var backoff = link.ScheduleReconnection(time.Now())
+			if err := r.Status().Update(ctx, &link); err != nil {
+				log.Error(err, "Unable to change the status of DeviceLink")
+				return ctrl.Result{Requeue: true}, nil
+			}
+			r.Eventf(&link, "Warning", "BackOff", "cannot connect to device: %v, reconnect in %v", err, backoff)
+			return ctrl.Result{RequeueAfter: backoff}, nil
+		}
 		if err := r.Status().Update(ctx, &link); err != nil {
 			log.Error(err, "Unable to change the status of DeviceLink")
 			return ctrl.Result{Requeue: true}, nil
@@ -209,6 +258,7 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, nil
 	}
 	link.SucceedOnDeviceConnected()
+	link.ResetSendRetries()
 
 	if err := r.Status().Update(ctx, &link); err != nil {
 		log.Error(err, "Unable to change the status of DeviceLink")
@@ -230,23 +280,68 @@ func (r *DeviceLinkReconciler) SetupWithManager(ctrlMgr ctrl.Manager, suctionCup
 	); err != nil {
 		return err
 	}
+	if err := ctrlMgr.GetFieldIndexer().IndexField(
+		r.Ctx,
+		&edgev1alpha1.DeviceLink{},
+		index.DeviceLinkBySecretField,
+		index.DeviceLinkBySecretFuncFactory(r.NodeName),
+	); err != nil {
+		return err
+	}
+	if err := ctrlMgr.GetFieldIndexer().IndexField(
+		r.Ctx,
+		&edgev1alpha1.DeviceLink{},
+		index.DeviceLinkByConfigMapField,
+		index.DeviceLinkByConfigMapFuncFactory(r.NodeName),
+	); err != nil {
+		return err
+	}
 
 	return ctrl.NewControllerManagedBy(ctrlMgr).
 		Named("limb_dl").
 		For(&edgev1alpha1.DeviceLink{}).
+		// the references should be sent to the adaptor again once the referred Secret/ConfigMap is changed.
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			&handler.EnqueueRequestsFromMapFunc{ToRequests: r.mapReferenceToLinks(index.DeviceLinkBySecretField)},
+		).
+		Watches(
+			&source.Kind{Type: &corev1.ConfigMap{}},
+			&handler.EnqueueRequestsFromMapFunc{ToRequests: r.mapReferenceToLinks(index.DeviceLinkByConfigMapField)},
+		).
 		WithEventFilter(predicate.DeviceLinkChangedPredicate{NodeName: r.NodeName}).
 		Complete(r)
 }
 
+// mapReferenceToLinks returns a mapper which maps the referred Secret/ConfigMap to the links on this node.
+func (r *DeviceLinkReconciler) mapReferenceToLinks(field string) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		if obj.Meta == nil {
+			return nil
+		}
+
+		var links edgev1alpha1.DeviceLinkList
+		if err := r.List(r.Ctx, &links, client.InNamespace(obj.Meta.GetNamespace()), client.MatchingFields{field: obj.Meta.GetName()}); err != nil {
+			r.Log.Error(err, "Unable to list related DeviceLink of reference", "reference", types.NamespacedName{Namespace: obj.Meta.GetNamespace(), Name: obj.Meta.GetName()})
+			return nil
+		}
+		var reqs = make([]reconcile.Request, 0, len(links.Items))
+		for _, link := range links.Items {
+			reqs = append(reqs, reconcile.Request{NamespacedName: object.GetNamespacedName(&link)})
+		}
+		return reqs
+	}
+}
+
 // fetchReferences fetches the references of deviceLink.
-func (r *DeviceLinkReconciler) fetchReferences(deviceLink *edgev1alpha1.DeviceLink) (map[string]map[string][]byte, error) {
+func (r *DeviceLinkReconciler) fetchReferences(deviceLink *edgev1alpha1.DeviceLink) (map[string]*api.ConnectRequestReferenceEntry, error) {
 	var ctx = r.Ctx
 	var references = deviceLink.Spec.References
 	var namespace = deviceLink.Namespace
 
-	var referencesData map[string]map[string][]byte
+	var referencesData map[string]*api.ConnectRequestReferenceEntry
 	if len(references) != 0 {
-		referencesData = make(map[string]map[string][]byte, len(references))
+		referencesData = make(map[string]*api.ConnectRequestReferenceEntry, len(references))
 
 		for _, rp := range references {
 			var name = rp.Name
@@ -255,10 +350,18 @@ func (r *DeviceLinkReconciler) fetchReferences(deviceLink *edgev1alpha1.DeviceLi
 			if rp.Secret != nil {
 				var desiredName = rp.Secret.Name
 				var desiredItems = rp.Secret.Items
+				var entry = &api.ConnectRequestReferenceEntry{
+					Defaults: toBytesMap(rp.Secret.DefaultValues),
+				}
 
 				var secret corev1.Secret
 				if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: desiredName}, &secret); err != nil {
-					return nil, err
+					if !apierrs.IsNotFound(err) || !rp.Secret.IsOptional() {
+						return nil, err
+					}
+					// skips the absent optional Secret, the adaptor gets the default values instead.
+					referencesData[name] = entry
+					continue
 				}
 
 				var items = secret.Data
@@ -267,13 +370,17 @@ func (r *DeviceLinkReconciler) fetchReferences(deviceLink *edgev1alpha1.DeviceLi
 					for _, sk := range desiredItems {
 						var sv, exist = secret.Data[sk]
 						if !exist {
+							if rp.Secret.IsOptional() {
+								continue
+							}
 							return nil, apierrs.NewNotFound(corev1.Resource(corev1.ResourceSecrets.String()), fmt.Sprintf("%s.data(%s)", desiredName, sk))
 						}
 						items[sk] = sv
 					}
 				}
 
-				referencesData[name] = items
+				entry.Items = items
+				referencesData[name] = entry
 				continue
 			}
 
@@ -281,10 +388,18 @@ func (r *DeviceLinkReconciler) fetchReferences(deviceLink *edgev1alpha1.DeviceLi
 			if rp.ConfigMap != nil {
 				var desiredName = rp.ConfigMap.Name
 				var desiredItems = rp.ConfigMap.Items
+				var entry = &api.ConnectRequestReferenceEntry{
+					Defaults: toBytesMap(rp.ConfigMap.DefaultValues),
+				}
 
 				var configMap corev1.ConfigMap
 				if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: desiredName}, &configMap); err != nil {
-					return nil, err
+					if !apierrs.IsNotFound(err) || !rp.ConfigMap.IsOptional() {
+						return nil, err
+					}
+					// skips the absent optional ConfigMap, the adaptor gets the default values instead.
+					referencesData[name] = entry
+					continue
 				}
 
 				var items map[string][]byte
@@ -293,18 +408,19 @@ func (r *DeviceLinkReconciler) fetchReferences(deviceLink *edgev1alpha1.DeviceLi
 					for _, cmk := range desiredItems {
 						var cmv, exist = configMap.Data[cmk]
 						if !exist {
+							if rp.ConfigMap.IsOptional() {
+								continue
+							}
 							return nil, apierrs.NewNotFound(corev1.Resource(corev1.ResourceConfigMaps.String()), fmt.Sprintf("%s.data(%s)", desiredName, cmk))
 						}
 						items[cmk] = []byte(cmv)
 					}
 				} else {
-					items = make(map[string][]byte, len(configMap.Data))
-					for cmk, cmv := range configMap.Data {
-						items[cmk] = []byte(cmv)
-					}
+					items = toBytesMap(configMap.Data)
 				}
 
-				referencesData[name] = items
+				entry.Items = items
+				referencesData[name] = entry
 				continue
 			}
 
@@ -322,7 +438,7 @@ func (r *DeviceLinkReconciler) fetchReferences(deviceLink *edgev1alpha1.DeviceLi
 					}
 				}
 
-				referencesData[name] = items
+				referencesData[name] = &api.ConnectRequestReferenceEntry{Items: items}
 			}
 		}
 	}
@@ -330,6 +446,18 @@ func (r *DeviceLinkReconciler) fetchReferences(deviceLink *edgev1alpha1.DeviceLi
 	return referencesData, nil
 }
 
+// toBytesMap converts the string map to bytes map.
+func toBytesMap(in map[string]string) map[string][]byte {
+	if len(in) == 0 {
+		return nil
+	}
+	var out = make(map[string][]byte, len(in))
+	for k, v := range in {
+		out[k] = []byte(v)
+	}
+	return out
+}
+
 // isDeviceSpecChanged returns true if there is any changed from deviceLink's template and applies the changes into device.
 func isDeviceSpecChanged(deviceLink *edgev1alpha1.DeviceLink, device *unstructured.Unstructured) bool {
 	var deviceTemplate = deviceLink.Spec.Template
@@ -29,11 +29,19 @@ func (r *DeviceLinkReconciler) ReceiveAdaptorStatus(req suctioncup.RequestAdapto
 		return suctioncup.Response{Requeue: true}, nil
 	}
 
+	// publishes the adaptors before updating the links,
+	// so that brain can see the adaptor once the links are changed.
+	if err := syncNodeAdaptor(ctx, r.Client, r.NodeName, r.SuctionCup); err != nil {
+		log.Error(err, "Unable to sync NodeAdaptor")
+		return suctioncup.Response{Requeue: true}, nil
+	}
+
+	// the connections of the stale adaptor are dropped if the adaptor is registered again.
+	metrics.GetLimbMetricsRecorder().ResetConnections(req.Name)
 	if req.Registered {
 		log.Info("Adaptor is registered")
 	} else {
 		log.Info("Adaptor is unregistered")
-		metrics.GetLimbMetricsRecorder().ResetConnections(req.Name)
 	}
 
 	// NB(thxCode) all items are guaranteed by indexer to be associated with the current node.
@@ -46,7 +54,9 @@ func (r *DeviceLinkReconciler) ReceiveAdaptorStatus(req suctioncup.RequestAdapto
 					return suctioncup.Response{Requeue: true}, nil
 				}
 			}
-			if link.GetDeviceConnectedStatus() == metav1.ConditionFalse {
+			// the healthy links should be re-attached to the adaptor if it is registered again,
+			// e.g. the adaptor was upgraded within the grace period.
+			if status := link.GetDeviceConnectedStatus(); status == metav1.ConditionFalse || status == metav1.ConditionTrue {
 				link.ToCheckDeviceConnected()
 				if err := r.Status().Update(ctx, &link); err != nil {
 					log.Error(err, "Unable to change the status of DeviceLink")
@@ -2,6 +2,7 @@
 
 import (
 	"context"
+	"time"
 
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,8 +37,30 @@ func (r *DeviceLinkReconciler) ReceiveConnectionStatus(req suctioncup.RequestCon
 	}
 
 	if req.Closed {
-		// NB(thxCode) we need to reconnect again if the connection is closed passively.
-		// TODO However, we need a way to stop the passive closed from unregistering adaptor.
+		// the reconnect policy can take over the passive closed,
+		// "Never" fails the connection and "Always" reconnects with backoff.
+		if policy := link.Spec.ReconnectPolicy; policy != nil && policy.GetType() != edgev1alpha1.DeviceLinkReconnectOnFailure {
+			r.SuctionCup.Disconnect(&link)
+			link.FailOnDeviceConnected("closed by adaptor")
+			if link.IsReconnectable(true) {
+				var backoff = link.ScheduleReconnection(time.Now())
+				if err := r.Status().Update(ctx, &link); err != nil {
+					log.Error(err, "Unable to change the status of DeviceLink")
+					return suctioncup.Response{Requeue: true}, nil
+				}
+				r.Eventf(&link, "Warning", "BackOff", "closed by adaptor, reconnect in %v", backoff)
+				return suctioncup.Response{}, nil
+			}
+			if err := r.Status().Update(ctx, &link); err != nil {
+				log.Error(err, "Unable to change the status of DeviceLink")
+				return suctioncup.Response{Requeue: true}, nil
+			}
+			r.Eventf(&link, "Warning", "Disconnected", "closed by adaptor")
+			return suctioncup.Response{}, nil
+		}
+
+		// NB(thxCode) we need to reconnect again if the connection is closed passively,
+		// the closing caused by adaptor leaving doesn't reach here, it is handled by the adaptor registration.
 		link.ToCheckDeviceConnected()
 		if err := r.Status().Update(ctx, &link); err != nil {
 			log.Error(err, "Unable to change the status of DeviceLink")
@@ -50,9 +73,18 @@ func (r *DeviceLinkReconciler) ReceiveConnectionStatus(req suctioncup.RequestCon
 	if req.Error != nil {
 		// NB(thxCode) we cannot reconnect directly if the connection returns an error,
 		// it may be something uncontrollable happened, e.g. passed a wrong parameter or failed to connect the physical device.
-		// it can be recovered by user manually.
+		// it can be recovered by user manually, or by limb with backoff if the reconnect policy allows.
 		r.SuctionCup.Disconnect(&link)
 		link.FailOnDeviceConnected("received error from adaptor")
+		if link.IsReconnectable(false) {
+			var backoff = link.ScheduleReconnection(time.Now())
+			if err := r.Status().Update(ctx, &link); err != nil {
+				log.Error(err, "Unable to change the status of DeviceLink")
+				return suctioncup.Response{Requeue: true}, nil
+			}
+			r.Eventf(&link, "Warning", "BackOff", "received error from adaptor: %v, reconnect in %v", req.Error, backoff)
+			return suctioncup.Response{}, nil
+		}
 		if err := r.Status().Update(ctx, &link); err != nil {
 			log.Error(err, "Unable to change the status of DeviceLink")
 			return suctioncup.Response{Requeue: true}, nil
@@ -66,6 +98,15 @@ func (r *DeviceLinkReconciler) ReceiveConnectionStatus(req suctioncup.RequestCon
 		return suctioncup.Response{}, nil
 	}
 
+	// the adaptor has sent data back, so the connection is healthy,
+	// we can clear the reconnection records.
+	if link.ResetReconnection() {
+		if err := r.Status().Update(ctx, &link); err != nil {
+			log.Error(err, "Unable to change the status of DeviceLink")
+			return suctioncup.Response{Requeue: true}, nil
+		}
+	}
+
 	// parses device status
 	var updatedStatus interface{}
 	if err := func() error {
@@ -108,6 +149,7 @@ func (r *DeviceLinkReconciler) ReceiveConnectionStatus(req suctioncup.RequestCon
 		log.Error(err, "Unable to update the device of DeviceLink")
 		return suctioncup.Response{Requeue: true}, nil
 	}
+	r.StatusHistory.Record(req.Name, updatedStatus)
 
 	return suctioncup.Response{}, nil
 }
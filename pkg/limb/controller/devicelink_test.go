@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+	api "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/suctioncup"
+)
+
+// fakeConnectingNeurons serves all models, and fails all connections.
+type fakeConnectingNeurons struct {
+	suctioncup.Neurons
+
+	connects int
+}
+
+func (n *fakeConnectingNeurons) ExistAdaptor(_ string) bool {
+	return true
+}
+
+func (n *fakeConnectingNeurons) SupportModel(_ string, _ metav1.TypeMeta) bool {
+	return true
+}
+
+func (n *fakeConnectingNeurons) Connect(_ map[string]*api.ConnectRequestReferenceEntry, _ *unstructured.Unstructured, _ *edgev1alpha1.DeviceLink) error {
+	n.connects++
+	return errors.New("adaptor is unreachable")
+}
+
+func TestDeviceLinkReconciler_Reconcile_SendRetry(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	_ = edgev1alpha1.AddToScheme(scheme)
+
+	var model = metav1.TypeMeta{APIVersion: "devices.edge.cattle.io/v1alpha1", Kind: "DummySpecialDevice"}
+	var maxRetries = int32(2)
+	var link = &edgev1alpha1.DeviceLink{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "default",
+			Name:       "living-room-fan",
+			Finalizers: []string{ReconcilingDeviceLink},
+		},
+		Spec: edgev1alpha1.DeviceLinkSpec{
+			Adaptor: edgev1alpha1.DeviceAdaptor{Node: "edge-worker", Name: "adaptors.edge.cattle.io/dummy"},
+			Model:   model,
+			SendPolicy: &edgev1alpha1.DeviceLinkSendPolicy{
+				MaxRetries: &maxRetries,
+				Backoff:    &metav1.Duration{Duration: time.Second},
+			},
+		},
+		Status: edgev1alpha1.DeviceLinkStatus{
+			NodeName:    "edge-worker",
+			AdaptorName: "adaptors.edge.cattle.io/dummy",
+			Model:       &model,
+		},
+	}
+	// the device has been created
+	var device = &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+	device.GetObjectKind().SetGroupVersionKind(model.GroupVersionKind())
+	device.SetNamespace("default")
+	device.SetName("living-room-fan")
+	device.SetAnnotations(map[string]string{
+		"edge.cattle.io/node-name":    "edge-worker",
+		"edge.cattle.io/adaptor-name": "adaptors.edge.cattle.io/dummy",
+	})
+	var neurons = &fakeConnectingNeurons{}
+	var r = &DeviceLinkReconciler{
+		Client:        fake.NewFakeClientWithScheme(scheme, link, device),
+		EventRecorder: record.NewFakeRecorder(10),
+		Ctx:           context.Background(),
+		Log:           logf.Log,
+		SuctionCup:    neurons,
+		NodeName:      "edge-worker",
+	}
+	var req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "living-room-fan"}}
+
+	type expected struct {
+		result    ctrl.Result
+		retries   int32
+		connected metav1.ConditionStatus
+	}
+	var testCases = []struct {
+		name     string
+		expected expected
+	}{
+		{
+			name: "retries the first failure",
+			expected: expected{
+				result:    ctrl.Result{RequeueAfter: time.Second},
+				retries:   1,
+				connected: metav1.ConditionUnknown,
+			},
+		},
+		{
+			name: "retries the second failure",
+			expected: expected{
+				result:    ctrl.Result{RequeueAfter: 2 * time.Second},
+				retries:   2,
+				connected: metav1.ConditionUnknown,
+			},
+		},
+		{
+			name: "fails once the retries are exhausted",
+			expected: expected{
+				result:    ctrl.Result{},
+				retries:   0,
+				connected: metav1.ConditionFalse,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var result, err = r.Reconcile(req)
+		assert.NoError(t, err, "case %q", tc.name)
+
+		var actualLink edgev1alpha1.DeviceLink
+		assert.NoError(t, r.Get(r.Ctx, req.NamespacedName, &actualLink), "case %q", tc.name)
+		var actual = expected{
+			result:    result,
+			retries:   actualLink.Status.SendRetries,
+			connected: actualLink.GetDeviceConnectedStatus(),
+		}
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+	assert.Equal(t, len(testCases), neurons.connects)
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/limb/predicate"
+	"github.com/rancher/octopus/pkg/suctioncup"
+)
+
+// NodeAdaptorResyncPeriod is the period to refresh the NodeAdaptor,
+// since the changes of connections are not notified.
+const NodeAdaptorResyncPeriod = 30 * time.Second
+
+// NodeAdaptorReconciler reconciles the NodeAdaptor object of the node
+type NodeAdaptorReconciler struct {
+	client.Client
+
+	Ctx context.Context
+	Log logr.Logger
+
+	SuctionCup suctioncup.Neurons
+	NodeName   string
+}
+
+// +kubebuilder:rbac:groups=edge.cattle.io,resources=nodeadaptors,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=edge.cattle.io,resources=nodeadaptors/status,verbs=get;update;patch
+
+func (r *NodeAdaptorReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	var log = r.Log.WithValues("nodeAdaptor", req.NamespacedName)
+
+	if err := syncNodeAdaptor(r.Ctx, r.Client, r.NodeName, r.SuctionCup); err != nil {
+		log.Error(err, "Unable to sync NodeAdaptor")
+		return ctrl.Result{Requeue: true}, nil
+	}
+	return ctrl.Result{RequeueAfter: NodeAdaptorResyncPeriod}, nil
+}
+
+func (r *NodeAdaptorReconciler) SetupWithManager(ctrlMgr ctrl.Manager) error {
+	// the NodeAdaptor might not be existed when limb starts,
+	// so we trigger the first reconciling to create it.
+	var initial = &edgev1alpha1.NodeAdaptor{}
+	initial.Name = r.NodeName
+	var trigger = make(chan event.GenericEvent, 1)
+	trigger <- event.GenericEvent{Meta: initial, Object: initial}
+
+	return ctrl.NewControllerManagedBy(ctrlMgr).
+		Named("limb_na").
+		For(&edgev1alpha1.NodeAdaptor{}).
+		Watches(
+			&source.Channel{Source: trigger},
+			&handler.EnqueueRequestForObject{},
+		).
+		WithEventFilter(predicate.NodeAdaptorChangedPredicate{NodeName: r.NodeName}).
+		Complete(r)
+}
+
+// syncNodeAdaptor records the registered adaptors into the NodeAdaptor of the node,
+// the NodeAdaptor is created if it is not existed.
+func syncNodeAdaptor(ctx context.Context, cli client.Client, nodeName string, neurons suctioncup.Neurons) error {
+	var nodeAdaptor edgev1alpha1.NodeAdaptor
+	if err := cli.Get(ctx, types.NamespacedName{Name: nodeName}, &nodeAdaptor); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+		nodeAdaptor.Name = nodeName
+		if err := cli.Create(ctx, &nodeAdaptor); err != nil {
+			return err
+		}
+	}
+
+	var infos = neurons.GetAdaptors()
+	var adaptors = make([]edgev1alpha1.RegisteredAdaptor, 0, len(infos))
+	for _, info := range infos {
+		adaptors = append(adaptors, edgev1alpha1.RegisteredAdaptor{
+			Name:        info.Name,
+			Version:     info.Version,
+			Endpoint:    info.Endpoint,
+			Models:      info.Models,
+			Features:    info.Features,
+			Connections: int32(info.Connections),
+			// the time is serialized in seconds.
+			RegistrationTime: metav1.NewTime(info.RegistrationTime).Rfc3339Copy(),
+		})
+	}
+	if len(adaptors) == 0 {
+		adaptors = nil
+	}
+	if equality.Semantic.DeepEqual(nodeAdaptor.Status.Adaptors, adaptors) {
+		return nil
+	}
+
+	nodeAdaptor.Status.Adaptors = adaptors
+	return cli.Status().Update(ctx, &nodeAdaptor)
+}
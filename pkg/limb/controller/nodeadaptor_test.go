@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/suctioncup"
+)
+
+type fakeNeurons struct {
+	suctioncup.Neurons
+
+	adaptors []suctioncup.AdaptorInfo
+}
+
+func (n *fakeNeurons) GetAdaptors() []suctioncup.AdaptorInfo {
+	return n.adaptors
+}
+
+func Test_syncNodeAdaptor(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	_ = edgev1alpha1.AddToScheme(scheme)
+	var ctx = context.Background()
+	var cli = fake.NewFakeClientWithScheme(scheme)
+	var neurons = &fakeNeurons{
+		adaptors: []suctioncup.AdaptorInfo{
+			{
+				Name:             "adaptors.edge.cattle.io/dummy",
+				Endpoint:         "dummy.sock",
+				Models:           []metav1.TypeMeta{{APIVersion: "devices.edge.cattle.io/v1alpha1", Kind: "DummySpecialDevice"}},
+				Connections:      1,
+				RegistrationTime: time.Now(),
+			},
+		},
+	}
+	var getNodeAdaptor = func() edgev1alpha1.NodeAdaptor {
+		var nodeAdaptor edgev1alpha1.NodeAdaptor
+		assert.NoError(t, cli.Get(ctx, types.NamespacedName{Name: "edge-worker"}, &nodeAdaptor))
+		return nodeAdaptor
+	}
+
+	// creates
+	assert.NoError(t, syncNodeAdaptor(ctx, cli, "edge-worker", neurons))
+	var created = getNodeAdaptor()
+	if assert.Len(t, created.Status.Adaptors, 1) {
+		assert.Equal(t, int32(1), created.Status.Adaptors[0].Connections)
+	}
+
+	// doesn't update if nothing changed
+	assert.NoError(t, syncNodeAdaptor(ctx, cli, "edge-worker", neurons))
+	assert.Equal(t, created.ResourceVersion, getNodeAdaptor().ResourceVersion)
+
+	// updates if changed
+	neurons.adaptors[0].Connections = 2
+	assert.NoError(t, syncNodeAdaptor(ctx, cli, "edge-worker", neurons))
+	var updated = getNodeAdaptor()
+	assert.NotEqual(t, created.ResourceVersion, updated.ResourceVersion)
+	if assert.Len(t, updated.Status.Adaptors, 1) {
+		assert.Equal(t, int32(2), updated.Status.Adaptors[0].Connections)
+	}
+}
@@ -0,0 +1,246 @@
+package history
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// History keeps a bounded buffer of the received status snapshots for each device,
+// all methods are safe to call on a nil History.
+type History struct {
+	sync.RWMutex
+
+	size    int
+	devices map[types.NamespacedName]*ring
+}
+
+// NewHistory creates the History which keeps up to size snapshots for each device,
+// it returns nil if the size is not positive.
+func NewHistory(size int) *History {
+	if size <= 0 {
+		return nil
+	}
+	return &History{
+		size:    size,
+		devices: make(map[types.NamespacedName]*ring),
+	}
+}
+
+// Record appends the status of device at now.
+func (h *History) Record(name types.NamespacedName, status interface{}) {
+	h.RecordAt(name, status, time.Now())
+}
+
+// RecordAt appends the status of device at the specified time.
+func (h *History) RecordAt(name types.NamespacedName, status interface{}, timestamp time.Time) {
+	if h == nil {
+		return
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	var r, exist = h.devices[name]
+	if !exist {
+		r = newRing(h.size)
+		h.devices[name] = r
+	}
+	r.push(Snapshot{Timestamp: timestamp, Status: status})
+}
+
+// Remove drops the snapshots of device.
+func (h *History) Remove(name types.NamespacedName) {
+	if h == nil {
+		return
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	delete(h.devices, name)
+}
+
+// List returns the names of all recorded devices in order.
+func (h *History) List() []types.NamespacedName {
+	if h == nil {
+		return nil
+	}
+
+	h.RLock()
+	defer h.RUnlock()
+
+	var ret = make([]types.NamespacedName, 0, len(h.devices))
+	for name := range h.devices {
+		ret = append(ret, name)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].String() < ret[j].String()
+	})
+	return ret
+}
+
+// Query returns the snapshots of device received in the [since, until] range,
+// a zero since or until means unbounded.
+func (h *History) Query(name types.NamespacedName, since, until time.Time) []Snapshot {
+	if h == nil {
+		return nil
+	}
+
+	h.RLock()
+	defer h.RUnlock()
+
+	var r, exist = h.devices[name]
+	if !exist {
+		return nil
+	}
+	return r.list(since, until)
+}
+
+// Point records a value of property received at the specified time.
+type Point struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Value     interface{} `json:"value"`
+}
+
+// QueryProperty returns the values of the device property received in the [since, until] range,
+// the snapshots without the property are skipped.
+func (h *History) QueryProperty(name types.NamespacedName, property string, since, until time.Time) []Point {
+	var snapshots = h.Query(name, since, until)
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	var ret = make([]Point, 0, len(snapshots))
+	for _, s := range snapshots {
+		var value, exist = ExtractProperty(s.Status, property)
+		if !exist {
+			continue
+		}
+		ret = append(ret, Point{Timestamp: s.Timestamp, Value: value})
+	}
+	return ret
+}
+
+// ExtractProperty extracts the value of property from the device status,
+// it supports the "status.properties" as a list of named objects or a map of objects,
+// and falls back to the top level field of status.
+func ExtractProperty(status interface{}, property string) (interface{}, bool) {
+	var statusMap, ok = status.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	switch properties := statusMap["properties"].(type) {
+	case []interface{}:
+		for _, p := range properties {
+			var pm, ok = p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if pm["name"] == property {
+				return pm, true
+			}
+		}
+	case map[string]interface{}:
+		if p, exist := properties[property]; exist {
+			return p, true
+		}
+	}
+
+	var p, exist = statusMap[property]
+	return p, exist
+}
+
+// Load restores the snapshots from the file, it's fine if the file doesn't exist.
+// The restored snapshots are merged before the recorded ones under the same lock of writers,
+// so that it is safe to load while recording.
+func (h *History) Load(path string) error {
+	if h == nil || path == "" {
+		return nil
+	}
+
+	var data, err = ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to read history file %s", path)
+	}
+
+	var records map[string][]Snapshot
+	if err := json.Unmarshal(data, &records); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal history file %s", path)
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	for key, snapshots := range records {
+		var name, ok = parseNamespacedName(key)
+		if !ok {
+			continue
+		}
+		var r = newRing(h.size)
+		for _, s := range snapshots {
+			r.push(s)
+		}
+		if recorded, exist := h.devices[name]; exist {
+			for _, s := range recorded.list(time.Time{}, time.Time{}) {
+				r.push(s)
+			}
+		}
+		h.devices[name] = r
+	}
+	return nil
+}
+
+// Save persists the snapshots into the file.
+func (h *History) Save(path string) error {
+	if h == nil || path == "" {
+		return nil
+	}
+
+	var records = make(map[string][]Snapshot)
+	h.RLock()
+	for name, r := range h.devices {
+		records[name.String()] = r.list(time.Time{}, time.Time{})
+	}
+	h.RUnlock()
+
+	var data, err = json.Marshal(records)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal history")
+	}
+
+	// writes to a temporary file and then renames it,
+	// so that a crash during writing doesn't corrupt the previous file.
+	var dir = filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create history directory %s", dir)
+	}
+	var tmpPath = path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write history file %s", tmpPath)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return errors.Wrapf(err, "failed to rename history file %s", tmpPath)
+	}
+	return nil
+}
+
+func parseNamespacedName(key string) (types.NamespacedName, bool) {
+	for i := 0; i < len(key); i++ {
+		if key[i] == types.Separator {
+			return types.NamespacedName{Namespace: key[:i], Name: key[i+1:]}, true
+		}
+	}
+	return types.NamespacedName{}, false
+}
@@ -0,0 +1,212 @@
+package history
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestHistory_Query(t *testing.T) {
+	var name = types.NamespacedName{Namespace: "default", Name: "dl"}
+	var base = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var h = NewHistory(3)
+	for i := 0; i < 5; i++ {
+		h.RecordAt(name, i, base.Add(time.Duration(i)*time.Minute))
+	}
+
+	var testCases = []struct {
+		name     string
+		since    time.Time
+		until    time.Time
+		expected []Snapshot
+	}{
+		{
+			name: "keeps the latest snapshots",
+			expected: []Snapshot{
+				{Timestamp: base.Add(2 * time.Minute), Status: 2},
+				{Timestamp: base.Add(3 * time.Minute), Status: 3},
+				{Timestamp: base.Add(4 * time.Minute), Status: 4},
+			},
+		},
+		{
+			name:  "since",
+			since: base.Add(3 * time.Minute),
+			expected: []Snapshot{
+				{Timestamp: base.Add(3 * time.Minute), Status: 3},
+				{Timestamp: base.Add(4 * time.Minute), Status: 4},
+			},
+		},
+		{
+			name:  "time range",
+			since: base.Add(time.Minute),
+			until: base.Add(3 * time.Minute),
+			expected: []Snapshot{
+				{Timestamp: base.Add(2 * time.Minute), Status: 2},
+				{Timestamp: base.Add(3 * time.Minute), Status: 3},
+			},
+		},
+		{
+			name:     "out of range",
+			since:    base.Add(5 * time.Minute),
+			expected: []Snapshot{},
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = h.Query(name, tc.since, tc.until)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+
+	h.Remove(name)
+	assert.Nil(t, h.Query(name, time.Time{}, time.Time{}))
+}
+
+func TestHistory_Disabled(t *testing.T) {
+	var name = types.NamespacedName{Namespace: "default", Name: "dl"}
+
+	var h = NewHistory(0)
+	h.Record(name, map[string]interface{}{})
+	assert.Nil(t, h.Query(name, time.Time{}, time.Time{}))
+	assert.Nil(t, h.List())
+	assert.NoError(t, h.Save("history.json"))
+}
+
+func TestExtractProperty(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenStatus   interface{}
+		givenProperty string
+		expected      interface{}
+		expectedExist bool
+	}{
+		{
+			name: "list properties",
+			givenStatus: map[string]interface{}{
+				"properties": []interface{}{
+					map[string]interface{}{"name": "temperature", "value": "20"},
+					map[string]interface{}{"name": "humidity", "value": "60"},
+				},
+			},
+			givenProperty: "humidity",
+			expected:      map[string]interface{}{"name": "humidity", "value": "60"},
+			expectedExist: true,
+		},
+		{
+			name: "map properties",
+			givenStatus: map[string]interface{}{
+				"properties": map[string]interface{}{
+					"temperature": map[string]interface{}{"value": "20"},
+				},
+			},
+			givenProperty: "temperature",
+			expected:      map[string]interface{}{"value": "20"},
+			expectedExist: true,
+		},
+		{
+			name: "top level field",
+			givenStatus: map[string]interface{}{
+				"gear":          "fast",
+				"rotatingSpeed": int64(100),
+			},
+			givenProperty: "rotatingSpeed",
+			expected:      int64(100),
+			expectedExist: true,
+		},
+		{
+			name: "absent property",
+			givenStatus: map[string]interface{}{
+				"properties": []interface{}{
+					map[string]interface{}{"name": "temperature", "value": "20"},
+				},
+			},
+			givenProperty: "humidity",
+			expected:      nil,
+			expectedExist: false,
+		},
+		{
+			name:          "invalid status",
+			givenStatus:   "invalid",
+			givenProperty: "humidity",
+			expected:      nil,
+			expectedExist: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual, actualExist = ExtractProperty(tc.givenStatus, tc.givenProperty)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+		assert.Equal(t, tc.expectedExist, actualExist, "case %q", tc.name)
+	}
+}
+
+func TestHistory_SaveAndLoad(t *testing.T) {
+	var dir, err = ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var name = types.NamespacedName{Namespace: "default", Name: "dl"}
+	var base = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var path = filepath.Join(dir, "history.json")
+
+	var h = NewHistory(2)
+	h.RecordAt(name, map[string]interface{}{"gear": "slow"}, base)
+	h.RecordAt(name, map[string]interface{}{"gear": "fast"}, base.Add(time.Minute))
+	assert.NoError(t, h.Save(path))
+
+	var restored = NewHistory(2)
+	assert.NoError(t, restored.Load(path))
+	assert.Equal(t, []types.NamespacedName{name}, restored.List())
+	assert.Equal(t, h.Query(name, time.Time{}, time.Time{}), restored.Query(name, time.Time{}, time.Time{}))
+}
+
+func TestHistory_LoadWhileRecording(t *testing.T) {
+	var dir, err = ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var name = types.NamespacedName{Namespace: "default", Name: "dl"}
+	var base = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var path = filepath.Join(dir, "history.json")
+
+	var h = NewHistory(3)
+	h.RecordAt(name, "saved-0", base)
+	h.RecordAt(name, "saved-1", base.Add(time.Minute))
+	assert.NoError(t, h.Save(path))
+
+	// the recorded snapshots are kept after the restored ones
+	var restored = NewHistory(3)
+	restored.RecordAt(name, "recorded-0", base.Add(2*time.Minute))
+	restored.RecordAt(name, "recorded-1", base.Add(3*time.Minute))
+	assert.NoError(t, restored.Load(path))
+	var actual []interface{}
+	for _, s := range restored.Query(name, time.Time{}, time.Time{}) {
+		actual = append(actual, s.Status)
+	}
+	assert.Equal(t, []interface{}{"saved-1", "recorded-0", "recorded-1"}, actual)
+
+	// loading concurrently with the writers is safe
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			restored.Record(name, i)
+		}(i)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, restored.Load(path))
+		}()
+	}
+	wg.Wait()
+}
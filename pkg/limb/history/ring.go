@@ -0,0 +1,55 @@
+package history
+
+import (
+	"time"
+)
+
+// Snapshot records a status of device received at the specified time.
+type Snapshot struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Status    interface{} `json:"status,omitempty"`
+}
+
+// ring is a bounded buffer of snapshots,
+// the oldest snapshot is overwritten if the buffer is full.
+type ring struct {
+	items []Snapshot
+	head  int
+	size  int
+}
+
+func newRing(capacity int) *ring {
+	return &ring{items: make([]Snapshot, capacity)}
+}
+
+// push appends the snapshot at the tail of buffer.
+func (r *ring) push(s Snapshot) {
+	var capacity = len(r.items)
+	if capacity == 0 {
+		return
+	}
+	r.items[(r.head+r.size)%capacity] = s
+	if r.size < capacity {
+		r.size++
+		return
+	}
+	r.head = (r.head + 1) % capacity
+}
+
+// list returns the snapshots received in the [since, until] range from oldest to newest,
+// a zero since or until means unbounded.
+func (r *ring) list(since, until time.Time) []Snapshot {
+	var capacity = len(r.items)
+	var ret = make([]Snapshot, 0, r.size)
+	for i := 0; i < r.size; i++ {
+		var s = r.items[(r.head+i)%capacity]
+		if !since.IsZero() && s.Timestamp.Before(since) {
+			continue
+		}
+		if !until.IsZero() && s.Timestamp.After(until) {
+			continue
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
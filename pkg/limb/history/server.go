@@ -0,0 +1,179 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/rancher/octopus/pkg/util/log/handler"
+)
+
+// PathPrefix is the path prefix of the query API,
+//   - GET /v1alpha1/history lists the recorded devices.
+//   - GET /v1alpha1/history/{namespace}/{name} queries the snapshots of device,
+//     accepts "property", "since" and "until" query parameters,
+//     the "since" and "until" can be a RFC3339 time or a duration before now, e.g. "1h".
+const PathPrefix = "/v1alpha1/history"
+
+var log = ctrl.Log.WithName("limb").WithName("history")
+
+type Server struct {
+	history    *History
+	address    string
+	file       string
+	savePeriod time.Duration
+}
+
+// NewServer creates the server to serve the query API of history at the address,
+// and persists the history into the file periodically if the file is not blank.
+// The query API is not authenticated, so the address should be a loopback address in general,
+// which is not exposed by the limb Pod, the API can be queried inside the Pod or via port-forwarding,
+// e.g. "kubectl port-forward pod/<limb pod> 8081".
+func NewServer(history *History, address string, file string, savePeriod time.Duration) *Server {
+	return &Server{
+		history:    history,
+		address:    address,
+		file:       file,
+		savePeriod: savePeriod,
+	}
+}
+
+// Restore restores the history from the file,
+// it should be called before recording to keep the order of snapshots.
+func (s *Server) Restore() {
+	if err := s.history.Load(s.file); err != nil {
+		log.Error(err, "Unable to restore history, starts with empty")
+	}
+}
+
+// Handler returns the http handler of the query API.
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(s.serve)
+}
+
+func (s *Server) Start(stop <-chan struct{}) error {
+	defer utilruntime.HandleCrash(handler.NewPanicsLogHandler(log))
+
+	if s.history == nil {
+		return nil
+	}
+
+	if s.file != "" && s.savePeriod > 0 {
+		go s.saveUntil(stop)
+	}
+
+	var mux = http.NewServeMux()
+	mux.Handle(PathPrefix, s.Handler())
+	mux.Handle(PathPrefix+"/", s.Handler())
+	var srv = &http.Server{Addr: s.address, Handler: mux}
+
+	var errC = make(chan error, 1)
+	go func() {
+		log.Info("Starting history server", "address", s.address)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errC <- err
+		}
+		close(errC)
+	}()
+
+	select {
+	case err := <-errC:
+		return errors.Wrap(err, "failed to serve history")
+	case <-stop:
+	}
+
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error(err, "Unable to shutdown history server")
+	}
+	if err := s.history.Save(s.file); err != nil {
+		log.Error(err, "Unable to persist history")
+	}
+	return nil
+}
+
+func (s *Server) saveUntil(stop <-chan struct{}) {
+	var ticker = time.NewTicker(s.savePeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.history.Save(s.file); err != nil {
+				log.Error(err, "Unable to persist history")
+			}
+		}
+	}
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var path = strings.Trim(strings.TrimPrefix(r.URL.Path, PathPrefix), "/")
+	if path == "" {
+		writeJSON(w, s.history.List())
+		return
+	}
+
+	var segments = strings.Split(path, "/")
+	if len(segments) != 2 || segments[0] == "" || segments[1] == "" {
+		http.Error(w, "path must be in the format of {namespace}/{name}", http.StatusBadRequest)
+		return
+	}
+	var name = types.NamespacedName{Namespace: segments[0], Name: segments[1]}
+
+	var now = time.Now()
+	var query = r.URL.Query()
+	since, err := parseTime(query.Get("since"), now)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid since: %v", err), http.StatusBadRequest)
+		return
+	}
+	until, err := parseTime(query.Get("until"), now)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid until: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if property := query.Get("property"); property != "" {
+		writeJSON(w, s.history.QueryProperty(name, property, since, until))
+		return
+	}
+	writeJSON(w, s.history.Query(name, since, until))
+}
+
+// parseTime parses the given string as a RFC3339 time or a duration before now,
+// it returns a zero time if the given string is blank.
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	var d, err = time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, errors.Errorf("%s is neither a RFC3339 time nor a duration", s)
+	}
+	return now.Add(-d), nil
+}
+
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		log.Error(err, "Unable to write response")
+	}
+}
@@ -0,0 +1,31 @@
+package index
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/rancher/octopus/pkg/util/object"
+)
+
+const DeviceActionByDeviceLinkField = "deviceActionByDeviceLink"
+
+var deviceActionByDeviceLinkIndexLog = ctrl.Log.WithName("index").WithName(DeviceActionByDeviceLinkField)
+
+func DeviceActionByDeviceLinkFunc(rawObj runtime.Object) []string {
+	var action = object.ToDeviceActionObject(rawObj)
+	if action == nil {
+		return nil
+	}
+
+	// rejects if the action has been completed
+	if action.Status.IsCompleted() {
+		return nil
+	}
+
+	var linkName = action.Spec.DeviceLink
+	if linkName != "" {
+		deviceActionByDeviceLinkIndexLog.V(6).Info("Indexed", "deviceLink", linkName, "object", object.GetNamespacedName(action))
+		return []string{linkName}
+	}
+	return nil
+}
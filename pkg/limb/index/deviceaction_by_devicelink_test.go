@@ -0,0 +1,56 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+)
+
+func TestDeviceActionByDeviceLinkFunc(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		given    runtime.Object
+		expected []string
+	}{
+		{
+			name: "non-empty link",
+			given: &edgev1alpha1.DeviceAction{
+				Spec: edgev1alpha1.DeviceActionSpec{
+					DeviceLink: "test",
+				},
+			},
+			expected: []string{"test"},
+		},
+		{
+			name: "non-empty link but completed",
+			given: &edgev1alpha1.DeviceAction{
+				Spec: edgev1alpha1.DeviceActionSpec{
+					DeviceLink: "test",
+				},
+				Status: edgev1alpha1.DeviceActionStatus{
+					Phase: edgev1alpha1.DeviceActionSucceeded,
+				},
+			},
+			expected: nil,
+		},
+		{
+			name:     "empty link",
+			given:    &edgev1alpha1.DeviceAction{},
+			expected: nil,
+		},
+		{
+			name:     "non-DeviceAction object",
+			given:    &corev1.Node{},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = DeviceActionByDeviceLinkFunc(tc.given)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
@@ -0,0 +1,89 @@
+package index
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+	"github.com/rancher/octopus/pkg/util/object"
+)
+
+const (
+	DeviceLinkBySecretField    = "deviceLinkBySecret"
+	DeviceLinkByConfigMapField = "deviceLinkByConfigMap"
+)
+
+var (
+	deviceLinkBySecretIndexLog    = ctrl.Log.WithName("index").WithName(DeviceLinkBySecretField)
+	deviceLinkByConfigMapIndexLog = ctrl.Log.WithName("index").WithName(DeviceLinkByConfigMapField)
+)
+
+func DeviceLinkBySecretFuncFactory(nodeName string) func(runtime.Object) []string {
+	return func(rawObj runtime.Object) []string {
+		var link = object.ToDeviceLinkObject(rawObj)
+		if link == nil {
+			return nil
+		}
+
+		// rejects if not the target node
+		if link.Status.NodeName != nodeName {
+			return nil
+		}
+
+		var secretNames = getReferenceNames(link, func(ref edgev1alpha1.DeviceLinkReference) string {
+			if ref.Secret == nil {
+				return ""
+			}
+			return ref.Secret.Name
+		})
+		if len(secretNames) != 0 {
+			deviceLinkBySecretIndexLog.V(6).Info("Indexed", "secretNames", secretNames, "object", object.GetNamespacedName(link))
+			return secretNames
+		}
+		return nil
+	}
+}
+
+func DeviceLinkByConfigMapFuncFactory(nodeName string) func(runtime.Object) []string {
+	return func(rawObj runtime.Object) []string {
+		var link = object.ToDeviceLinkObject(rawObj)
+		if link == nil {
+			return nil
+		}
+
+		// rejects if not the target node
+		if link.Status.NodeName != nodeName {
+			return nil
+		}
+
+		var configMapNames = getReferenceNames(link, func(ref edgev1alpha1.DeviceLinkReference) string {
+			if ref.ConfigMap == nil {
+				return ""
+			}
+			return ref.ConfigMap.Name
+		})
+		if len(configMapNames) != 0 {
+			deviceLinkByConfigMapIndexLog.V(6).Info("Indexed", "configMapNames", configMapNames, "object", object.GetNamespacedName(link))
+			return configMapNames
+		}
+		return nil
+	}
+}
+
+// getReferenceNames returns the distinct non-blank names picked from the references of link.
+func getReferenceNames(link *edgev1alpha1.DeviceLink, pick func(edgev1alpha1.DeviceLinkReference) string) []string {
+	var names []string
+	var seen = map[string]struct{}{}
+	for _, ref := range link.Spec.References {
+		var name = pick(ref)
+		if name == "" {
+			continue
+		}
+		if _, exist := seen[name]; exist {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,162 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+)
+
+func TestDeviceLinkBySecretFuncFactory(t *testing.T) {
+	var targetNode = "edge-worker"
+	var nonTargetNode = "edge-worker1"
+
+	var testCases = []struct {
+		name     string
+		given    runtime.Object
+		expected []string
+	}{
+		{
+			name: "referred secrets but non-target node",
+			given: &edgev1alpha1.DeviceLink{
+				Spec: edgev1alpha1.DeviceLinkSpec{
+					References: []edgev1alpha1.DeviceLinkReference{
+						{
+							Name: "credential",
+							DeviceLinkReferenceSource: edgev1alpha1.DeviceLinkReferenceSource{
+								Secret: &edgev1alpha1.DeviceLinkReferenceSecretSource{
+									Name: "mqtt-credential",
+								},
+							},
+						},
+					},
+				},
+				Status: edgev1alpha1.DeviceLinkStatus{
+					NodeName: nonTargetNode,
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "referred secrets",
+			given: &edgev1alpha1.DeviceLink{
+				Spec: edgev1alpha1.DeviceLinkSpec{
+					References: []edgev1alpha1.DeviceLinkReference{
+						{
+							Name: "credential",
+							DeviceLinkReferenceSource: edgev1alpha1.DeviceLinkReferenceSource{
+								Secret: &edgev1alpha1.DeviceLinkReferenceSecretSource{
+									Name: "mqtt-credential",
+								},
+							},
+						},
+						{
+							Name: "tls",
+							DeviceLinkReferenceSource: edgev1alpha1.DeviceLinkReferenceSource{
+								Secret: &edgev1alpha1.DeviceLinkReferenceSecretSource{
+									Name: "mqtt-tls",
+								},
+							},
+						},
+						{
+							Name: "ca",
+							DeviceLinkReferenceSource: edgev1alpha1.DeviceLinkReferenceSource{
+								Secret: &edgev1alpha1.DeviceLinkReferenceSecretSource{
+									Name: "mqtt-tls",
+								},
+							},
+						},
+						{
+							Name: "config",
+							DeviceLinkReferenceSource: edgev1alpha1.DeviceLinkReferenceSource{
+								ConfigMap: &edgev1alpha1.DeviceLinkReferenceConfigMapSource{
+									Name: "mqtt-config",
+								},
+							},
+						},
+					},
+				},
+				Status: edgev1alpha1.DeviceLinkStatus{
+					NodeName: targetNode,
+				},
+			},
+			expected: []string{"mqtt-credential", "mqtt-tls"},
+		},
+		{
+			name: "without secrets",
+			given: &edgev1alpha1.DeviceLink{
+				Status: edgev1alpha1.DeviceLinkStatus{
+					NodeName: targetNode,
+				},
+			},
+			expected: nil,
+		},
+		{
+			name:     "non-DeviceLink object",
+			given:    &corev1.Node{},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = DeviceLinkBySecretFuncFactory(targetNode)(tc.given)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
+func TestDeviceLinkByConfigMapFuncFactory(t *testing.T) {
+	var targetNode = "edge-worker"
+
+	var testCases = []struct {
+		name     string
+		given    runtime.Object
+		expected []string
+	}{
+		{
+			name: "referred configmaps",
+			given: &edgev1alpha1.DeviceLink{
+				Spec: edgev1alpha1.DeviceLinkSpec{
+					References: []edgev1alpha1.DeviceLinkReference{
+						{
+							Name: "credential",
+							DeviceLinkReferenceSource: edgev1alpha1.DeviceLinkReferenceSource{
+								Secret: &edgev1alpha1.DeviceLinkReferenceSecretSource{
+									Name: "mqtt-credential",
+								},
+							},
+						},
+						{
+							Name: "config",
+							DeviceLinkReferenceSource: edgev1alpha1.DeviceLinkReferenceSource{
+								ConfigMap: &edgev1alpha1.DeviceLinkReferenceConfigMapSource{
+									Name: "mqtt-config",
+								},
+							},
+						},
+					},
+				},
+				Status: edgev1alpha1.DeviceLinkStatus{
+					NodeName: targetNode,
+				},
+			},
+			expected: []string{"mqtt-config"},
+		},
+		{
+			name: "without configmaps",
+			given: &edgev1alpha1.DeviceLink{
+				Status: edgev1alpha1.DeviceLinkStatus{
+					NodeName: targetNode,
+				},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = DeviceLinkByConfigMapFuncFactory(targetNode)(tc.given)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
@@ -4,6 +4,7 @@
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -17,6 +18,7 @@
 	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
 	"github.com/rancher/octopus/cmd/limb/options"
 	"github.com/rancher/octopus/pkg/limb/controller"
+	"github.com/rancher/octopus/pkg/limb/history"
 	"github.com/rancher/octopus/pkg/metrics"
 	"github.com/rancher/octopus/pkg/suctioncup"
 	"github.com/rancher/octopus/pkg/util/critical"
@@ -69,12 +71,17 @@ func Run(name string, opts *options.Options) error {
 	}
 
 	log.V(0).Info("Creating suction cup manager")
-	suctionCupMgr, err := suctioncup.NewManager()
+	suctionCupMgr, err := suctioncup.NewManager(opts.AdaptorGracePeriod)
 	if err != nil {
 		log.Error(err, "Unable to start suction cup manager")
 		return err
 	}
 
+	log.V(0).Info("Creating status history server")
+	var statusHistory = history.NewHistory(opts.HistorySize)
+	var historySrv = history.NewServer(statusHistory, opts.HistoryAddr, opts.HistoryFile, time.Minute)
+	historySrv.Restore()
+
 	log.V(0).Info("Creating controllers")
 	if err = (&controller.DeviceLinkReconciler{
 		Client:        controllerMgr.GetClient(),
@@ -83,10 +90,32 @@ func Run(name string, opts *options.Options) error {
 		Log:           ctrl.Log.WithName("controller").WithName("deviceLink"),
 		SuctionCup:    suctionCupMgr.GetNeurons(),
 		NodeName:      nodeName,
+		StatusHistory: statusHistory,
 	}).SetupWithManager(controllerMgr, suctionCupMgr); err != nil {
 		log.Error(err, "Unable to create controller", "controller", "DeviceLink")
 		return err
 	}
+	if err = (&controller.DeviceActionReconciler{
+		Client:        controllerMgr.GetClient(),
+		EventRecorder: controllerMgr.GetEventRecorderFor(name),
+		Ctx:           ctx,
+		Log:           ctrl.Log.WithName("controller").WithName("deviceAction"),
+		SuctionCup:    suctionCupMgr.GetNeurons(),
+		NodeName:      nodeName,
+	}).SetupWithManager(controllerMgr); err != nil {
+		log.Error(err, "Unable to create controller", "controller", "DeviceAction")
+		return err
+	}
+	if err = (&controller.NodeAdaptorReconciler{
+		Client:     controllerMgr.GetClient(),
+		Ctx:        ctx,
+		Log:        ctrl.Log.WithName("controller").WithName("nodeAdaptor"),
+		SuctionCup: suctionCupMgr.GetNeurons(),
+		NodeName:   nodeName,
+	}).SetupWithManager(controllerMgr); err != nil {
+		log.Error(err, "Unable to create controller", "controller", "NodeAdaptor")
+		return err
+	}
 
 	log.Info("Starting")
 	var stop = ctrl.SetupSignalHandler()
@@ -98,6 +127,9 @@ func Run(name string, opts *options.Options) error {
 	eg.Go(func() error {
 		return controllerMgr.Start(stop)
 	})
+	eg.Go(func() error {
+		return historySrv.Start(stop)
+	})
 	if err = eg.Wait(); err != nil {
 		log.Error(err, "Problem running")
 		return err
@@ -6,6 +6,7 @@
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
 	"github.com/rancher/octopus/pkg/util/object"
 )
 
@@ -68,6 +69,12 @@ func (p DeviceLinkChangedPredicate) Update(e event.UpdateEvent) bool {
 	var dl = object.ToDeviceLinkObject(e.ObjectNew)
 	// rejects if not the target node
 	if dl.Spec.Adaptor.Node != p.NodeName && dl.Status.NodeName != p.NodeName {
+		// accepts if the link is rescheduled from the target node,
+		// so that the previous connection can be disconnected.
+		if object.ToDeviceLinkObject(e.ObjectOld).Status.NodeName == p.NodeName {
+			deviceLinkChangedPredicateLog.V(5).Info("Accept UpdateEvent as the link is rescheduled to another node", "object", object.GetNamespacedName(e.MetaOld))
+			return true
+		}
 		return false
 	}
 
@@ -76,6 +83,11 @@ func (p DeviceLinkChangedPredicate) Update(e event.UpdateEvent) bool {
 		return true
 	}
 
+	if isReconnectionScheduled(object.ToDeviceLinkObject(e.ObjectOld), dl) {
+		deviceLinkChangedPredicateLog.V(5).Info("Accept UpdateEvent as the reconnection is scheduled", "object", object.GetNamespacedName(e.MetaOld))
+		return true
+	}
+
 	if dl.GetNodeExistedStatus() == metav1.ConditionFalse {
 		deviceLinkChangedPredicateLog.V(5).Info("Accept UpdateEvent as the NodeExisted status is failed", "object", object.GetNamespacedName(e.MetaOld))
 		return true
@@ -99,3 +111,16 @@ func (p DeviceLinkChangedPredicate) Update(e event.UpdateEvent) bool {
 
 	return false
 }
+
+// isReconnectionScheduled returns true if a new reconnection is scheduled.
+func isReconnectionScheduled(oldDL, newDL *edgev1alpha1.DeviceLink) bool {
+	var newReconnection = newDL.Status.Reconnection
+	if newReconnection == nil || newReconnection.NextRetryTime == nil {
+		return false
+	}
+	var oldReconnection = oldDL.Status.Reconnection
+	if oldReconnection == nil || oldReconnection.NextRetryTime == nil {
+		return true
+	}
+	return !oldReconnection.NextRetryTime.Equal(newReconnection.NextRetryTime)
+}
@@ -2,6 +2,7 @@
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -172,6 +173,50 @@ func TestDeviceLinkChangedPredicate_Update(t *testing.T) {
 			),
 			expected: true,
 		},
+		{
+			name: "same generation but scheduled a reconnection",
+			given: generateUpdateEvent(
+				&edgev1alpha1.DeviceLink{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:  "default",
+						Name:       "test",
+						Generation: 1,
+					},
+					Spec: edgev1alpha1.DeviceLinkSpec{
+						Adaptor: edgev1alpha1.DeviceAdaptor{
+							Name: "adaptors.test.io/dummy",
+							Node: targetNode,
+						},
+					},
+					Status: edgev1alpha1.DeviceLinkStatus{
+						AdaptorName: "adaptors.test.io/dummy",
+						NodeName:    targetNode,
+					},
+				},
+				&edgev1alpha1.DeviceLink{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:  "default",
+						Name:       "test",
+						Generation: 1,
+					},
+					Spec: edgev1alpha1.DeviceLinkSpec{
+						Adaptor: edgev1alpha1.DeviceAdaptor{
+							Name: "adaptors.test.io/dummy",
+							Node: targetNode,
+						},
+					},
+					Status: edgev1alpha1.DeviceLinkStatus{
+						AdaptorName: "adaptors.test.io/dummy",
+						NodeName:    targetNode,
+						Reconnection: &edgev1alpha1.DeviceLinkReconnectionStatus{
+							Attempts:      1,
+							NextRetryTime: &metav1.Time{Time: time.Now().Add(5 * time.Second)},
+						},
+					},
+				},
+			),
+			expected: true,
+		},
 		{
 			name: "request another node",
 			given: generateUpdateEvent(
@@ -208,6 +253,49 @@ func TestDeviceLinkChangedPredicate_Update(t *testing.T) {
 			),
 			expected: false,
 		},
+		{
+			name: "rescheduled from the same node to another node",
+			given: generateUpdateEvent(
+				&edgev1alpha1.DeviceLink{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:  "default",
+						Name:       "test",
+						Generation: 1,
+					},
+					Spec: edgev1alpha1.DeviceLinkSpec{
+						Adaptor: edgev1alpha1.DeviceAdaptor{
+							Name: "adaptors.test.io/dummy",
+							NodeSelector: map[string]string{
+								"edge.cattle.io/gateway": "true",
+							},
+						},
+					},
+					Status: edgev1alpha1.DeviceLinkStatus{
+						AdaptorName: "adaptors.test.io/dummy",
+						NodeName:    targetNode,
+					},
+				},
+				&edgev1alpha1.DeviceLink{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:  "default",
+						Name:       "test",
+						Generation: 1,
+					},
+					Spec: edgev1alpha1.DeviceLinkSpec{
+						Adaptor: edgev1alpha1.DeviceAdaptor{
+							Name: "adaptors.test.io/dummy",
+							NodeSelector: map[string]string{
+								"edge.cattle.io/gateway": "true",
+							},
+						},
+					},
+					Status: edgev1alpha1.DeviceLinkStatus{
+						NodeName: nonTargetNode,
+					},
+				},
+			),
+			expected: true,
+		},
 	}
 
 	var predication = DeviceLinkChangedPredicate{NodeName: targetNode}
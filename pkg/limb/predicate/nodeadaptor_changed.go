@@ -0,0 +1,54 @@
+package predicate
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+var nodeAdaptorChangedPredicateLog = ctrl.Log.WithName("predicate").WithName("nodeAdaptorChanged")
+
+type NodeAdaptorChangedPredicate struct {
+	predicate.Funcs
+
+	NodeName string
+}
+
+func (p NodeAdaptorChangedPredicate) Create(e event.CreateEvent) bool {
+	if e.Meta == nil || e.Object == nil {
+		return false
+	}
+
+	if e.Meta.GetName() == p.NodeName {
+		nodeAdaptorChangedPredicateLog.V(5).Info("Accept CreateEvent as requested the same node", "object", e.Meta.GetName())
+		return true
+	}
+	return false
+}
+
+func (p NodeAdaptorChangedPredicate) Delete(e event.DeleteEvent) bool {
+	if e.Meta == nil || e.Object == nil {
+		return false
+	}
+
+	// the NodeAdaptor should be recreated if it is deleted accidentally.
+	if e.Meta.GetName() == p.NodeName {
+		nodeAdaptorChangedPredicateLog.V(5).Info("Accept DeleteEvent as requested the same node", "object", e.Meta.GetName())
+		return true
+	}
+	return false
+}
+
+func (p NodeAdaptorChangedPredicate) Update(e event.UpdateEvent) bool {
+	// limb is the only writer of its NodeAdaptor,
+	// so the updates are triggered by limb itself.
+	return false
+}
+
+func (p NodeAdaptorChangedPredicate) Generic(e event.GenericEvent) bool {
+	if e.Meta == nil || e.Object == nil {
+		return false
+	}
+
+	return e.Meta.GetName() == p.NodeName
+}
@@ -0,0 +1,96 @@
+package predicate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+)
+
+func TestNodeAdaptorChangedPredicate_Create(t *testing.T) {
+	var targetNode = "edge-worker"
+	var nonTargetNode = "edge-worker1"
+
+	var testCases = []struct {
+		name     string
+		given    event.CreateEvent
+		expected bool
+	}{
+		{
+			name:     "without object",
+			given:    event.CreateEvent{},
+			expected: false,
+		},
+		{
+			name: "requested the same node",
+			given: generateCreateEvent(&edgev1alpha1.NodeAdaptor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: targetNode,
+				},
+			}),
+			expected: true,
+		},
+		{
+			name: "requested another node",
+			given: generateCreateEvent(&edgev1alpha1.NodeAdaptor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: nonTargetNode,
+				},
+			}),
+			expected: false,
+		},
+	}
+
+	var predication = NodeAdaptorChangedPredicate{
+		NodeName: targetNode,
+	}
+	for _, tc := range testCases {
+		var actual = predication.Create(tc.given)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
+func TestNodeAdaptorChangedPredicate_Update(t *testing.T) {
+	var targetNode = "edge-worker"
+
+	var testCases = []struct {
+		name     string
+		given    event.UpdateEvent
+		expected bool
+	}{
+		{
+			name: "updated by limb",
+			given: generateUpdateEvent(
+				&edgev1alpha1.NodeAdaptor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: targetNode,
+					},
+				},
+				&edgev1alpha1.NodeAdaptor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: targetNode,
+					},
+					Status: edgev1alpha1.NodeAdaptorStatus{
+						Adaptors: []edgev1alpha1.RegisteredAdaptor{
+							{
+								Name: "adaptors.test.io/dummy",
+							},
+						},
+					},
+				},
+			),
+			expected: false,
+		},
+	}
+
+	var predication = NodeAdaptorChangedPredicate{
+		NodeName: targetNode,
+	}
+	for _, tc := range testCases {
+		var actual = predication.Update(tc.given)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
@@ -13,6 +13,13 @@ type runtimeMetaObject interface {
 	metav1.Object
 }
 
+func generateCreateEvent(o runtimeMetaObject) event.CreateEvent {
+	return event.CreateEvent{
+		Meta:   o,
+		Object: o,
+	}
+}
+
 func generateUpdateEvent(o, n runtimeMetaObject) event.UpdateEvent {
 	return event.UpdateEvent{
 		MetaOld:   o,
@@ -2,6 +2,7 @@
 
 import (
 	"github.com/rancher/octopus/pkg/metrics/limb"
+	"github.com/rancher/octopus/pkg/metrics/mqtt"
 )
 
 // alias limb package
@@ -9,3 +10,9 @@
 	RegisterLimbMetrics    = limb.RegisterMetrics
 	GetLimbMetricsRecorder = limb.GetMetricsRecorder
 )
+
+// alias mqtt package
+var (
+	RegisterMQTTMetrics    = mqtt.RegisterMetrics
+	GetMQTTMetricsRecorder = mqtt.GetMetricsRecorder
+)
@@ -36,6 +36,15 @@
 		[]string{"adaptor"},
 	)
 
+	sendRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "send_retries_total",
+			Help:      "Total number of retries of sending device desired to adaptor.",
+		},
+		[]string{"adaptor"},
+	)
+
 	sendLatency = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: namespace,
@@ -51,6 +60,7 @@ func RegisterMetrics(registry prometheus.Registerer) error {
 		connections,
 		connectErrors,
 		sendErrors,
+		sendRetries,
 		sendLatency,
 	}
 
@@ -80,6 +90,9 @@ type MetricsRecorder interface {
 
 	// IncreaseSendErrors increases the error counter when failed to send to adaptor.
 	IncreaseSendErrors(adaptorName string)
+
+	// IncreaseSendRetries increases the retry counter when retrying to send to adaptor.
+	IncreaseSendRetries(adaptorName string)
 }
 
 type metricsRecorder struct{}
@@ -108,6 +121,10 @@ func (metricsRecorder) IncreaseSendErrors(adaptorName string) {
 	sendErrors.WithLabelValues(adaptorName).Inc()
 }
 
+func (metricsRecorder) IncreaseSendRetries(adaptorName string) {
+	sendRetries.WithLabelValues(adaptorName).Inc()
+}
+
 var recorder = metricsRecorder{}
 
 func GetMetricsRecorder() MetricsRecorder {
@@ -0,0 +1,152 @@
+package mqtt
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	namespace = "mqtt"
+
+	bufferMessages = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "buffer_messages",
+			Help:      "How many messages are buffered while the broker is unreachable.",
+		},
+		[]string{"client"},
+	)
+
+	bufferBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "buffer_bytes",
+			Help:      "How many bytes are buffered while the broker is unreachable.",
+		},
+		[]string{"client"},
+	)
+
+	bufferDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "buffer_dropped_messages_total",
+			Help:      "Total number of buffered messages dropped by the quota.",
+		},
+		[]string{"client"},
+	)
+
+	bufferReplayed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "buffer_replayed_messages_total",
+			Help:      "Total number of buffered messages replayed after reconnected.",
+		},
+		[]string{"client"},
+	)
+
+	publishBatched = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "publish_batched_messages_total",
+			Help:      "Total number of messages packed into batches.",
+		},
+		[]string{"client"},
+	)
+
+	publishCoalesced = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "publish_coalesced_messages_total",
+			Help:      "Total number of messages replaced by the newer ones while rate limited.",
+		},
+		[]string{"client"},
+	)
+
+	publishDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "publish_dropped_messages_total",
+			Help:      "Total number of messages dropped as the batch is full while rate limited.",
+		},
+		[]string{"client"},
+	)
+)
+
+func RegisterMetrics(registry prometheus.Registerer) error {
+	var collectors = []prometheus.Collector{
+		bufferMessages,
+		bufferBytes,
+		bufferDropped,
+		bufferReplayed,
+		publishBatched,
+		publishCoalesced,
+		publishDropped,
+	}
+
+	for _, collector := range collectors {
+		if err := registry.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type MetricsRecorder interface {
+	// SetBufferDepth sets the number and the bytes of buffered messages.
+	SetBufferDepth(clientName string, messages int, bytes int64)
+
+	// DeleteBufferDepth deletes the depth metrics of the closed client.
+	DeleteBufferDepth(clientName string)
+
+	// IncreaseBufferDropped increases the counter of dropped messages.
+	IncreaseBufferDropped(clientName string, count int)
+
+	// IncreaseBufferReplayed increases the counter of replayed messages.
+	IncreaseBufferReplayed(clientName string, count int)
+
+	// IncreasePublishBatched increases the counter of batched messages.
+	IncreasePublishBatched(clientName string, count int)
+
+	// IncreasePublishCoalesced increases the counter of coalesced messages.
+	IncreasePublishCoalesced(clientName string, count int)
+
+	// IncreasePublishDropped increases the counter of dropped messages.
+	IncreasePublishDropped(clientName string, count int)
+}
+
+type metricsRecorder struct{}
+
+func (metricsRecorder) SetBufferDepth(clientName string, messages int, bytes int64) {
+	bufferMessages.WithLabelValues(clientName).Set(float64(messages))
+	bufferBytes.WithLabelValues(clientName).Set(float64(bytes))
+}
+
+func (metricsRecorder) DeleteBufferDepth(clientName string) {
+	bufferMessages.DeleteLabelValues(clientName)
+	bufferBytes.DeleteLabelValues(clientName)
+}
+
+func (metricsRecorder) IncreaseBufferDropped(clientName string, count int) {
+	bufferDropped.WithLabelValues(clientName).Add(float64(count))
+}
+
+func (metricsRecorder) IncreaseBufferReplayed(clientName string, count int) {
+	bufferReplayed.WithLabelValues(clientName).Add(float64(count))
+}
+
+func (metricsRecorder) IncreasePublishBatched(clientName string, count int) {
+	publishBatched.WithLabelValues(clientName).Add(float64(count))
+}
+
+func (metricsRecorder) IncreasePublishCoalesced(clientName string, count int) {
+	publishCoalesced.WithLabelValues(clientName).Add(float64(count))
+}
+
+func (metricsRecorder) IncreasePublishDropped(clientName string, count int) {
+	publishDropped.WithLabelValues(clientName).Add(float64(count))
+}
+
+var recorder = metricsRecorder{}
+
+func GetMetricsRecorder() MetricsRecorder {
+	return recorder
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
@@ -94,6 +95,52 @@ type MQTTClientStore struct {
 	DirectoryPrefix string `json:"directoryPrefix,omitempty"`
 }
 
+// MQTTClientBufferDropPolicy defines the policy to drop messages when the buffer is full.
+// Oldest: Drops the oldest messages until the new message fits.
+// Newest: Drops the new message and keeps the buffered messages.
+// Sample: Drops every other message until the new message fits, to keep the whole period in lower resolution.
+// The expired messages are dropped before applying the policy, and a message larger than the buffer is always dropped.
+// +kubebuilder:validation:Enum=Oldest;Newest;Sample
+type MQTTClientBufferDropPolicy string
+
+// MQTTClientBuffer defines the buffer for publishing messages while the broker is unreachable.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type MQTTClientBuffer struct {
+	// Specifies the type of storage.
+	// The default value is "Memory".
+	// +kubebuilder:default="Memory"
+	// +optional
+	Type MQTTClientStorageType `json:"type,omitempty"`
+
+	// Specifies the directory prefix of the storage, if using file store.
+	// The default value is "/var/run/octopus/mqtt-buffer".
+	// +kubebuilder:validation:Pattern="^/.*[^/]$"
+	// +optional
+	DirectoryPrefix string `json:"directoryPrefix,omitempty"`
+
+	// Specifies the maximum size of the buffered messages.
+	// The default value is "64Mi", which is also used if the value is not positive.
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+
+	// Specifies the maximum age of the buffered messages,
+	// the older messages are dropped.
+	// A duration of 0 keeps the messages until the buffer is full.
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+
+	// Specifies the policy to drop messages when the buffer is full.
+	// The default value is "Oldest".
+	// +kubebuilder:default="Oldest"
+	// +optional
+	DropPolicy MQTTClientBufferDropPolicy `json:"dropPolicy,omitempty"`
+}
+
+// MQTTClientServer defines the server URI of MQTT broker, the format should be `schema://host:port`.
+// +kubebuilder:validation:Pattern="^(ws|wss|tcp|unix|ssl|tls|tcps)+://[^\\s]*$"
+type MQTTClientServer string
+
 // MQTTClientOptions defines the options of MQTT client.
 // +kubebuilder:object:generate=true
 // +kubebuilder:object:root=false
@@ -104,10 +151,19 @@ type MQTTClientOptions struct {
 	// +kubebuilder:validation:Required
 	Server string `json:"server"`
 
+	// Specifies the ordered list of backup brokers' server URI,
+	// the client tries the primary server first and then the backup servers in order
+	// during connecting and reconnecting.
+	// The connected server is reported in the status only in MQTT v5,
+	// since MQTT v3.x client cannot tell which server is connected when having the backup servers.
+	// +optional
+	BackupServers []MQTTClientServer `json:"backupServers,omitempty"`
+
 	// Specifies the MQTT protocol version that the cluster uses to connect to broker.
-	// Legitimate values are currently 3 - MQTT v3.1 or 4 - MQTT v3.1.1.
+	// Legitimate values are currently 3 - MQTT v3.1, 4 - MQTT v3.1.1 or 5 - MQTT v5.
 	// The default value is 0, which means MQTT v3.1.1 identification is preferred.
-	// +kubebuilder:validation:Enum=0;3;4
+	// MQTT v5 doesn't support the "ws" and "wss" schemas.
+	// +kubebuilder:validation:Enum=0;3;4;5
 	// +kubebuilder:default=0
 	// +optional
 	ProtocolVersion *uint `json:"protocolVersion,omitempty"`
@@ -136,6 +192,11 @@ type MQTTClientOptions struct {
 	// +optional
 	Store *MQTTClientStore `json:"store,omitempty"`
 
+	// Specifies to buffer the publishing messages while the broker is unreachable,
+	// the buffered messages are published in order after reconnected.
+	// +optional
+	Buffer *MQTTClientBuffer `json:"buffer,omitempty"`
+
 	// Specifies to enable resuming of stored (un)subscribe messages when connecting but not reconnecting.
 	// This is only valid if `CleanSession` is false.
 	// The default value is "false".
@@ -223,6 +284,21 @@ type MQTTClientOptions struct {
 	// +optional
 	MessageChannelDepth *uint `json:"messageChannelDepth,omitempty"`
 
+	// Specifies the amount of time that the broker keeps the session after the client disconnects.
+	// This is only valid if `ProtocolVersion` is 5.
+	// The session ends when the connection is closed if not set.
+	// +optional
+	SessionExpiryInterval *metav1.Duration `json:"sessionExpiryInterval,omitempty"`
+
+	// Specifies the maximum number of topic aliases that the client accepts from the broker,
+	// it also limits the number of topic aliases that the client uses when publishing,
+	// the topic alias reduces the size of publishing messages on the same topic.
+	// This is only valid if `ProtocolVersion` is 5.
+	// The default value is "0", which means the topic alias is disabled.
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	TopicAliasMaximum *uint `json:"topicAliasMaximum,omitempty"`
+
 	// Specifies the additional HTTP headers that the client sends in the WebSocket opening handshake.
 	// +optional
 	// +mapType=atomic
@@ -1,6 +1,10 @@
 package api
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
 	"github.com/rancher/octopus/pkg/util/converter"
 )
 
@@ -111,6 +115,160 @@ type MQTTWillMessage struct {
 	Content MQTTWillMessageContent `json:"content"`
 }
 
+// MQTTMessageUserProperty defines the user property of MQTT v5 message.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type MQTTMessageUserProperty struct {
+	// Specifies the name of user property.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Specifies the value of user property.
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// MQTTMessageProperties defines the properties of MQTT v5 message.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type MQTTMessageProperties struct {
+	// Specifies the MIME type of the payload, e.g. "application/json".
+	// +optional
+	ContentType string `json:"contentType,omitempty"`
+
+	// Specifies the topic for the receiver to publish the response message,
+	// it's used in the request/response pattern.
+	// +optional
+	ResponseTopic string `json:"responseTopic,omitempty"`
+
+	// Specifies the data for the requester to identify which request the response message is for,
+	// it's used in the request/response pattern.
+	// +optional
+	CorrelationData string `json:"correlationData,omitempty"`
+
+	// Specifies the lifetime of the message,
+	// the broker discards the message if it cannot be delivered within this interval.
+	// The message never expires if not set.
+	// +optional
+	MessageExpiryInterval *metav1.Duration `json:"messageExpiryInterval,omitempty"`
+
+	// Specifies the user properties of the message.
+	// +optional
+	UserProperties []MQTTMessageUserProperty `json:"userProperties,omitempty"`
+}
+
+// MQTTMessagePayloadCodecType defines the type of codec for encoding/decoding the payload.
+// +kubebuilder:validation:Enum=JSON;CBOR;MessagePack;Protobuf
+type MQTTMessagePayloadCodecType string
+
+const (
+	MQTTMessagePayloadCodecTypeJSON        MQTTMessagePayloadCodecType = "JSON"
+	MQTTMessagePayloadCodecTypeCBOR        MQTTMessagePayloadCodecType = "CBOR"
+	MQTTMessagePayloadCodecTypeMessagePack MQTTMessagePayloadCodecType = "MessagePack"
+	MQTTMessagePayloadCodecTypeProtobuf    MQTTMessagePayloadCodecType = "Protobuf"
+)
+
+// MQTTMessagePayloadProtobufCodec defines the options of Protocol Buffers codec.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type MQTTMessagePayloadProtobufCodec struct {
+	// Specifies the relationship of DeviceLink's references to
+	// refer to the FileDescriptorSet generated by `protoc --include_imports --descriptor_set_out`,
+	// the content can be raw bytes or a base64 encoded string.
+	// +kubebuilder:validation:Required
+	DescriptorSetRef *edgev1alpha1.DeviceLinkReferenceRelationship `json:"descriptorSetRef"`
+
+	// Specifies the full name of message type, e.g. "example.v1.Status".
+	// +kubebuilder:validation:Required
+	MessageType string `json:"messageType"`
+}
+
+// MQTTMessagePayloadCodec defines the codec of MQTT message payload.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type MQTTMessagePayloadCodec struct {
+	// Specifies the type of codec.
+	// The default value is "JSON".
+	// +kubebuilder:default=JSON
+	// +optional
+	Type MQTTMessagePayloadCodecType `json:"type,omitempty"`
+
+	// Specifies the options of Protocol Buffers codec,
+	// it's required if the type is "Protobuf".
+	// +optional
+	Protobuf *MQTTMessagePayloadProtobufCodec `json:"protobuf,omitempty"`
+}
+
+// MQTTMessagePerPropertyOptions defines the options of publishing per property.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type MQTTMessagePerPropertyOptions struct {
+	// Specifies the deadband of numeric value, which is in form of float string,
+	// the property is published only if its value changes more than the deadband since the last publishing.
+	// The default value is "0", which publishes any changes.
+	// +kubebuilder:validation:Pattern="^[0-9]+(\\.[0-9]+)?$"
+	// +optional
+	Deadband string `json:"deadband,omitempty"`
+}
+
+// MQTTMessageCommandOptions defines the options of receiving commands.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type MQTTMessageCommandOptions struct {
+	// Specifies the topic for receiving commands, which is rendered as same as the topic of message.
+	// If not set, the topic will append "$cmd" to the topic name specified in parent field.
+	// +kubebuilder:validation:Pattern=".*[^/]$"
+	// +optional
+	Topic string `json:"topic,omitempty"`
+
+	// Specifies the topic for publishing the results of commands.
+	// If not set, the topic will append "$reply" to the command topic,
+	// and the response topic of command is preferred in MQTT v5.
+	// +kubebuilder:validation:Pattern=".*[^/]$"
+	// +optional
+	ReplyTopic string `json:"replyTopic,omitempty"`
+}
+
+// MQTTMessageBatchOptions defines the options of publishing in batch.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type MQTTMessageBatchOptions struct {
+	// Specifies the maximum number of messages in a batch.
+	// The default value is "100".
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=100
+	// +optional
+	MaxMessages *int32 `json:"maxMessages,omitempty"`
+
+	// Specifies the maximum size of the payloads in a batch.
+	// The default value is "256Ki".
+	// +optional
+	MaxBytes *resource.Quantity `json:"maxBytes,omitempty"`
+
+	// Specifies the maximum time to wait for packing the messages into a batch.
+	// The default value is "1s".
+	// +optional
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+}
+
+// MQTTMessageRateLimitOptions defines the options of limiting the publishing rate.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type MQTTMessageRateLimitOptions struct {
+	// Specifies the number of publishing per second, which is in form of float string,
+	// the messages exceeded the rate are coalesced, only the newest one of the same topic is kept.
+	// +kubebuilder:validation:Pattern="^[0-9]+(\\.[0-9]+)?$"
+	// +kubebuilder:validation:Required
+	Rate string `json:"rate"`
+
+	// Specifies the maximum number of publishing at the same moment.
+	// The default value is "1".
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	Burst *int32 `json:"burst,omitempty"`
+}
+
 // MQTTMessageOptions defines the options of MQTT message.
 // +kubebuilder:object:generate=true
 // +kubebuilder:object:root=false
@@ -126,4 +284,55 @@ type MQTTMessageOptions struct {
 	// Specifies the will message.
 	// +optional
 	Will *MQTTWillMessage `json:"will,omitempty"`
+
+	// Specifies the properties of publishing message.
+	// This is only valid if the `ProtocolVersion` of client is 5.
+	// +optional
+	Properties *MQTTMessageProperties `json:"properties,omitempty"`
+
+	// Specifies the group name of shared subscription,
+	// the broker distributes the subscribed messages among the clients in the same group,
+	// the topic is subscribed as `$share/{group}/{topic}`.
+	// This is only valid if the `ProtocolVersion` of client is 5.
+	// +kubebuilder:validation:Pattern="^[^/+#]+$"
+	// +optional
+	SharedSubscriptionGroup string `json:"sharedSubscriptionGroup,omitempty"`
+
+	// Specifies the codec of payload,
+	// the payload is encoded during publishing and decoded during subscribing.
+	// The default codec is JSON.
+	// +optional
+	Codec *MQTTMessagePayloadCodec `json:"codec,omitempty"`
+
+	// Specifies the Go text/template for transforming the payload before publishing,
+	// the rendered result is published as it is instead of encoding by the codec.
+	// The template can access the DeviceLink's values via `.Namespace`, `.Name`, `.UID`,
+	// the rendering values of topic via `.Values`, e.g. `{{ .Values.path }}`,
+	// the device status via `.Status` and the publishing time via `.Timestamp`,
+	// and use the `json` function to marshal a value as JSON.
+	// +optional
+	PayloadTemplate string `json:"payloadTemplate,omitempty"`
+
+	// Specifies to publish each property to its own topic instead of the whole status,
+	// the topic is rendered with the `:property` keyword, or appended with the name of property if no keyword.
+	// Only the properties changed since the last publishing are published.
+	// +optional
+	PerProperty *MQTTMessagePerPropertyOptions `json:"perProperty,omitempty"`
+
+	// Specifies to receive commands for writing the properties of device,
+	// the command is an object keyed by the names of properties,
+	// and the results of writing are published to the reply topic.
+	// +optional
+	Command *MQTTMessageCommandOptions `json:"command,omitempty"`
+
+	// Specifies to pack multiple messages of the same topic into one array payload,
+	// the batch is published if it's full or the delay is reached.
+	// This is not supported in per property mode.
+	// +optional
+	Batch *MQTTMessageBatchOptions `json:"batch,omitempty"`
+
+	// Specifies to limit the publishing rate by token bucket,
+	// a batch consumes one token if publishing in batch.
+	// +optional
+	RateLimit *MQTTMessageRateLimitOptions `json:"rateLimit,omitempty"`
 }
@@ -1,5 +1,28 @@
 package api
 
+// MQTTSparkplugBOptions defines the options of Sparkplug B mode,
+// refer to https://www.eclipse.org/tahu/spec/Sparkplug%20Topic%20Namespace%20and%20State%20ManagementV2.2-with%20appendix%20B%20format%20-%20Eclipse.pdf.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type MQTTSparkplugBOptions struct {
+	// Specifies the group ID of edge node.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^[^/+#]+$"
+	GroupID string `json:"groupID"`
+
+	// Specifies the ID of edge node,
+	// the default value is "{namespace}.{name}" of the device.
+	// +kubebuilder:validation:Pattern="^[^/+#]+$"
+	// +optional
+	EdgeNodeID string `json:"edgeNodeID,omitempty"`
+
+	// Specifies the ID of device,
+	// the default value is the name of the device.
+	// +kubebuilder:validation:Pattern="^[^/+#]+$"
+	// +optional
+	DeviceID string `json:"deviceID,omitempty"`
+}
+
 // MQTTOptions defines the desired state of MQTT client.
 // +kubebuilder:object:generate=true
 // +kubebuilder:object:root=false
@@ -8,7 +31,15 @@ type MQTTOptions struct {
 	// +kubebuilder:validation:Required
 	Client MQTTClientOptions `json:"client"`
 
-	// Specifies the message settings.
-	// +kubebuilder:validation:Required
-	Message MQTTMessageOptions `json:"message"`
+	// Specifies the message settings,
+	// it's required if not in Sparkplug B mode.
+	// +optional
+	Message MQTTMessageOptions `json:"message,omitempty"`
+
+	// Specifies to publish in Sparkplug B mode,
+	// the properties of device are published as the metrics of NBIRTH/DBIRTH/DDATA/DDEATH,
+	// and the metrics of DCMD are written back to the properties.
+	// The message settings are ignored in this mode, and the NDEATH is used as the will message.
+	// +optional
+	SparkplugB *MQTTSparkplugBOptions `json:"sparkplugB,omitempty"`
 }
@@ -0,0 +1,32 @@
+package api
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MQTTConnectionStatus defines the observed connection of MQTT client.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=false
+type MQTTConnectionStatus struct {
+	// Reports if the client is connected to the broker.
+	// +optional
+	Connected bool `json:"connected,omitempty"`
+
+	// Reports the server URI of the connected broker,
+	// it's reported in MQTT v5 or in MQTT v3.x with a single server,
+	// and it's always blank in MQTT v3.x with the backup servers.
+	// +optional
+	Server string `json:"server,omitempty"`
+
+	// Reports the last error of connection.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// Reports the number of times the client reconnected after the connection lost.
+	// +optional
+	ReconnectCount int32 `json:"reconnectCount,omitempty"`
+
+	// Reports the last time the connection transitioned from one state to another.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
@@ -49,9 +49,39 @@ func (in *MQTTClientBasicAuth) DeepCopy() *MQTTClientBasicAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTClientBuffer) DeepCopyInto(out *MQTTClientBuffer) {
+	*out = *in
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTClientBuffer.
+func (in *MQTTClientBuffer) DeepCopy() *MQTTClientBuffer {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTClientBuffer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MQTTClientOptions) DeepCopyInto(out *MQTTClientOptions) {
 	*out = *in
+	if in.BackupServers != nil {
+		in, out := &in.BackupServers, &out.BackupServers
+		*out = make([]MQTTClientServer, len(*in))
+		copy(*out, *in)
+	}
 	if in.ProtocolVersion != nil {
 		in, out := &in.ProtocolVersion, &out.ProtocolVersion
 		*out = new(uint)
@@ -77,6 +107,11 @@ func (in *MQTTClientOptions) DeepCopyInto(out *MQTTClientOptions) {
 		*out = new(MQTTClientStore)
 		**out = **in
 	}
+	if in.Buffer != nil {
+		in, out := &in.Buffer, &out.Buffer
+		*out = new(MQTTClientBuffer)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ResumeSubs != nil {
 		in, out := &in.ResumeSubs, &out.ResumeSubs
 		*out = new(bool)
@@ -132,6 +167,16 @@ func (in *MQTTClientOptions) DeepCopyInto(out *MQTTClientOptions) {
 		*out = new(uint)
 		**out = **in
 	}
+	if in.SessionExpiryInterval != nil {
+		in, out := &in.SessionExpiryInterval, &out.SessionExpiryInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TopicAliasMaximum != nil {
+		in, out := &in.TopicAliasMaximum, &out.TopicAliasMaximum
+		*out = new(uint)
+		**out = **in
+	}
 	if in.HTTPHeaders != nil {
 		in, out := &in.HTTPHeaders, &out.HTTPHeaders
 		*out = make(map[string][]string, len(*in))
@@ -189,6 +234,70 @@ func (in *MQTTClientTLS) DeepCopy() *MQTTClientTLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTConnectionStatus) DeepCopyInto(out *MQTTConnectionStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTConnectionStatus.
+func (in *MQTTConnectionStatus) DeepCopy() *MQTTConnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTConnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTMessageBatchOptions) DeepCopyInto(out *MQTTMessageBatchOptions) {
+	*out = *in
+	if in.MaxMessages != nil {
+		in, out := &in.MaxMessages, &out.MaxMessages
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxBytes != nil {
+		in, out := &in.MaxBytes, &out.MaxBytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxDelay != nil {
+		in, out := &in.MaxDelay, &out.MaxDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTMessageBatchOptions.
+func (in *MQTTMessageBatchOptions) DeepCopy() *MQTTMessageBatchOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTMessageBatchOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTMessageCommandOptions) DeepCopyInto(out *MQTTMessageCommandOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTMessageCommandOptions.
+func (in *MQTTMessageCommandOptions) DeepCopy() *MQTTMessageCommandOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTMessageCommandOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MQTTMessageOptions) DeepCopyInto(out *MQTTMessageOptions) {
 	*out = *in
@@ -199,6 +308,36 @@ func (in *MQTTMessageOptions) DeepCopyInto(out *MQTTMessageOptions) {
 		*out = new(MQTTWillMessage)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = new(MQTTMessageProperties)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Codec != nil {
+		in, out := &in.Codec, &out.Codec
+		*out = new(MQTTMessagePayloadCodec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PerProperty != nil {
+		in, out := &in.PerProperty, &out.PerProperty
+		*out = new(MQTTMessagePerPropertyOptions)
+		**out = **in
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = new(MQTTMessageCommandOptions)
+		**out = **in
+	}
+	if in.Batch != nil {
+		in, out := &in.Batch, &out.Batch
+		*out = new(MQTTMessageBatchOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(MQTTMessageRateLimitOptions)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTMessageOptions.
@@ -211,6 +350,26 @@ func (in *MQTTMessageOptions) DeepCopy() *MQTTMessageOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTMessagePayloadCodec) DeepCopyInto(out *MQTTMessagePayloadCodec) {
+	*out = *in
+	if in.Protobuf != nil {
+		in, out := &in.Protobuf, &out.Protobuf
+		*out = new(MQTTMessagePayloadProtobufCodec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTMessagePayloadCodec.
+func (in *MQTTMessagePayloadCodec) DeepCopy() *MQTTMessagePayloadCodec {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTMessagePayloadCodec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MQTTMessagePayloadOptions) DeepCopyInto(out *MQTTMessagePayloadOptions) {
 	*out = *in
@@ -236,6 +395,86 @@ func (in *MQTTMessagePayloadOptions) DeepCopy() *MQTTMessagePayloadOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTMessagePayloadProtobufCodec) DeepCopyInto(out *MQTTMessagePayloadProtobufCodec) {
+	*out = *in
+	if in.DescriptorSetRef != nil {
+		in, out := &in.DescriptorSetRef, &out.DescriptorSetRef
+		*out = new(v1alpha1.DeviceLinkReferenceRelationship)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTMessagePayloadProtobufCodec.
+func (in *MQTTMessagePayloadProtobufCodec) DeepCopy() *MQTTMessagePayloadProtobufCodec {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTMessagePayloadProtobufCodec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTMessagePerPropertyOptions) DeepCopyInto(out *MQTTMessagePerPropertyOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTMessagePerPropertyOptions.
+func (in *MQTTMessagePerPropertyOptions) DeepCopy() *MQTTMessagePerPropertyOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTMessagePerPropertyOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTMessageProperties) DeepCopyInto(out *MQTTMessageProperties) {
+	*out = *in
+	if in.MessageExpiryInterval != nil {
+		in, out := &in.MessageExpiryInterval, &out.MessageExpiryInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.UserProperties != nil {
+		in, out := &in.UserProperties, &out.UserProperties
+		*out = make([]MQTTMessageUserProperty, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTMessageProperties.
+func (in *MQTTMessageProperties) DeepCopy() *MQTTMessageProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTMessageProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTMessageRateLimitOptions) DeepCopyInto(out *MQTTMessageRateLimitOptions) {
+	*out = *in
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTMessageRateLimitOptions.
+func (in *MQTTMessageRateLimitOptions) DeepCopy() *MQTTMessageRateLimitOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTMessageRateLimitOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MQTTMessageTopicOperation) DeepCopyInto(out *MQTTMessageTopicOperation) {
 	*out = *in
@@ -271,11 +510,31 @@ func (in *MQTTMessageTopicOperator) DeepCopy() *MQTTMessageTopicOperator {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTMessageUserProperty) DeepCopyInto(out *MQTTMessageUserProperty) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTMessageUserProperty.
+func (in *MQTTMessageUserProperty) DeepCopy() *MQTTMessageUserProperty {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTMessageUserProperty)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MQTTOptions) DeepCopyInto(out *MQTTOptions) {
 	*out = *in
 	in.Client.DeepCopyInto(&out.Client)
 	in.Message.DeepCopyInto(&out.Message)
+	if in.SparkplugB != nil {
+		in, out := &in.SparkplugB, &out.SparkplugB
+		*out = new(MQTTSparkplugBOptions)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTOptions.
@@ -288,6 +547,21 @@ func (in *MQTTOptions) DeepCopy() *MQTTOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTSparkplugBOptions) DeepCopyInto(out *MQTTSparkplugBOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTSparkplugBOptions.
+func (in *MQTTSparkplugBOptions) DeepCopy() *MQTTSparkplugBOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTSparkplugBOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MQTTWillMessage) DeepCopyInto(out *MQTTWillMessage) {
 	*out = *in
@@ -0,0 +1,298 @@
+package buffer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DropPolicy defines the policy to drop messages when the buffer is full,
+// the expired messages are always dropped from the head of buffer before applying the policy,
+// and a message larger than the whole buffer is always dropped.
+type DropPolicy string
+
+const (
+	// DropOldest drops the messages from the head of buffer until the pushing message fits.
+	DropOldest DropPolicy = "Oldest"
+	// DropNewest drops the pushing message, the buffered messages are kept.
+	DropNewest DropPolicy = "Newest"
+	// DropSample drops the messages at odd indexes until the pushing message fits,
+	// so that the buffer keeps the whole period in lower resolution.
+	DropSample DropPolicy = "Sample"
+)
+
+// DefaultMaxSize is the maximum size of the buffered messages if not specified.
+const DefaultMaxSize int64 = 64 * 1024 * 1024
+
+// Message is the buffered message.
+type Message struct {
+	Topic    string
+	QoS      byte
+	Retained bool
+	// Properties is the MQTT v5 properties of the message, it's nil in MQTT v3.
+	Properties *Properties
+	Payload    []byte
+	// Timestamp is the time of buffering.
+	Timestamp time.Time
+}
+
+// size returns the size of the message in bytes.
+func (m *Message) size() int64 {
+	return int64(len(m.Topic)+len(m.Payload)) + m.Properties.size()
+}
+
+// Properties is the MQTT v5 properties of the buffered message.
+type Properties struct {
+	ContentType     string `json:"contentType,omitempty"`
+	ResponseTopic   string `json:"responseTopic,omitempty"`
+	CorrelationData []byte `json:"correlationData,omitempty"`
+	// MessageExpiry is the lifetime of the message in seconds since buffering.
+	MessageExpiry  *uint32        `json:"messageExpiry,omitempty"`
+	UserProperties []UserProperty `json:"userProperties,omitempty"`
+}
+
+// UserProperty is the user property of the buffered message.
+type UserProperty struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// size returns the size of the properties in bytes.
+func (p *Properties) size() int64 {
+	if p == nil {
+		return 0
+	}
+	var ret = len(p.ContentType) + len(p.ResponseTopic) + len(p.CorrelationData)
+	if p.MessageExpiry != nil {
+		ret += 4
+	}
+	for _, up := range p.UserProperties {
+		ret += len(up.Key) + len(up.Value)
+	}
+	return int64(ret)
+}
+
+// Options defines the options of buffer.
+type Options struct {
+	// MaxSize is the maximum size of the buffered messages in bytes,
+	// the DefaultMaxSize is used if it's not positive, so the buffer never grows without limit.
+	MaxSize int64
+	// MaxAge is the maximum age of the buffered messages, 0 means no limit.
+	MaxAge time.Duration
+	// DropPolicy is the policy to drop messages when the buffer is full.
+	DropPolicy DropPolicy
+	// Directory is the directory for persisting messages, the messages are kept in memory if blank.
+	Directory string
+}
+
+// Buffer is a FIFO queue of messages with quota.
+type Buffer interface {
+	// Push pushes the message into the tail of buffer,
+	// and returns the number of dropped messages.
+	Push(msg Message) (dropped int, err error)
+
+	// Replay publishes the messages from the head of buffer in order,
+	// the published messages are removed, and it stops at the first failed message.
+	// It returns the number of replayed messages and dropped messages.
+	Replay(publish func(msg Message) error) (replayed int, dropped int, err error)
+
+	// Len returns the number of buffered messages.
+	Len() int
+
+	// Size returns the size of buffered messages in bytes.
+	Size() int64
+}
+
+type entry struct {
+	seq       uint64
+	size      int64
+	timestamp time.Time
+}
+
+// storage persists the messages of entries.
+type storage interface {
+	// load returns the entries in order.
+	load() ([]entry, error)
+	put(e entry, msg *Message) error
+	get(e entry) (*Message, error)
+	remove(e entry) error
+}
+
+type buffer struct {
+	sync.Mutex
+
+	opts    Options
+	storage storage
+	entries []entry
+	size    int64
+	seq     uint64
+}
+
+func (b *buffer) Push(msg Message) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+	var dropped = b.expire(msg.Timestamp)
+
+	var size = msg.size()
+	if size > b.opts.MaxSize {
+		return dropped + 1, nil
+	}
+	for b.size+size > b.opts.MaxSize && len(b.entries) != 0 {
+		switch b.opts.DropPolicy {
+		case DropNewest:
+			return dropped + 1, nil
+		case DropSample:
+			if len(b.entries) == 1 {
+				dropped += b.drop(0)
+				continue
+			}
+			// drops the entries at odd indexes
+			for i := len(b.entries) - 1; i > 0; i-- {
+				if i%2 == 1 {
+					dropped += b.drop(i)
+				}
+			}
+		default:
+			dropped += b.drop(0)
+		}
+	}
+
+	b.seq++
+	var e = entry{seq: b.seq, size: size, timestamp: msg.Timestamp}
+	if err := b.storage.put(e, &msg); err != nil {
+		return dropped, errors.Wrap(err, "failed to buffer message")
+	}
+	b.entries = append(b.entries, e)
+	b.size += size
+	return dropped, nil
+}
+
+func (b *buffer) Replay(publish func(msg Message) error) (int, int, error) {
+	var replayed, dropped int
+	for {
+		b.Lock()
+		dropped += b.expire(time.Now())
+		if len(b.entries) == 0 {
+			b.Unlock()
+			return replayed, dropped, nil
+		}
+		var e = b.entries[0]
+		var msg, err = b.storage.get(e)
+		if err != nil {
+			// drops the broken message
+			dropped += b.drop(0)
+			b.Unlock()
+			continue
+		}
+		b.Unlock()
+
+		// doesn't hold the lock during publishing, so that the pushing isn't blocked.
+		if err := publish(*msg); err != nil {
+			return replayed, dropped, err
+		}
+		replayed++
+
+		b.Lock()
+		if len(b.entries) != 0 && b.entries[0].seq == e.seq {
+			b.drop(0)
+		}
+		b.Unlock()
+	}
+}
+
+func (b *buffer) Len() int {
+	b.Lock()
+	defer b.Unlock()
+
+	return len(b.entries)
+}
+
+func (b *buffer) Size() int64 {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.size
+}
+
+// expire drops the messages older than the max age, and returns the number of dropped messages.
+func (b *buffer) expire(now time.Time) int {
+	if b.opts.MaxAge <= 0 {
+		return 0
+	}
+	var dropped int
+	for len(b.entries) != 0 && now.Sub(b.entries[0].timestamp) > b.opts.MaxAge {
+		dropped += b.drop(0)
+	}
+	return dropped
+}
+
+// drop removes the entry at the index, and returns 1.
+func (b *buffer) drop(i int) int {
+	var e = b.entries[i]
+	_ = b.storage.remove(e)
+	b.entries = append(b.entries[:i], b.entries[i+1:]...)
+	b.size -= e.size
+	return 1
+}
+
+// New creates a buffer with the options,
+// the persisted messages are loaded if the directory is specified.
+func New(opts Options) (Buffer, error) {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultMaxSize
+	}
+	var s storage = &memoryStorage{messages: map[uint64]*Message{}}
+	if opts.Directory != "" {
+		var fs, err = newFileStorage(opts.Directory)
+		if err != nil {
+			return nil, err
+		}
+		s = fs
+	}
+
+	var entries, err = s.load()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load buffered messages")
+	}
+	var b = &buffer{
+		opts:    opts,
+		storage: s,
+		entries: entries,
+	}
+	for _, e := range entries {
+		b.size += e.size
+		b.seq = e.seq
+	}
+	return b, nil
+}
+
+type memoryStorage struct {
+	messages map[uint64]*Message
+}
+
+func (s *memoryStorage) load() ([]entry, error) {
+	return nil, nil
+}
+
+func (s *memoryStorage) put(e entry, msg *Message) error {
+	s.messages[e.seq] = msg
+	return nil
+}
+
+func (s *memoryStorage) get(e entry) (*Message, error) {
+	var msg, exist = s.messages[e.seq]
+	if !exist {
+		return nil, errors.Errorf("cannot find message %d", e.seq)
+	}
+	return msg, nil
+}
+
+func (s *memoryStorage) remove(e entry) error {
+	delete(s.messages, e.seq)
+	return nil
+}
@@ -0,0 +1,197 @@
+package buffer
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuffer_Push(t *testing.T) {
+	type given struct {
+		opts     Options
+		payloads []string
+	}
+	type expected struct {
+		dropped  int
+		payloads []string
+	}
+
+	var testCases = []struct {
+		name     string
+		given    given
+		expected expected
+	}{
+		{
+			name: "keeps all messages within default quota",
+			given: given{
+				payloads: []string{"1", "2", "3"},
+			},
+			expected: expected{
+				payloads: []string{"1", "2", "3"},
+			},
+		},
+		{
+			name: "drops the oldest messages",
+			given: given{
+				opts:     Options{MaxSize: 6, DropPolicy: DropOldest},
+				payloads: []string{"1", "2", "3", "4"},
+			},
+			expected: expected{
+				dropped:  1,
+				payloads: []string{"2", "3", "4"},
+			},
+		},
+		{
+			name: "drops the newest messages",
+			given: given{
+				opts:     Options{MaxSize: 6, DropPolicy: DropNewest},
+				payloads: []string{"1", "2", "3", "4"},
+			},
+			expected: expected{
+				dropped:  1,
+				payloads: []string{"1", "2", "3"},
+			},
+		},
+		{
+			name: "drops every other message",
+			given: given{
+				opts:     Options{MaxSize: 8, DropPolicy: DropSample},
+				payloads: []string{"1", "2", "3", "4", "5"},
+			},
+			expected: expected{
+				dropped:  2,
+				payloads: []string{"1", "3", "5"},
+			},
+		},
+		{
+			name: "drops the message larger than quota",
+			given: given{
+				opts:     Options{MaxSize: 4},
+				payloads: []string{"1", "23456"},
+			},
+			expected: expected{
+				dropped:  1,
+				payloads: []string{"1"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var b, err = New(tc.given.opts)
+		assert.NoError(t, err, "case %q", tc.name)
+
+		var dropped int
+		for _, payload := range tc.given.payloads {
+			var d, err = b.Push(Message{Topic: "t", Payload: []byte(payload)})
+			assert.NoError(t, err, "case %q", tc.name)
+			dropped += d
+		}
+		assert.Equal(t, tc.expected.dropped, dropped, "case %q", tc.name)
+		assert.Equal(t, tc.expected.payloads, replayAll(t, b), "case %q", tc.name)
+	}
+}
+
+func TestBuffer_Replay(t *testing.T) {
+	var b, err = New(Options{MaxAge: time.Minute})
+	assert.NoError(t, err)
+
+	// the first message is expired when pushing the second message
+	var pushDropped int
+	for i, payload := range []string{"1", "2", "3", "4"} {
+		var timestamp = time.Now()
+		if i == 0 {
+			timestamp = timestamp.Add(-2 * time.Minute)
+		}
+		var d, err = b.Push(Message{Topic: "t", Payload: []byte(payload), Timestamp: timestamp})
+		assert.NoError(t, err)
+		pushDropped += d
+	}
+	assert.Equal(t, 1, pushDropped)
+
+	// stops at the first failed message
+	var published []string
+	replayed, dropped, err := b.Replay(func(msg Message) error {
+		if string(msg.Payload) == "3" {
+			return errors.New("unreachable")
+		}
+		published = append(published, string(msg.Payload))
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, replayed)
+	assert.Equal(t, 0, dropped)
+	assert.Equal(t, []string{"2"}, published)
+	assert.Equal(t, 2, b.Len())
+	assert.Equal(t, int64(4), b.Size())
+
+	// replays the remaining messages in order
+	assert.Equal(t, []string{"3", "4"}, replayAll(t, b))
+	assert.Equal(t, 0, b.Len())
+	assert.Equal(t, int64(0), b.Size())
+}
+
+func TestBuffer_File(t *testing.T) {
+	var directory, err = ioutil.TempDir("", "buffer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	var opts = Options{MaxSize: 1024, Directory: directory}
+	b, err := New(opts)
+	assert.NoError(t, err)
+	var expiry = uint32(60)
+	var msgs = []Message{
+		{
+			Topic:    "a",
+			QoS:      1,
+			Retained: true,
+			Properties: &Properties{
+				ContentType:     "application/json",
+				ResponseTopic:   "a/reply",
+				CorrelationData: []byte{0x01, 0x02},
+				MessageExpiry:   &expiry,
+				UserProperties:  []UserProperty{{Key: "k", Value: "v"}},
+			},
+			Payload: []byte(`{"v":1}`),
+		},
+		{Topic: "b", QoS: 0, Payload: []byte("2")},
+	}
+	for _, msg := range msgs {
+		_, err = b.Push(msg)
+		assert.NoError(t, err)
+	}
+
+	// reloads the persisted messages
+	reloaded, err := New(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, reloaded.Len())
+	assert.Equal(t, b.Size(), reloaded.Size())
+
+	var actual []Message
+	_, _, err = reloaded.Replay(func(msg Message) error {
+		msg.Timestamp = time.Time{}
+		actual = append(actual, msg)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, msgs, actual)
+
+	files, err := ioutil.ReadDir(directory)
+	assert.NoError(t, err)
+	assert.Len(t, files, 0)
+}
+
+func replayAll(t *testing.T, b Buffer) []string {
+	var payloads []string
+	var _, _, err = b.Replay(func(msg Message) error {
+		payloads = append(payloads, string(msg.Payload))
+		return nil
+	})
+	assert.NoError(t, err)
+	return payloads
+}
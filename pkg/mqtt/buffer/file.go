@@ -0,0 +1,176 @@
+package buffer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	fileSuffix = ".msg"
+	// the header contains timestamp(8), qos(1), retained(1), topic length(2) and properties length(4),
+	// the properties are encoded in JSON.
+	fileHeaderSize = 16
+)
+
+// fileStorage persists each message in a file named by the sequence number.
+type fileStorage struct {
+	directory string
+}
+
+func (s *fileStorage) load() ([]entry, error) {
+	var files, err = ioutil.ReadDir(s.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries = make([]entry, 0, len(files))
+	for _, f := range files {
+		var name = f.Name()
+		if f.IsDir() || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		var seq, err = strconv.ParseUint(strings.TrimSuffix(name, fileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		var e = entry{seq: seq}
+		msg, err := s.get(e)
+		if err != nil {
+			// removes the broken message
+			_ = s.remove(e)
+			continue
+		}
+		e.size = msg.size()
+		e.timestamp = msg.Timestamp
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].seq < entries[j].seq
+	})
+	return entries, nil
+}
+
+func (s *fileStorage) put(e entry, msg *Message) error {
+	if len(msg.Topic) > 0xFFFF {
+		return errors.New("topic is too long")
+	}
+	var properties []byte
+	if msg.Properties != nil {
+		var err error
+		properties, err = json.Marshal(msg.Properties)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal properties")
+		}
+	}
+
+	var data = make([]byte, fileHeaderSize, fileHeaderSize+len(msg.Topic)+len(properties)+len(msg.Payload))
+	binary.BigEndian.PutUint64(data[0:8], uint64(msg.Timestamp.UnixNano()))
+	data[8] = msg.QoS
+	if msg.Retained {
+		data[9] = 1
+	}
+	binary.BigEndian.PutUint16(data[10:12], uint16(len(msg.Topic)))
+	binary.BigEndian.PutUint32(data[12:16], uint32(len(properties)))
+	data = append(data, msg.Topic...)
+	data = append(data, properties...)
+	data = append(data, msg.Payload...)
+
+	// writes to a temporary file and then renames it, so that a broken message is never loaded,
+	// both the file and the directory are synced, so that the message survives a power loss.
+	var path = s.path(e)
+	var tmpPath = path + ".tmp"
+	if err := writeFileSync(tmpPath, data); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	return syncDir(s.directory)
+}
+
+func (s *fileStorage) get(e entry) (*Message, error) {
+	var data, err = ioutil.ReadFile(s.path(e))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < fileHeaderSize {
+		return nil, errors.New("unexpected end of header")
+	}
+
+	var topicLen = int(binary.BigEndian.Uint16(data[10:12]))
+	var propertiesLen = int(binary.BigEndian.Uint32(data[12:16]))
+	if len(data)-fileHeaderSize < topicLen+propertiesLen {
+		return nil, errors.New("unexpected end of message")
+	}
+	var body = data[fileHeaderSize:]
+	var msg = &Message{
+		Timestamp: time.Unix(0, int64(binary.BigEndian.Uint64(data[0:8]))),
+		QoS:       data[8],
+		Retained:  data[9] == 1,
+		Topic:     string(body[:topicLen]),
+		Payload:   body[topicLen+propertiesLen:],
+	}
+	if propertiesLen != 0 {
+		if err := json.Unmarshal(body[topicLen:topicLen+propertiesLen], &msg.Properties); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal properties")
+		}
+	}
+	return msg, nil
+}
+
+func (s *fileStorage) remove(e entry) error {
+	var err = os.Remove(s.path(e))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *fileStorage) path(e entry) string {
+	return filepath.Join(s.directory, fmt.Sprintf("%020d%s", e.seq, fileSuffix))
+}
+
+// writeFileSync writes the data to the file and flushes it to the disk.
+func writeFileSync(path string, data []byte) error {
+	var f, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncDir flushes the entries of directory to the disk.
+func syncDir(directory string) error {
+	var d, err = os.Open(directory)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func newFileStorage(directory string) (*fileStorage, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, errors.Wrapf(err, "failed to create directory %s", directory)
+	}
+	return &fileStorage{directory: directory}, nil
+}
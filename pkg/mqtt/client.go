@@ -11,7 +11,9 @@
 
 	adaptorapi "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
 	"github.com/rancher/octopus/pkg/mqtt/api"
-	"github.com/rancher/octopus/pkg/util/converter"
+	"github.com/rancher/octopus/pkg/mqtt/buffer"
+	"github.com/rancher/octopus/pkg/mqtt/codec"
+	v5 "github.com/rancher/octopus/pkg/mqtt/v5"
 )
 
 // PublishMessage aggregates the parameters for publishing.
@@ -27,8 +29,16 @@ type PublishMessage struct {
 	// otherwise uses the global value.
 	RetainedPointer *bool
 
-	// Specifies the payload.
+	// Specifies the payload,
+	// the bytes payload is published as it is, otherwise the payload is encoded by the codec.
 	Payload interface{}
+
+	// Specifies the properties for publishing, which are merged into the global value,
+	// only works in MQTT v5.
+	Properties *api.MQTTMessageProperties
+
+	// skipTemplate publishes the payload without rendering by the payload template.
+	skipTemplate bool
 }
 
 // SubscribeMessage aggregates the result from subscribing.
@@ -41,6 +51,35 @@ type SubscribeMessage struct {
 
 	// Reports the payload content.
 	Payload []byte
+
+	// Reports the properties of message, only works in MQTT v5.
+	Properties *api.MQTTMessageProperties
+
+	codec codec.Codec
+	reply func(payload interface{}) error
+}
+
+// Decode decodes the payload into the given value with the codec.
+func (m SubscribeMessage) Decode(v interface{}) error {
+	if m.codec == nil {
+		return codec.JSON.Decode(m.Payload, v)
+	}
+	return m.codec.Decode(m.Payload, v)
+}
+
+// JSONPayload returns the payload as JSON,
+// the payload is converted if the codec is not JSON.
+func (m SubscribeMessage) JSONPayload() ([]byte, error) {
+	return codec.ToJSON(m.codec, m.Payload)
+}
+
+// Reply publishes the payload as the reply of message,
+// it does nothing if the message cannot be replied, e.g. the message is not a command.
+func (m SubscribeMessage) Reply(payload interface{}) error {
+	if m.reply == nil {
+		return nil
+	}
+	return m.reply(payload)
 }
 
 // SubscribeHandler is a callback type which can be set to be
@@ -69,6 +108,20 @@ func (i SubscribeTopicIndex) Index(topicName string, topic *SubscribeTopic) {
 	i[topicName] = *topic
 }
 
+// Match returns the topic which name is same as the given topic name,
+// or which name is a wildcard filter matching the given topic name.
+func (i SubscribeTopicIndex) Match(topicName string) (SubscribeTopic, bool) {
+	if topic, exist := i[topicName]; exist {
+		return topic, true
+	}
+	for filter, topic := range i {
+		if v5.MatchTopic(filter, topicName) {
+			return topic, true
+		}
+	}
+	return SubscribeTopic{}, false
+}
+
 // Difference returns a set of topic names that are not in o.
 func (i SubscribeTopicIndex) DifferenceIndexes(o SubscribeTopicIndex) []string {
 	var result = sets.String{}
@@ -87,7 +140,8 @@ type Client interface {
 	// Disconnect will end the connection with the server.
 	Disconnect()
 
-	// RawClient returns the original MQTT client.
+	// RawClient returns the original MQTT client,
+	// it returns an adapted client in MQTT v5.
 	RawClient() mqtt.Client
 
 	// Publish publishes the message to corresponding topic.
@@ -96,6 +150,9 @@ type Client interface {
 	// Subscribe subscribes the corresponding topic and handle in the same handler,
 	// and deals with the unsubscribe actions automatically.
 	Subscribe(topics []SubscribeTopic, handler SubscribeHandler) error
+
+	// Status returns the connection status of client.
+	Status() api.MQTTConnectionStatus
 }
 
 type client struct {
@@ -105,6 +162,10 @@ type client struct {
 	topic             SegmentTopic
 	qos               byte
 	retained          bool
+	codec             codec.Codec
+	template          *payloadTemplate
+	outbox            *outbox
+	tracker           *connectionTracker
 
 	subscribeTopicIndexer SubscribeTopicIndex
 }
@@ -122,7 +183,9 @@ func (c *client) Connect() error {
 	var token = c.raw.Connect()
 	_ = token.Wait()
 	// NB(thxCode) we don't need to call token.WaitTimeout() in here as the connection timeout has been injected.
-	return token.Error()
+	var err = token.Error()
+	c.tracker.onConnectFailed(err)
+	return err
 }
 
 func (c *client) Disconnect() {
@@ -131,12 +194,19 @@ func (c *client) Disconnect() {
 		disconnectQuiesce = 5 * time.Second
 	}
 	c.raw.Disconnect(uint(disconnectQuiesce.Milliseconds()))
+	if c.outbox != nil {
+		c.outbox.Close()
+	}
 }
 
 func (c *client) RawClient() mqtt.Client {
 	return c.raw
 }
 
+func (c *client) Status() api.MQTTConnectionStatus {
+	return c.tracker.Status()
+}
+
 func (c *client) Subscribe(topics []SubscribeTopic, handler SubscribeHandler) error {
 	if len(topics) == 0 {
 		return nil
@@ -165,7 +235,7 @@ func (c *client) Subscribe(topics []SubscribeTopic, handler SubscribeHandler) er
 			return
 		}
 		var topicName = msg.Topic()
-		var topic, exist = topicIndexer[topicName]
+		var topic, exist = topicIndexer.Match(topicName)
 		if !exist {
 			return
 		}
@@ -176,6 +246,7 @@ func (c *client) Subscribe(topics []SubscribeTopic, handler SubscribeHandler) er
 			Index:   topic.Index,
 			Topic:   topicName,
 			Payload: msg.Payload(),
+			codec:   c.codec,
 		})
 	}
 	var topicFilters = make(map[string]byte, len(topicIndexer))
@@ -200,18 +271,13 @@ func (c *client) Publish(message PublishMessage) error {
 	if message.Payload == nil {
 		return nil
 	}
-	var payload []byte
-	switch p := message.Payload.(type) {
-	case []byte:
-		payload = p
-	case bytes.Buffer:
-		payload = p.Bytes()
-	default:
-		var encodedPayload, err = converter.MarshalJSON(message.Payload)
-		if err != nil {
-			return err
-		}
-		payload = encodedPayload
+	var rendered, err = renderPayload(c.template, message)
+	if err != nil {
+		return err
+	}
+	payload, _, err := encodePayload(c.codec, rendered)
+	if err != nil {
+		return err
 	}
 	var qos = c.qos
 	if message.QoSPointer != nil {
@@ -222,12 +288,46 @@ func (c *client) Publish(message PublishMessage) error {
 		retained = *message.RetainedPointer
 	}
 	var topicName = c.topic.RenderForPublish(message.Render)
-	log.Println("Publish  ", "topic: ", topicName, ", qos: ", qos, ", retained: ", retained)
+	var msg = buffer.Message{
+		Topic:    topicName,
+		QoS:      qos,
+		Retained: retained,
+		Payload:  payload,
+	}
+	if c.outbox != nil {
+		return c.outbox.Publish(msg, func() error {
+			return c.publish(msg)
+		})
+	}
+	return c.publish(msg)
+}
+
+func (c *client) publish(msg buffer.Message) error {
+	log.Println("Publish  ", "topic: ", msg.Topic, ", qos: ", msg.QoS, ", retained: ", msg.Retained)
 
-	var token = c.raw.Publish(topicName, qos, retained, payload)
+	var token = c.raw.Publish(msg.Topic, msg.QoS, msg.Retained, msg.Payload)
 	return c.wait(token)
 }
 
+// encodePayload encodes the payload with the codec,
+// the bytes payload is returned as it is and the encoded flag is false.
+func encodePayload(c codec.Codec, payload interface{}) (data []byte, encoded bool, err error) {
+	switch p := payload.(type) {
+	case []byte:
+		return p, false, nil
+	case bytes.Buffer:
+		return p.Bytes(), false, nil
+	}
+	if c == nil {
+		c = codec.JSON
+	}
+	data, err = c.Encode(payload)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to encode payload")
+	}
+	return data, true, nil
+}
+
 // NewClient creates the MQTT client with expected options.
 func NewClient(spec api.MQTTOptions, ref corev1.ObjectReference, handler adaptorapi.ReferencesHandler) (Client, error) {
 	var clientBuilder = NewClientBuilder(spec, ref)
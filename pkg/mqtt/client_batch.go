@@ -0,0 +1,243 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/rancher/octopus/pkg/metrics"
+	"github.com/rancher/octopus/pkg/mqtt/api"
+)
+
+// batch is the pending messages of the same topic.
+type batch struct {
+	message  PublishMessage
+	payloads []interface{}
+	size     int
+	deadline time.Time
+}
+
+// batchClient is the batching and rate limiting implementation of Client,
+// it packs the messages of the same topic into one array payload if batching,
+// and coalesces the messages exceeded the rate if rate limiting.
+type batchClient struct {
+	sync.Mutex
+
+	raw  Client
+	name string
+	// maxMessages is 0 if not batching.
+	maxMessages int
+	maxBytes    int
+	maxDelay    time.Duration
+	// limiter is nil if not rate limiting.
+	limiter *tokenBucket
+	batches map[string]*batch
+	// keys keeps the order of the pending batches.
+	keys []string
+	stop chan struct{}
+}
+
+func (c *batchClient) Connect() error {
+	return c.raw.Connect()
+}
+
+// Disconnect publishes the pending messages regardless of the rate limit, and then disconnects.
+func (c *batchClient) Disconnect() {
+	c.Lock()
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+	var pending = c.popAll(func(*batch) bool { return true })
+	c.Unlock()
+
+	if err := c.publish(pending); err != nil {
+		log.Println("Failed to publish pending messages before disconnecting  ", "error: ", err)
+	}
+	c.raw.Disconnect()
+}
+
+func (c *batchClient) RawClient() mqtt.Client {
+	return c.raw.RawClient()
+}
+
+func (c *batchClient) Status() api.MQTTConnectionStatus {
+	return c.raw.Status()
+}
+
+func (c *batchClient) Subscribe(topics []SubscribeTopic, handler SubscribeHandler) error {
+	return c.raw.Subscribe(topics, handler)
+}
+
+// Publish queues the message, the message is published asynchronously
+// if it's waiting for batching or rate limited.
+func (c *batchClient) Publish(message PublishMessage) error {
+	if message.Payload == nil {
+		return nil
+	}
+	var now = time.Now()
+
+	c.Lock()
+	var key = batchKey(message.Render)
+	var b, exist = c.batches[key]
+	if !exist {
+		b = &batch{message: message, deadline: now.Add(c.maxDelay)}
+		c.batches[key] = b
+		c.keys = append(c.keys, key)
+	}
+	var size = payloadSize(message.Payload)
+	if c.maxMessages == 0 {
+		// keeps the newest message only if not batching.
+		if len(b.payloads) != 0 {
+			metrics.GetMQTTMetricsRecorder().IncreasePublishCoalesced(c.name, 1)
+		}
+		b.message = message
+		b.payloads = []interface{}{message.Payload}
+		b.size = size
+	} else {
+		// the batch can only be full while rate limited,
+		// drops the oldest message to leave room for the newest one.
+		if len(b.payloads) >= c.maxMessages {
+			b.size -= payloadSize(b.payloads[0])
+			b.payloads = b.payloads[1:]
+			metrics.GetMQTTMetricsRecorder().IncreasePublishDropped(c.name, 1)
+		}
+		b.payloads = append(b.payloads, message.Payload)
+		b.size += size
+	}
+	var pending = c.popAll(func(b *batch) bool {
+		return c.ready(b, now)
+	})
+	c.Unlock()
+
+	return c.publish(pending)
+}
+
+// ready returns true if the batch can be published.
+func (c *batchClient) ready(b *batch, now time.Time) bool {
+	if c.maxMessages != 0 {
+		var full = len(b.payloads) >= c.maxMessages || (c.maxBytes > 0 && b.size >= c.maxBytes)
+		if !full && now.Before(b.deadline) {
+			return false
+		}
+	}
+	return c.limiter == nil || c.limiter.take(now)
+}
+
+// popAll removes the batches which match the predicate, and returns them in order.
+func (c *batchClient) popAll(predicate func(b *batch) bool) []*batch {
+	var ret []*batch
+	var keys = c.keys[:0]
+	for _, key := range c.keys {
+		var b = c.batches[key]
+		if predicate(b) {
+			ret = append(ret, b)
+			delete(c.batches, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	c.keys = keys
+	return ret
+}
+
+// publish publishes the batches, the payloads of batch are packed into an array if batching,
+// it continues with the rest batches if failed, and returns the errors of all failed batches.
+func (c *batchClient) publish(batches []*batch) error {
+	var errs []error
+	for _, b := range batches {
+		var message = b.message
+		if c.maxMessages != 0 {
+			message.Payload = b.payloads
+			metrics.GetMQTTMetricsRecorder().IncreasePublishBatched(c.name, len(b.payloads))
+		}
+		if err := c.raw.Publish(message); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to publish %d message(s) of batch %q", len(b.payloads), batchKey(message.Render)))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// run publishes the pending batches when they are ready.
+func (c *batchClient) run(interval time.Duration, stop <-chan struct{}) {
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			c.Lock()
+			var pending = c.popAll(func(b *batch) bool {
+				return c.ready(b, now)
+			})
+			c.Unlock()
+
+			if err := c.publish(pending); err != nil {
+				log.Println("Failed to publish pending messages  ", "error: ", err)
+			}
+		}
+	}
+}
+
+// batchKey returns the key of the rendering values, the messages with the same key are published to the same topic.
+func batchKey(render map[string]string) string {
+	var pairs = make([]string, 0, len(render))
+	for k, v := range render {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// payloadSize returns the estimated size of payload in JSON.
+func payloadSize(payload interface{}) int {
+	switch p := payload.(type) {
+	case []byte:
+		return len(p)
+	}
+	var data, err = json.Marshal(payload)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// tokenBucket is a token bucket rate limiter.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// take returns true if a token is taken.
+func (b *tokenBucket) take(now time.Time) bool {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
@@ -0,0 +1,151 @@
+package mqtt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchClient_Publish(t *testing.T) {
+	type given struct {
+		maxMessages int
+		maxDelay    time.Duration
+		limiter     *tokenBucket
+		messages    []PublishMessage
+	}
+	type expected struct {
+		published    []PublishMessage
+		disconnected []PublishMessage
+	}
+
+	var message = func(property string, payload interface{}) PublishMessage {
+		return PublishMessage{Render: map[string]string{"property": property}, Payload: payload}
+	}
+
+	var testCases = []struct {
+		name     string
+		given    given
+		expected expected
+	}{
+		{
+			name: "packs the messages of the same topic",
+			given: given{
+				maxMessages: 3,
+				maxDelay:    time.Hour,
+				messages: []PublishMessage{
+					message("a", 1), message("b", 1), message("a", 2), message("a", 3), message("b", 2), message("a", 4),
+				},
+			},
+			expected: expected{
+				published: []PublishMessage{
+					message("a", []interface{}{1, 2, 3}),
+				},
+				disconnected: []PublishMessage{
+					message("b", []interface{}{1, 2}),
+					message("a", []interface{}{4}),
+				},
+			},
+		},
+		{
+			name: "coalesces the messages while rate limited",
+			given: given{
+				limiter: newTokenBucket(0.001, 1),
+				messages: []PublishMessage{
+					message("a", 1), message("a", 2), message("b", 1), message("a", 3),
+				},
+			},
+			expected: expected{
+				published: []PublishMessage{
+					message("a", 1),
+				},
+				disconnected: []PublishMessage{
+					message("a", 3),
+					message("b", 1),
+				},
+			},
+		},
+		{
+			name: "drops the oldest messages of full batch while rate limited",
+			given: given{
+				maxMessages: 2,
+				maxDelay:    time.Hour,
+				limiter:     newTokenBucket(0.001, 1),
+				messages: []PublishMessage{
+					message("a", 1), message("a", 2), message("a", 3), message("a", 4), message("a", 5),
+				},
+			},
+			expected: expected{
+				published: []PublishMessage{
+					message("a", []interface{}{1, 2}),
+				},
+				disconnected: []PublishMessage{
+					message("a", []interface{}{4, 5}),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var raw = &fakeRawClient{}
+		var cli = &batchClient{
+			raw:         raw,
+			name:        "default/test",
+			maxMessages: tc.given.maxMessages,
+			maxDelay:    tc.given.maxDelay,
+			limiter:     tc.given.limiter,
+			batches:     map[string]*batch{},
+		}
+		for _, msg := range tc.given.messages {
+			assert.NoError(t, cli.Publish(msg), "case %q", tc.name)
+		}
+		assert.Equal(t, tc.expected.published, raw.published, "case %q", tc.name)
+
+		raw.published = nil
+		cli.Disconnect()
+		assert.Equal(t, tc.expected.disconnected, raw.published, "case %q", tc.name)
+	}
+}
+
+func TestBatchClient_PublishError(t *testing.T) {
+	var raw = &fakeRawClient{publishErr: errors.New("unreachable")}
+	var cli = &batchClient{
+		raw:         raw,
+		name:        "default/test",
+		maxMessages: 2,
+		maxDelay:    time.Hour,
+		batches:     map[string]*batch{},
+	}
+	var err = cli.publish([]*batch{
+		{message: PublishMessage{Render: map[string]string{"property": "a"}}, payloads: []interface{}{1}},
+		{message: PublishMessage{Render: map[string]string{"property": "b"}}, payloads: []interface{}{1, 2}},
+	})
+
+	// publishes the rest batches after failed, and returns the errors of all failed batches
+	assert.Len(t, raw.published, 2)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `failed to publish 1 message(s) of batch "property=a": unreachable`)
+		assert.Contains(t, err.Error(), `failed to publish 2 message(s) of batch "property=b": unreachable`)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	var bucket = newTokenBucket(2, 2)
+	var now = time.Now()
+
+	assert.True(t, bucket.take(now))
+	assert.True(t, bucket.take(now))
+	assert.False(t, bucket.take(now))
+
+	// refills a token after half a second
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, bucket.take(now))
+	assert.False(t, bucket.take(now))
+
+	// doesn't exceed the burst
+	now = now.Add(time.Minute)
+	assert.True(t, bucket.take(now))
+	assert.True(t, bucket.take(now))
+	assert.False(t, bucket.take(now))
+}
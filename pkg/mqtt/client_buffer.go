@@ -0,0 +1,85 @@
+package mqtt
+
+import (
+	"sync/atomic"
+
+	"github.com/rancher/octopus/pkg/metrics"
+	"github.com/rancher/octopus/pkg/mqtt/buffer"
+)
+
+// outbox buffers the publishing messages while the broker is unreachable,
+// and replays them in order after reconnected.
+type outbox struct {
+	name   string
+	buffer buffer.Buffer
+	// publish publishes the buffered message during replaying.
+	publish   func(msg buffer.Message) error
+	connected func() bool
+	replaying int32
+	requested int32
+}
+
+// Publish publishes the message via the given function if the broker is reachable and nothing is buffered,
+// otherwise buffers the message to keep the order.
+func (o *outbox) Publish(msg buffer.Message, publish func() error) error {
+	if o.connected() && o.buffer.Len() == 0 {
+		var err = publish()
+		if err == nil {
+			return nil
+		}
+		log.Println("Failed to publish, buffering  ", "topic: ", msg.Topic, ", error: ", err)
+	}
+
+	var dropped, err = o.buffer.Push(msg)
+	o.record(0, dropped)
+	if err != nil {
+		return err
+	}
+	if o.connected() {
+		go o.Replay()
+	}
+	return nil
+}
+
+// Replay publishes the buffered messages in order, only one replaying works at the same time.
+// It's requested on every (re)connection, and the working replaying runs again if requested meanwhile,
+// e.g. the replaying of the lost connection is still waiting for the failure while reconnected.
+func (o *outbox) Replay() {
+	atomic.StoreInt32(&o.requested, 1)
+	for atomic.CompareAndSwapInt32(&o.replaying, 0, 1) {
+		atomic.StoreInt32(&o.requested, 0)
+		var replayed, dropped, err = o.buffer.Replay(o.publish)
+		atomic.StoreInt32(&o.replaying, 0)
+
+		o.record(replayed, dropped)
+		if err != nil {
+			log.Println("Failed to replay  ", "remaining: ", o.buffer.Len(), ", error: ", err)
+		} else if replayed != 0 {
+			log.Println("Replay  ", "messages: ", replayed)
+		}
+		if !o.connected() {
+			return
+		}
+		// replays again if requested during the last replaying,
+		// or the messages are pushed after the last replaying finished.
+		if atomic.LoadInt32(&o.requested) == 0 && (err != nil || o.buffer.Len() == 0) {
+			return
+		}
+	}
+}
+
+// Close cleans the metrics of the outbox.
+func (o *outbox) Close() {
+	metrics.GetMQTTMetricsRecorder().DeleteBufferDepth(o.name)
+}
+
+func (o *outbox) record(replayed, dropped int) {
+	var recorder = metrics.GetMQTTMetricsRecorder()
+	recorder.SetBufferDepth(o.name, o.buffer.Len(), o.buffer.Size())
+	if replayed != 0 {
+		recorder.IncreaseBufferReplayed(o.name, replayed)
+	}
+	if dropped != 0 {
+		recorder.IncreaseBufferDropped(o.name, dropped)
+	}
+}
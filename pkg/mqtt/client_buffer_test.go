@@ -0,0 +1,154 @@
+package mqtt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rancher/octopus/pkg/mqtt/buffer"
+)
+
+func TestOutbox(t *testing.T) {
+	var buf, err = buffer.New(buffer.Options{})
+	assert.NoError(t, err)
+
+	var connected bool
+	var published []string
+	var ob = &outbox{
+		name:   "default/test",
+		buffer: buf,
+		publish: func(msg buffer.Message) error {
+			if !connected {
+				return errors.New("unreachable")
+			}
+			published = append(published, string(msg.Payload))
+			return nil
+		},
+		connected: func() bool {
+			return connected
+		},
+	}
+	var publish = func(payload string) error {
+		var msg = buffer.Message{Topic: "t", Payload: []byte(payload)}
+		return ob.Publish(msg, func() error {
+			return ob.publish(msg)
+		})
+	}
+
+	// buffers the messages while disconnected
+	assert.NoError(t, publish("1"))
+	assert.NoError(t, publish("2"))
+	assert.Nil(t, published)
+	assert.Equal(t, 2, buf.Len())
+
+	// replays the buffered messages in order after connected
+	connected = true
+	ob.Replay()
+	assert.Equal(t, []string{"1", "2"}, published)
+	assert.Equal(t, 0, buf.Len())
+
+	// publishes directly if nothing is buffered
+	assert.NoError(t, publish("3"))
+	assert.Equal(t, []string{"1", "2", "3"}, published)
+	ob.Close()
+}
+
+func TestOutbox_ReplayOnReconnect(t *testing.T) {
+	var buf, err = buffer.New(buffer.Options{})
+	assert.NoError(t, err)
+	_, _ = buf.Push(buffer.Message{Topic: "t", Payload: []byte("1")})
+	_, _ = buf.Push(buffer.Message{Topic: "t", Payload: []byte("2")})
+
+	var connected = true
+	var published []string
+	var ob *outbox
+	ob = &outbox{
+		name:   "default/test",
+		buffer: buf,
+		publish: func(msg buffer.Message) error {
+			if published == nil {
+				// the connection is lost during replaying,
+				// and the reconnection requests a replaying before the failure is returned.
+				published = []string{}
+				ob.Replay()
+				return errors.New("connection lost")
+			}
+			published = append(published, string(msg.Payload))
+			return nil
+		},
+		connected: func() bool {
+			return connected
+		},
+	}
+	defer ob.Close()
+
+	// the buffered messages are replayed by the working replaying after reconnected
+	ob.Replay()
+	assert.Equal(t, []string{"1", "2"}, published)
+	assert.Equal(t, 0, buf.Len())
+}
+
+func Test_fromBufferedProperties(t *testing.T) {
+	var expiry = uint32(60)
+	var remaining = uint32(30)
+	var properties = &paho.PublishProperties{
+		ContentType:     "application/json",
+		ResponseTopic:   "fan/reply",
+		CorrelationData: []byte("1"),
+		MessageExpiry:   &expiry,
+		User:            []paho.UserProperty{{Key: "site", Value: "s1"}},
+	}
+
+	type given struct {
+		properties *paho.PublishProperties
+		buffered   time.Duration
+	}
+	type expected struct {
+		properties *paho.PublishProperties
+		expired    bool
+	}
+	var testCases = []struct {
+		name     string
+		given    given
+		expected expected
+	}{
+		{
+			name: "without properties",
+		},
+		{
+			name: "reduces the message expiry by the buffered time",
+			given: given{
+				properties: properties,
+				buffered:   30 * time.Second,
+			},
+			expected: expected{
+				properties: &paho.PublishProperties{
+					ContentType:     "application/json",
+					ResponseTopic:   "fan/reply",
+					CorrelationData: []byte("1"),
+					MessageExpiry:   &remaining,
+					User:            []paho.UserProperty{{Key: "site", Value: "s1"}},
+				},
+			},
+		},
+		{
+			name: "expires",
+			given: given{
+				properties: properties,
+				buffered:   time.Minute,
+			},
+			expected: expected{
+				expired: true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual expected
+		actual.properties, actual.expired = fromBufferedProperties(toBufferedProperties(tc.given.properties), tc.given.buffered)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
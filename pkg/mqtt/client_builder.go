@@ -6,13 +6,21 @@
 	"fmt"
 	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/eclipse/paho.golang/paho"
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 
 	adaptorapi "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
 	"github.com/rancher/octopus/pkg/mqtt/api"
+	"github.com/rancher/octopus/pkg/mqtt/buffer"
+	"github.com/rancher/octopus/pkg/mqtt/codec"
+	"github.com/rancher/octopus/pkg/mqtt/sparkplug"
+	v5 "github.com/rancher/octopus/pkg/mqtt/v5"
 	"github.com/rancher/octopus/pkg/util/converter"
 	"github.com/rancher/octopus/pkg/util/uuid"
 )
@@ -23,7 +31,20 @@ type ClientBuilder struct {
 	ref    corev1.ObjectReference
 	spec   *api.MQTTOptions
 	status *mqtt.ClientOptions
-	err    error
+	codec  codec.Codec
+	// template is not nil if transforming the payload before publishing.
+	template *payloadTemplate
+	// sparkplug is not nil in Sparkplug B mode.
+	sparkplug *sparkplugSession
+	// deadband is the deadband of numeric value in per property mode.
+	deadband float64
+	// rate is the number of publishing per second if rate limiting.
+	rate float64
+	// buffer is not nil if buffering the publishing messages while the broker is unreachable.
+	buffer *buffer.Options
+	// statusChanged is invoked after the connection status changed.
+	statusChanged func()
+	err           error
 }
 
 // Render renders the MQTT client options with expected options.
@@ -33,6 +54,37 @@ func (b *ClientBuilder) Render(handler adaptorapi.ReferencesHandler) {
 	var messageSpec = b.spec.Message
 	var status = b.status
 
+	// processes Sparkplug B mode, the message settings are ignored in this mode
+	if sparkplugSpec := b.spec.SparkplugB; sparkplugSpec != nil {
+		var session = &sparkplugSession{
+			uid:        ref.UID,
+			groupID:    sparkplugSpec.GroupID,
+			edgeNodeID: sparkplugSpec.EdgeNodeID,
+			deviceID:   sparkplugSpec.DeviceID,
+		}
+		if session.edgeNodeID == "" {
+			session.edgeNodeID = fmt.Sprintf("%s.%s", ref.Namespace, ref.Name)
+		}
+		if session.deviceID == "" {
+			session.deviceID = ref.Name
+		}
+		if !sparkplug.ValidID(session.groupID) || !sparkplug.ValidID(session.edgeNodeID) || !sparkplug.ValidID(session.deviceID) {
+			b.err = errors.Errorf("illegal Sparkplug B options as blank IDs or IDs containing '/', '+' or '#'")
+			return
+		}
+		session.renew()
+		b.sparkplug = session
+		messageSpec = api.MQTTMessageOptions{}
+	}
+
+	// validates MQTT v5 only options
+	if !isProtocolV5(clientSpec.ProtocolVersion) {
+		if clientSpec.SessionExpiryInterval != nil || clientSpec.TopicAliasMaximum != nil || messageSpec.Properties != nil || messageSpec.SharedSubscriptionGroup != "" {
+			b.err = errors.Errorf("session expiry interval, topic alias maximum, message properties and shared subscription group require MQTT v5")
+			return
+		}
+	}
+
 	// processes basic authentication
 	if clientSpec.BasicAuth != nil {
 		var basicAuthSpec = clientSpec.BasicAuth
@@ -156,6 +208,31 @@ func (b *ClientBuilder) Render(handler adaptorapi.ReferencesHandler) {
 		}())
 	}
 
+	// processes publishing buffer
+	if bufferSpec := clientSpec.Buffer; bufferSpec != nil {
+		var opts = &buffer.Options{
+			MaxSize:    buffer.DefaultMaxSize,
+			DropPolicy: buffer.DropOldest,
+		}
+		if bufferSpec.MaxSize != nil {
+			opts.MaxSize = bufferSpec.MaxSize.Value()
+		}
+		if bufferSpec.MaxAge != nil {
+			opts.MaxAge = bufferSpec.MaxAge.Duration
+		}
+		if bufferSpec.DropPolicy != "" {
+			opts.DropPolicy = buffer.DropPolicy(bufferSpec.DropPolicy)
+		}
+		if bufferSpec.Type == "File" {
+			var directoryPrefix = "/var/run/octopus/mqtt-buffer"
+			if bufferSpec.DirectoryPrefix != "" {
+				directoryPrefix = bufferSpec.DirectoryPrefix
+			}
+			opts.Directory = filepath.Join(filepath.FromSlash(directoryPrefix), string(ref.UID))
+		}
+		b.buffer = opts
+	}
+
 	// processes client id
 	status.SetClientID(func() string {
 		var clientIDPrefix = "octopus-"
@@ -168,8 +245,71 @@ func (b *ClientBuilder) Render(handler adaptorapi.ReferencesHandler) {
 		return clientID
 	}())
 
+	// processes payload codec
+	if messageSpec.Codec != nil {
+		var payloadCodec, err = buildCodec(messageSpec.Codec, handler)
+		if err != nil {
+			b.err = err
+			return
+		}
+		b.codec = payloadCodec
+	}
+
+	// processes payload template
+	if messageSpec.PayloadTemplate != "" && b.sparkplug == nil {
+		var tmpl, err = newPayloadTemplate(messageSpec.PayloadTemplate, messageSpec.MQTTMessageTopicOperation, ref)
+		if err != nil {
+			b.err = err
+			return
+		}
+		b.template = tmpl
+	}
+
+	// processes per property publishing
+	if perPropertySpec := messageSpec.PerProperty; perPropertySpec != nil && perPropertySpec.Deadband != "" {
+		var deadband, err = strconv.ParseFloat(perPropertySpec.Deadband, 64)
+		if err != nil || deadband < 0 {
+			b.err = errors.Errorf("illegal per property options as invalid deadband %q", perPropertySpec.Deadband)
+			return
+		}
+		b.deadband = deadband
+	}
+
+	// validates batch options
+	if batchSpec := messageSpec.Batch; batchSpec != nil {
+		if messageSpec.PerProperty != nil {
+			b.err = errors.Errorf("illegal batch options as batching is not supported in per property mode")
+			return
+		}
+		if (batchSpec.MaxMessages != nil && *batchSpec.MaxMessages < 1) ||
+			(batchSpec.MaxBytes != nil && batchSpec.MaxBytes.Sign() < 0) ||
+			(batchSpec.MaxDelay != nil && batchSpec.MaxDelay.Duration <= 0) {
+			b.err = errors.Errorf("illegal batch options as non-positive max messages, negative max bytes or non-positive max delay")
+			return
+		}
+	}
+
+	// processes rate limit
+	if rateLimitSpec := messageSpec.RateLimit; rateLimitSpec != nil {
+		var rate, err = strconv.ParseFloat(rateLimitSpec.Rate, 64)
+		if err != nil || rate <= 0 {
+			b.err = errors.Errorf("illegal rate limit options as invalid rate %q", rateLimitSpec.Rate)
+			return
+		}
+		b.rate = rate
+	}
+
 	// processes will message
-	if messageSpec.Will != nil {
+	if session := b.sparkplug; session != nil {
+		// the NDEATH is the will message in Sparkplug B mode.
+		var data, err = session.deathPayload()
+		if err != nil {
+			b.err = errors.Wrap(err, "failed to construct NDEATH payload")
+			return
+		}
+		var willTopic = sparkplug.Topic(session.groupID, sparkplug.NDEATH, session.edgeNodeID, "")
+		status.SetBinaryWill(willTopic, data, 1, false)
+	} else if messageSpec.Will != nil {
 		var topic = messageSpec.Will.Topic
 		if topic == "" {
 			topic = path.Join(messageSpec.Topic, "$will")
@@ -182,7 +322,12 @@ func (b *ClientBuilder) Render(handler adaptorapi.ReferencesHandler) {
 	if clientSpec.Server != "" {
 		status.AddBroker(clientSpec.Server)
 	}
-	if clientSpec.ProtocolVersion != nil {
+	for _, server := range clientSpec.BackupServers {
+		if server != "" {
+			status.AddBroker(string(server))
+		}
+	}
+	if clientSpec.ProtocolVersion != nil && !isProtocolV5(clientSpec.ProtocolVersion) {
 		status.SetProtocolVersion(*clientSpec.ProtocolVersion)
 	}
 	if clientSpec.CleanSession != nil {
@@ -232,23 +377,86 @@ func (b *ClientBuilder) ConfigureOptions(customFunc CustomMQTTOptionFunc) {
 	}
 }
 
+// OnStatusChanged sets the handler which is invoked after connected or the connection lost,
+// the handler should get the latest status via `Client.Status`,
+// as the handlers of successive changes may run concurrently.
+func (b *ClientBuilder) OnStatusChanged(handler func()) {
+	b.statusChanged = handler
+}
+
 // Build returns a MQTT client wrapper.
 func (b *ClientBuilder) Build() (Client, error) {
 	if b.err != nil {
 		return nil, b.err
 	}
+	if b.sparkplug != nil {
+		return b.buildSparkplugB()
+	}
+
+	var messageSpec = b.spec.Message
+	if messageSpec.PerProperty != nil {
+		// appends the `:property` keyword if the topic doesn't contain.
+		var hasKeyword bool
+		for _, seg := range strings.Split(messageSpec.Topic, "/") {
+			if seg == ":property" {
+				hasKeyword = true
+				break
+			}
+		}
+		if !hasKeyword {
+			b.spec.Message.Topic = path.Join(messageSpec.Topic, ":property")
+		}
+	}
 
+	var cli Client
+	var err error
+	if messageSpec.Command != nil {
+		cli, err = b.buildCommand()
+	} else {
+		cli, err = b.build()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if messageSpec.Batch != nil || messageSpec.RateLimit != nil {
+		cli = b.buildBatch(cli)
+	}
+	if messageSpec.PerProperty != nil {
+		cli = &propertyClient{
+			raw:       cli,
+			deadband:  b.deadband,
+			published: map[string]interface{}{},
+		}
+	}
+	return cli, nil
+}
+
+// build returns a MQTT v3 or v5 client wrapper.
+func (b *ClientBuilder) build() (Client, error) {
 	var ref = b.ref
 	var clientSpec = b.spec.Client
 	var messageSpec = b.spec.Message
 	var status = b.status
 
+	if isProtocolV5(clientSpec.ProtocolVersion) {
+		return b.buildV5()
+	}
+
+	var outbox, err = b.buildOutbox()
+	if err != nil {
+		return nil, err
+	}
+	var tracker = b.buildTracker()
+
 	// constructs client
 	var cli = &client{
 		raw:                   mqtt.NewClient(status),
 		topic:                 NewSegmentTopic(messageSpec.Topic, messageSpec.MQTTMessageTopicOperation, ref),
 		qos:                   1,
 		retained:              true,
+		codec:                 b.codec,
+		template:              b.template,
 		subscribeTopicIndexer: SubscribeTopicIndex{},
 	}
 	if clientSpec.WaitTimeout != nil {
@@ -263,6 +471,20 @@ func (b *ClientBuilder) Build() (Client, error) {
 	if messageSpec.Retained != nil {
 		cli.retained = *messageSpec.Retained
 	}
+	if outbox != nil {
+		outbox.publish = cli.publish
+		outbox.connected = cli.raw.IsConnectionOpen
+		cli.outbox = outbox
+	}
+	// paho MQTT v3.x client doesn't tell which broker is connected,
+	// so the server is only reported if there is a single server, as the API docs state.
+	if len(status.Servers) == 1 {
+		var server = status.Servers[0].String()
+		tracker.server = func() string {
+			return server
+		}
+	}
+	cli.tracker = tracker
 
 	log.Println("Build  ",
 		fmt.Sprintf("client (%s/%s, %s)=> "+
@@ -279,6 +501,360 @@ func (b *ClientBuilder) Build() (Client, error) {
 	return cli, nil
 }
 
+// buildV5 returns a MQTT v5 client wrapper, which reuses the rendered paho options.
+func (b *ClientBuilder) buildV5() (Client, error) {
+	var ref = b.ref
+	var clientSpec = b.spec.Client
+	var messageSpec = b.spec.Message
+	var status = b.status
+
+	for _, server := range status.Servers {
+		switch server.Scheme {
+		case "ws", "wss":
+			return nil, errors.Errorf("MQTT v5 doesn't support the %s schema", server.Scheme)
+		}
+	}
+
+	var outbox, err = b.buildOutbox()
+	if err != nil {
+		return nil, err
+	}
+	var tracker = b.buildTracker()
+
+	var opts = v5.Options{
+		Servers:              status.Servers,
+		ClientID:             status.ClientID,
+		Username:             status.Username,
+		Password:             status.Password,
+		CleanStart:           status.CleanSession,
+		TLSConfig:            status.TLSConfig,
+		KeepAlive:            time.Duration(status.KeepAlive) * time.Second,
+		ConnectTimeout:       status.ConnectTimeout,
+		WriteTimeout:         status.WriteTimeout,
+		AutoReconnect:        status.AutoReconnect,
+		MaxReconnectInterval: status.MaxReconnectInterval,
+		Logger:               log,
+	}
+	if status.WillEnabled {
+		var will = &paho.WillMessage{
+			Topic:   status.WillTopic,
+			QoS:     status.WillQos,
+			Retain:  status.WillRetained,
+			Payload: status.WillPayload,
+		}
+		opts.Will = func() *paho.WillMessage {
+			return will
+		}
+		if session := b.sparkplug; session != nil {
+			// the first CONNECT uses the rendered NDEATH, and the later ones renew the bdSeq.
+			var renew bool
+			opts.Will = func() *paho.WillMessage {
+				if !renew {
+					renew = true
+					return will
+				}
+				session.renew()
+				var data, err = session.deathPayload()
+				if err != nil {
+					log.Println("Failed to construct NDEATH payload  ", "error: ", err)
+					return will
+				}
+				var renewed = *will
+				renewed.Payload = data
+				return &renewed
+			}
+		}
+	}
+	if clientSpec.SessionExpiryInterval != nil {
+		var interval = uint32(clientSpec.SessionExpiryInterval.Duration / time.Second)
+		opts.SessionExpiryInterval = &interval
+	}
+	if clientSpec.TopicAliasMaximum != nil {
+		opts.TopicAliasMaximum = uint16(*clientSpec.TopicAliasMaximum)
+	}
+	// the customized handlers receive the adapted client of MQTT v5.
+	var rawV3 = &rawClientV5{options: mqtt.NewClient(status).OptionsReader()}
+	if onConnect := status.OnConnect; onConnect != nil {
+		opts.OnConnect = func() {
+			onConnect(rawV3)
+		}
+	}
+	if onConnectionLost := status.OnConnectionLost; onConnectionLost != nil {
+		opts.OnConnectionLost = func(err error) {
+			onConnectionLost(rawV3, err)
+		}
+	}
+	rawV3.raw = v5.NewClient(opts)
+
+	var cli = &clientV5{
+		raw:                   rawV3.raw,
+		rawV3:                 rawV3,
+		topic:                 NewSegmentTopic(messageSpec.Topic, messageSpec.MQTTMessageTopicOperation, ref),
+		qos:                   1,
+		retained:              true,
+		properties:            messageSpec.Properties,
+		sharedGroup:           messageSpec.SharedSubscriptionGroup,
+		codec:                 b.codec,
+		template:              b.template,
+		subscribeTopicIndexer: SubscribeTopicIndex{},
+	}
+	if clientSpec.WaitTimeout != nil {
+		cli.waitDuration = clientSpec.WaitTimeout.Duration
+		rawV3.waitDuration = clientSpec.WaitTimeout.Duration
+	}
+	if clientSpec.DisconnectQuiesce != nil {
+		cli.disconnectQuiesce = clientSpec.DisconnectQuiesce.Duration
+	}
+	if messageSpec.QoS != nil {
+		cli.qos = byte(*messageSpec.QoS)
+	}
+	if messageSpec.Retained != nil {
+		cli.retained = *messageSpec.Retained
+	}
+	if outbox != nil {
+		outbox.publish = cli.publishBuffered
+		outbox.connected = cli.raw.IsConnected
+		cli.outbox = outbox
+	}
+	tracker.server = func() string {
+		if server := cli.raw.Server(); server != nil {
+			return server.String()
+		}
+		return ""
+	}
+	cli.tracker = tracker
+
+	log.Println("Build  ",
+		fmt.Sprintf("client v5 (%s/%s, %s)=> "+
+			"server: %v, client id: %q, "+
+			"tls: %v, basic auth: %v, "+
+			"will topic: %q, autoconnect: %v",
+			ref.Namespace, ref.Name, ref.UID,
+			status.Servers, status.ClientID,
+			status.TLSConfig != nil, status.Username != "" && status.Password != "",
+			status.WillTopic, status.AutoReconnect,
+		),
+	)
+
+	return cli, nil
+}
+
+// buildBatch returns a batching and rate limiting client wrapper.
+func (b *ClientBuilder) buildBatch(raw Client) Client {
+	var ref = b.ref
+	var messageSpec = b.spec.Message
+
+	var cli = &batchClient{
+		raw:     raw,
+		name:    fmt.Sprintf("%s/%s", ref.Namespace, ref.Name),
+		batches: map[string]*batch{},
+		stop:    make(chan struct{}),
+	}
+	// checks the pending batches in a quarter of the delay or the token interval.
+	var interval = time.Second
+	if batchSpec := messageSpec.Batch; batchSpec != nil {
+		cli.maxMessages = 100
+		cli.maxBytes = 256 * 1024
+		cli.maxDelay = time.Second
+		if batchSpec.MaxMessages != nil {
+			cli.maxMessages = int(*batchSpec.MaxMessages)
+		}
+		if batchSpec.MaxBytes != nil {
+			cli.maxBytes = int(batchSpec.MaxBytes.Value())
+		}
+		if batchSpec.MaxDelay != nil {
+			cli.maxDelay = batchSpec.MaxDelay.Duration
+		}
+		if cli.maxDelay/4 < interval {
+			interval = cli.maxDelay / 4
+		}
+	}
+	if rateLimitSpec := messageSpec.RateLimit; rateLimitSpec != nil {
+		var burst = 1
+		if rateLimitSpec.Burst != nil && *rateLimitSpec.Burst > 0 {
+			burst = int(*rateLimitSpec.Burst)
+		}
+		cli.limiter = newTokenBucket(b.rate, burst)
+		if tokenInterval := time.Duration(float64(time.Second) / b.rate / 4); tokenInterval < interval {
+			interval = tokenInterval
+		}
+	}
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	go cli.run(interval, cli.stop)
+	return cli
+}
+
+// buildTracker returns a tracker to record the connection status via the connection handlers.
+func (b *ClientBuilder) buildTracker() *connectionTracker {
+	var status = b.status
+	var tracker = &connectionTracker{changed: b.statusChanged}
+
+	var onConnect = status.OnConnect
+	status.SetOnConnectHandler(func(c mqtt.Client) {
+		tracker.onConnect()
+		if onConnect != nil {
+			onConnect(c)
+		}
+	})
+	var onConnectionLost = status.OnConnectionLost
+	status.SetConnectionLostHandler(func(c mqtt.Client, err error) {
+		tracker.onConnectionLost(err)
+		if onConnectionLost != nil {
+			onConnectionLost(c, err)
+		}
+	})
+	return tracker
+}
+
+// buildOutbox returns an outbox if buffering the publishing messages,
+// and the outbox replays the buffered messages on every (re)connection.
+func (b *ClientBuilder) buildOutbox() (*outbox, error) {
+	if b.buffer == nil {
+		return nil, nil
+	}
+	var ref = b.ref
+	var status = b.status
+
+	var buf, err = buffer.New(*b.buffer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create publishing buffer")
+	}
+	var ob = &outbox{
+		name:   fmt.Sprintf("%s/%s", ref.Namespace, ref.Name),
+		buffer: buf,
+	}
+
+	var onConnect = status.OnConnect
+	status.SetOnConnectHandler(func(c mqtt.Client) {
+		if onConnect != nil {
+			onConnect(c)
+		}
+		go ob.Replay()
+	})
+	return ob, nil
+}
+
+// buildSparkplugB returns a Sparkplug B client wrapper, which publishes the births after connected.
+func (b *ClientBuilder) buildSparkplugB() (Client, error) {
+	var status = b.status
+	var cli = &sparkplugClient{session: b.sparkplug}
+
+	var onConnect = status.OnConnect
+	status.SetOnConnectHandler(func(c mqtt.Client) {
+		if onConnect != nil {
+			onConnect(c)
+		}
+		cli.onConnect()
+	})
+	var onConnectionLost = status.OnConnectionLost
+	status.SetConnectionLostHandler(func(c mqtt.Client, err error) {
+		if onConnectionLost != nil {
+			onConnectionLost(c, err)
+		}
+		cli.onConnectionLost()
+	})
+	// renews the bdSeq of NDEATH before reconnecting, MQTT v5 renews it in the will of each CONNECT.
+	var onReconnecting = status.OnReconnecting
+	status.SetReconnectingHandler(func(c mqtt.Client, opts *mqtt.ClientOptions) {
+		if onReconnecting != nil {
+			onReconnecting(c, opts)
+		}
+		b.sparkplug.renew()
+		var data, err = b.sparkplug.deathPayload()
+		if err != nil {
+			log.Println("Failed to construct NDEATH payload  ", "error: ", err)
+			return
+		}
+		opts.WillPayload = data
+	})
+
+	// the inner client publishes to the topic rendered with "type" and "device".
+	b.spec.Message = api.MQTTMessageOptions{Topic: b.sparkplug.topic()}
+	b.codec = nil
+	b.template = nil
+	var raw, err = b.build()
+	if err != nil {
+		return nil, err
+	}
+	cli.raw = raw
+	return cli, nil
+}
+
+// buildCommand returns a command client wrapper, which subscribes the command topic.
+func (b *ClientBuilder) buildCommand() (Client, error) {
+	var ref = b.ref
+	var messageSpec = b.spec.Message
+	var status = b.status
+
+	var commandTopic = messageSpec.Command.Topic
+	if commandTopic == "" {
+		commandTopic = path.Join(messageSpec.Topic, "$cmd")
+	}
+	var replyTopic = messageSpec.Command.ReplyTopic
+	if replyTopic == "" {
+		replyTopic = path.Join(commandTopic, "$reply")
+	}
+	var cli = &commandClient{
+		topic:        NewSegmentTopic(messageSpec.Topic, messageSpec.MQTTMessageTopicOperation, ref),
+		commandTopic: NewSegmentTopic(commandTopic, messageSpec.MQTTMessageTopicOperation, ref),
+		replyTopic:   NewSegmentTopic(replyTopic, messageSpec.MQTTMessageTopicOperation, ref),
+	}
+
+	var onConnect = status.OnConnect
+	status.SetOnConnectHandler(func(c mqtt.Client) {
+		if onConnect != nil {
+			onConnect(c)
+		}
+		cli.onConnect()
+	})
+
+	// the inner client publishes/subscribes to the topic rendered by the command client.
+	b.spec.Message.Topic = ":topic"
+	b.spec.Message.MQTTMessageTopicOperation = api.MQTTMessageTopicOperation{}
+	var raw, err = b.build()
+	if err != nil {
+		return nil, err
+	}
+	cli.raw = raw
+	return cli, nil
+}
+
+// buildCodec returns the payload codec with the codec options.
+func buildCodec(spec *api.MQTTMessagePayloadCodec, handler adaptorapi.ReferencesHandler) (codec.Codec, error) {
+	switch spec.Type {
+	case api.MQTTMessagePayloadCodecTypeCBOR:
+		return codec.CBOR, nil
+	case api.MQTTMessagePayloadCodecTypeMessagePack:
+		return codec.MessagePack, nil
+	case api.MQTTMessagePayloadCodecTypeProtobuf:
+		var protobufSpec = spec.Protobuf
+		if protobufSpec == nil || protobufSpec.DescriptorSetRef == nil || protobufSpec.MessageType == "" {
+			return nil, errors.Errorf("illegal protobuf codec as blank descriptor set reference or message type")
+		}
+		if handler == nil {
+			return nil, errors.Errorf("references handler is nil")
+		}
+		var ref = protobufSpec.DescriptorSetRef
+		var descriptorSet = handler.GetData(ref.Name, ref.Item)
+		if len(descriptorSet) == 0 {
+			return nil, errors.Errorf("illegal protobuf codec as blank descriptor set")
+		}
+		var payloadCodec, err = codec.NewProtobuf(descriptorSet, protobufSpec.MessageType)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create protobuf codec")
+		}
+		return payloadCodec, nil
+	default:
+		return codec.JSON, nil
+	}
+}
+
+func isProtocolV5(version *uint) bool {
+	return version != nil && *version == 5
+}
+
 // NewClientBuilder creates the MQTT client builder.
 func NewClientBuilder(spec api.MQTTOptions, ref corev1.ObjectReference) *ClientBuilder {
 	return &ClientBuilder{ref: ref, spec: &spec, status: mqtt.NewClientOptions()}
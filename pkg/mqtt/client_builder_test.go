@@ -4,14 +4,20 @@
 	"crypto/tls"
 	"crypto/x509"
 	"testing"
+	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
 	adaptorapi "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
 	"github.com/rancher/octopus/pkg/mqtt/api"
+	"github.com/rancher/octopus/pkg/mqtt/codec"
+	"github.com/rancher/octopus/pkg/mqtt/sparkplug"
 	"github.com/rancher/octopus/test/util/testdata"
 )
 
@@ -56,6 +62,29 @@ type expected struct {
 					SetBinaryWill("static/topic-will", []byte("closed"), 1, true),
 			},
 		},
+		{
+			name: "failover to backup servers",
+			given: given{
+				spec: api.MQTTOptions{
+					Client: api.MQTTClientOptions{
+						Server:        "tcp://primary:1883",
+						BackupServers: []api.MQTTClientServer{"tcp://backup-1:1883", "ssl://backup-2:8883"},
+					},
+				},
+				ref: corev1.ObjectReference{
+					Namespace: "default",
+					Name:      "test",
+					UID:       "835aea2e-5f80-4d14-88f5-40c4bda41aa3",
+				},
+			},
+			expected: expected{
+				ret: mqtt.NewClientOptions().
+					SetClientID("octopus-835aea2e5f804d1488f540c4bda41aa3").
+					AddBroker("tcp://primary:1883").
+					AddBroker("tcp://backup-1:1883").
+					AddBroker("ssl://backup-2:8883"),
+			},
+		},
 		{
 			name: "will message without static topic name",
 			given: given{
@@ -246,6 +275,31 @@ type expected struct {
 					SetClientID("octopus-efcb04957c34593"),
 			},
 		},
+		{
+			name: "configures protocol version as 5",
+			given: given{
+				spec: api.MQTTOptions{
+					Client: api.MQTTClientOptions{
+						ProtocolVersion:   func() *uint { var i uint = 5; return &i }(),
+						TopicAliasMaximum: func() *uint { var i uint = 10; return &i }(),
+					},
+					Message: api.MQTTMessageOptions{
+						Properties: &api.MQTTMessageProperties{
+							ContentType: "application/json",
+						},
+					},
+				},
+				ref: corev1.ObjectReference{
+					Namespace: "default",
+					Name:      "test",
+					UID:       "4d4d3b05-6ac8-4a5f-9b44-bd6e8dbcd0d0",
+				},
+			},
+			expected: expected{
+				ret: mqtt.NewClientOptions().
+					SetClientID("octopus-4d4d3b056ac84a5f9b44bd6e8dbcd0d0"),
+			},
+		},
 	}
 
 	// NB(thxCode) according to https://golang.org/src/reflect/deepequal.go, the Func values are equal only if both are nil.
@@ -270,3 +324,397 @@ type expected struct {
 		}
 	}
 }
+
+func TestClientBuilder_Build(t *testing.T) {
+	var ref = corev1.ObjectReference{
+		Namespace: "default",
+		Name:      "test",
+		UID:       "4d4d3b05-6ac8-4a5f-9b44-bd6e8dbcd0d0",
+	}
+
+	var testCases = []struct {
+		name     string
+		given    api.MQTTOptions
+		expected bool
+	}{
+		{
+			name: "message properties require MQTT v5",
+			given: api.MQTTOptions{
+				Client: api.MQTTClientOptions{
+					Server: "tcp://127.0.0.1:1883",
+				},
+				Message: api.MQTTMessageOptions{
+					Properties: &api.MQTTMessageProperties{
+						ContentType: "application/json",
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "MQTT v5 doesn't support websocket",
+			given: api.MQTTOptions{
+				Client: api.MQTTClientOptions{
+					Server:          "ws://127.0.0.1:1883",
+					ProtocolVersion: func() *uint { var i uint = 5; return &i }(),
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "shared subscription requires MQTT v5",
+			given: api.MQTTOptions{
+				Client: api.MQTTClientOptions{
+					Server: "tcp://127.0.0.1:1883",
+				},
+				Message: api.MQTTMessageOptions{
+					SharedSubscriptionGroup: "group",
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "MQTT v5 with shared subscription",
+			given: api.MQTTOptions{
+				Client: api.MQTTClientOptions{
+					Server:          "tcp://127.0.0.1:1883",
+					ProtocolVersion: func() *uint { var i uint = 5; return &i }(),
+				},
+				Message: api.MQTTMessageOptions{
+					SharedSubscriptionGroup: "group",
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "Sparkplug B mode",
+			given: api.MQTTOptions{
+				Client: api.MQTTClientOptions{
+					Server: "tcp://127.0.0.1:1883",
+				},
+				SparkplugB: &api.MQTTSparkplugBOptions{
+					GroupID: "group",
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "per property mode",
+			given: api.MQTTOptions{
+				Client: api.MQTTClientOptions{
+					Server: "tcp://127.0.0.1:1883",
+				},
+				Message: api.MQTTMessageOptions{
+					Topic: "devices/:name",
+					PerProperty: &api.MQTTMessagePerPropertyOptions{
+						Deadband: "0.5",
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "command mode",
+			given: api.MQTTOptions{
+				Client: api.MQTTClientOptions{
+					Server: "tcp://127.0.0.1:1883",
+				},
+				Message: api.MQTTMessageOptions{
+					Topic:   "devices/:name",
+					Command: &api.MQTTMessageCommandOptions{},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "batch with rate limit",
+			given: api.MQTTOptions{
+				Client: api.MQTTClientOptions{
+					Server: "tcp://127.0.0.1:1883",
+				},
+				Message: api.MQTTMessageOptions{
+					Topic: "devices/:name",
+					Batch: &api.MQTTMessageBatchOptions{
+						MaxDelay: &metav1.Duration{Duration: 500 * time.Millisecond},
+					},
+					RateLimit: &api.MQTTMessageRateLimitOptions{
+						Rate: "0.5",
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "batch with illegal max delay",
+			given: api.MQTTOptions{
+				Client: api.MQTTClientOptions{
+					Server: "tcp://127.0.0.1:1883",
+				},
+				Message: api.MQTTMessageOptions{
+					Topic: "devices/:name",
+					Batch: &api.MQTTMessageBatchOptions{
+						MaxDelay: &metav1.Duration{},
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "batch in per property mode",
+			given: api.MQTTOptions{
+				Client: api.MQTTClientOptions{
+					Server: "tcp://127.0.0.1:1883",
+				},
+				Message: api.MQTTMessageOptions{
+					Topic:       "devices/:name",
+					Batch:       &api.MQTTMessageBatchOptions{},
+					PerProperty: &api.MQTTMessagePerPropertyOptions{},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "rate limit with illegal rate",
+			given: api.MQTTOptions{
+				Client: api.MQTTClientOptions{
+					Server: "tcp://127.0.0.1:1883",
+				},
+				Message: api.MQTTMessageOptions{
+					Topic: "devices/:name",
+					RateLimit: &api.MQTTMessageRateLimitOptions{
+						Rate: "0",
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "per property mode with illegal deadband",
+			given: api.MQTTOptions{
+				Client: api.MQTTClientOptions{
+					Server: "tcp://127.0.0.1:1883",
+				},
+				Message: api.MQTTMessageOptions{
+					Topic: "devices/:name",
+					PerProperty: &api.MQTTMessagePerPropertyOptions{
+						Deadband: "-1",
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "Sparkplug B mode with illegal group ID",
+			given: api.MQTTOptions{
+				Client: api.MQTTClientOptions{
+					Server: "tcp://127.0.0.1:1883",
+				},
+				SparkplugB: &api.MQTTSparkplugBOptions{
+					GroupID: "group/a",
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		var cb = NewClientBuilder(tc.given, ref)
+		cb.Render(nil)
+		var cli, err = cb.Build()
+		if tc.expected {
+			assert.NoError(t, err, "case %q", tc.name)
+			cli.Disconnect()
+		} else {
+			assert.Error(t, err, "case %q", tc.name)
+		}
+	}
+}
+
+func TestClientBuilder_BuildV5(t *testing.T) {
+	var ref = corev1.ObjectReference{
+		Namespace: "default",
+		Name:      "test",
+		UID:       "4d4d3b05-6ac8-4a5f-9b44-bd6e8dbcd0d0",
+	}
+	var cb = NewClientBuilder(api.MQTTOptions{
+		Client: api.MQTTClientOptions{
+			Server:          "tcp://127.0.0.1:1883",
+			ProtocolVersion: func() *uint { var i uint = 5; return &i }(),
+		},
+	}, ref)
+	cb.Render(nil)
+	var cli, err = cb.Build()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer cli.Disconnect()
+
+	// the raw client is adapted in MQTT v5
+	var raw = cli.RawClient()
+	if assert.NotNil(t, raw) {
+		var options = raw.OptionsReader()
+		assert.Equal(t, cb.GetOptions().ClientID, options.ClientID())
+		assert.False(t, raw.IsConnected())
+	}
+}
+
+func TestClientBuilder_BuildSparkplugB(t *testing.T) {
+	var ref = corev1.ObjectReference{
+		Namespace: "default",
+		Name:      "test",
+		UID:       "e4b4a4b5-0c4e-4b9f-8b0b-3f4e8a3b0d51",
+	}
+	var cb = NewClientBuilder(api.MQTTOptions{
+		Client: api.MQTTClientOptions{
+			Server: "tcp://127.0.0.1:1883",
+		},
+		SparkplugB: &api.MQTTSparkplugBOptions{
+			GroupID: "group",
+		},
+	}, ref)
+	cb.Render(nil)
+	var cli, err = cb.Build()
+	if !assert.NoError(t, err) {
+		return
+	}
+	var session = cli.(*sparkplugClient).session
+
+	var bdSeqOf = func(data []byte) interface{} {
+		var payload, err = sparkplug.Unmarshal(data)
+		if !assert.NoError(t, err) || !assert.Len(t, payload.Metrics, 1) {
+			return nil
+		}
+		return payload.Metrics[0].Value
+	}
+
+	// the will of the first CONNECT carries the rendered bdSeq
+	var options = cb.GetOptions()
+	assert.Equal(t, int64(0), bdSeqOf(options.WillPayload))
+	assert.Equal(t, uint64(0), session.currentBDSeq())
+
+	// the will of each reconnection carries the increased bdSeq, which is shared with NBIRTH
+	for _, expected := range []int64{1, 2} {
+		options.OnReconnecting(nil, options)
+		assert.Equal(t, expected, bdSeqOf(options.WillPayload))
+		assert.Equal(t, uint64(expected), session.currentBDSeq())
+	}
+}
+
+func TestBuildCodec(t *testing.T) {
+	var descriptorSet, err = proto.Marshal(&descriptor.FileDescriptorSet{
+		File: []*descriptor.FileDescriptorProto{
+			{
+				Name:    proto.String("status.proto"),
+				Package: proto.String("test"),
+				Syntax:  proto.String("proto3"),
+				MessageType: []*descriptor.DescriptorProto{
+					{
+						Name: proto.String("Status"),
+						Field: []*descriptor.FieldDescriptorProto{
+							{
+								Name:   proto.String("name"),
+								Number: proto.Int32(1),
+								Label:  descriptor.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+								Type:   descriptor.FieldDescriptorProto_TYPE_STRING.Enum(),
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var handler = adaptorapi.ReferencesHandler(
+		map[string]*adaptorapi.ConnectRequestReferenceEntry{
+			"proto": {
+				Items: map[string][]byte{
+					"descriptor": descriptorSet,
+				},
+			},
+		},
+	)
+
+	type given struct {
+		spec    api.MQTTMessagePayloadCodec
+		handler adaptorapi.ReferencesHandler
+	}
+	var testCases = []struct {
+		name     string
+		given    given
+		expected codec.Codec
+		err      bool
+	}{
+		{
+			name:     "default",
+			given:    given{spec: api.MQTTMessagePayloadCodec{}},
+			expected: codec.JSON,
+		},
+		{
+			name:     "CBOR",
+			given:    given{spec: api.MQTTMessagePayloadCodec{Type: api.MQTTMessagePayloadCodecTypeCBOR}},
+			expected: codec.CBOR,
+		},
+		{
+			name:     "MessagePack",
+			given:    given{spec: api.MQTTMessagePayloadCodec{Type: api.MQTTMessagePayloadCodecTypeMessagePack}},
+			expected: codec.MessagePack,
+		},
+		{
+			name:  "Protobuf without options",
+			given: given{spec: api.MQTTMessagePayloadCodec{Type: api.MQTTMessagePayloadCodecTypeProtobuf}},
+			err:   true,
+		},
+		{
+			name: "Protobuf without references handler",
+			given: given{
+				spec: api.MQTTMessagePayloadCodec{
+					Type: api.MQTTMessagePayloadCodecTypeProtobuf,
+					Protobuf: &api.MQTTMessagePayloadProtobufCodec{
+						DescriptorSetRef: &edgev1alpha1.DeviceLinkReferenceRelationship{Name: "proto", Item: "descriptor"},
+						MessageType:      "test.Status",
+					},
+				},
+			},
+			err: true,
+		},
+		{
+			name: "Protobuf with unknown message type",
+			given: given{
+				spec: api.MQTTMessagePayloadCodec{
+					Type: api.MQTTMessagePayloadCodecTypeProtobuf,
+					Protobuf: &api.MQTTMessagePayloadProtobufCodec{
+						DescriptorSetRef: &edgev1alpha1.DeviceLinkReferenceRelationship{Name: "proto", Item: "descriptor"},
+						MessageType:      "test.Unknown",
+					},
+				},
+				handler: handler,
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual, err = buildCodec(&tc.given.spec, tc.given.handler)
+		if tc.err {
+			assert.Error(t, err, "case %q", tc.name)
+			continue
+		}
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+
+	// encodes with the protobuf codec which is built from references
+	var actual, _ = buildCodec(&api.MQTTMessagePayloadCodec{
+		Type: api.MQTTMessagePayloadCodecTypeProtobuf,
+		Protobuf: &api.MQTTMessagePayloadProtobufCodec{
+			DescriptorSetRef: &edgev1alpha1.DeviceLinkReferenceRelationship{Name: "proto", Item: "descriptor"},
+			MessageType:      "test.Status",
+		},
+	}, handler)
+	var payload, encoded, encodeErr = encodePayload(actual, map[string]interface{}{"name": "a"})
+	assert.NoError(t, encodeErr)
+	assert.True(t, encoded)
+	assert.Equal(t, []byte{0x0a, 0x01, 'a'}, payload)
+}
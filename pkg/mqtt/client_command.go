@@ -0,0 +1,144 @@
+package mqtt
+
+import (
+	"sync"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/rancher/octopus/pkg/mqtt/api"
+	"github.com/rancher/octopus/pkg/util/collection"
+)
+
+// CommandResult is the result of writing a property via command.
+type CommandResult struct {
+	// Reports the name of property.
+	Name string `json:"name"`
+
+	// Reports the value of property after writing.
+	Value interface{} `json:"value,omitempty"`
+
+	// Reports the error of writing.
+	Error string `json:"error,omitempty"`
+}
+
+// CommandReply is the reply of command.
+type CommandReply struct {
+	// Reports the results of writing properties.
+	Results []CommandResult `json:"results"`
+}
+
+// commandClient is the command implementation of Client,
+// it subscribes the command topic and publishes the reply of command to the reply topic.
+type commandClient struct {
+	sync.Mutex
+
+	// the raw client renders the topic with the `:topic` keyword only,
+	// so that the command client can publish/subscribe any rendered topics.
+	raw          Client
+	topic        SegmentTopic
+	commandTopic SegmentTopic
+	replyTopic   SegmentTopic
+	handler      SubscribeHandler
+}
+
+func (c *commandClient) Connect() error {
+	return c.raw.Connect()
+}
+
+func (c *commandClient) Disconnect() {
+	c.raw.Disconnect()
+}
+
+func (c *commandClient) RawClient() mqtt.Client {
+	return c.raw.RawClient()
+}
+
+func (c *commandClient) Status() api.MQTTConnectionStatus {
+	return c.raw.Status()
+}
+
+// Subscribe subscribes the command topic if the topics are empty,
+// otherwise subscribes the topics rendered with the topic of message.
+func (c *commandClient) Subscribe(topics []SubscribeTopic, handler SubscribeHandler) error {
+	if len(topics) != 0 {
+		var renderedTopics = make([]SubscribeTopic, 0, len(topics))
+		for _, topic := range topics {
+			renderedTopics = append(renderedTopics, SubscribeTopic{
+				Index:      topic.Index,
+				Render:     map[string]string{"topic": c.topic.RenderForSubscribe(topic.Render)},
+				QoSPointer: topic.QoSPointer,
+			})
+		}
+		return c.raw.Subscribe(renderedTopics, handler)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.handler = handler
+	return c.subscribeCommand()
+}
+
+func (c *commandClient) Publish(message PublishMessage) error {
+	if message.Payload == nil {
+		return nil
+	}
+	// keeps the rendering values for the payload template.
+	var render = collection.StringMapCopy(message.Render)
+	render["topic"] = c.topic.RenderForPublish(message.Render)
+	message.Render = render
+	return c.raw.Publish(message)
+}
+
+// onConnect resubscribes the command topic after reconnected.
+func (c *commandClient) onConnect() {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.handler == nil {
+		return
+	}
+	if err := c.subscribeCommand(); err != nil {
+		log.Println("Failed to subscribe commands  ", "error: ", err)
+	}
+}
+
+func (c *commandClient) subscribeCommand() error {
+	if c.handler == nil {
+		return nil
+	}
+	var handler = c.handler
+	var topics = []SubscribeTopic{
+		{Render: map[string]string{"topic": c.commandTopic.RenderForSubscribe()}},
+	}
+	return c.raw.Subscribe(topics, func(msg SubscribeMessage) {
+		msg.reply = func(payload interface{}) error {
+			return c.publishReply(msg, payload)
+		}
+		handler(msg)
+	})
+}
+
+// publishReply publishes the reply to the reply topic,
+// or to the response topic of command in MQTT v5.
+func (c *commandClient) publishReply(msg SubscribeMessage, payload interface{}) error {
+	var topic = c.replyTopic.RenderForPublish()
+	var properties *api.MQTTMessageProperties
+	if msg.Properties != nil {
+		if msg.Properties.ResponseTopic != "" {
+			topic = msg.Properties.ResponseTopic
+		}
+		if msg.Properties.CorrelationData != "" {
+			properties = &api.MQTTMessageProperties{CorrelationData: msg.Properties.CorrelationData}
+		}
+	}
+	var retained = false
+	log.Println("Reply Command  ", "topic: ", topic)
+	return c.raw.Publish(PublishMessage{
+		Render:          map[string]string{"topic": topic},
+		RetainedPointer: &retained,
+		Payload:         payload,
+		Properties:      properties,
+		skipTemplate:    true,
+	})
+}
@@ -0,0 +1,63 @@
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/rancher/octopus/pkg/mqtt/api"
+)
+
+func TestCommandClient(t *testing.T) {
+	var ref = corev1.ObjectReference{
+		Namespace: "default",
+		Name:      "test",
+		UID:       "uid-xyz",
+	}
+	var operation = api.MQTTMessageTopicOperation{
+		Operator: &api.MQTTMessageTopicOperator{
+			Read:  "set",
+			Write: "status",
+		},
+	}
+
+	var raw = &fakeRawClient{}
+	var cli = &commandClient{
+		raw:          raw,
+		topic:        NewSegmentTopic("devices/:namespace/:name/:operator", operation, ref),
+		commandTopic: NewSegmentTopic("devices/:namespace/:name/:operator", operation, ref),
+		replyTopic:   NewSegmentTopic("devices/:namespace/:name/$reply", operation, ref),
+	}
+
+	// publishes to the topic of message
+	assert.NoError(t, cli.Publish(PublishMessage{Payload: "status"}))
+	assert.Equal(t, map[string]string{"topic": "devices/default/test/status"}, raw.published[0].Render)
+
+	// subscribes the command topic
+	var received []SubscribeMessage
+	assert.NoError(t, cli.Subscribe(nil, func(msg SubscribeMessage) {
+		received = append(received, msg)
+		assert.NoError(t, msg.Reply(CommandReply{Results: []CommandResult{{Name: "switch", Value: "true"}}}))
+	}))
+	assert.Equal(t, map[string]string{"topic": "devices/default/test/set"}, raw.subscribed[0].Render)
+
+	// replies to the reply topic
+	raw.handler(SubscribeMessage{Topic: "devices/default/test/set", Payload: []byte(`{"switch":"true"}`)})
+	assert.Len(t, received, 1)
+	assert.Equal(t, map[string]string{"topic": "devices/default/test/$reply"}, raw.published[1].Render)
+	assert.False(t, *raw.published[1].RetainedPointer)
+
+	// replies to the response topic in MQTT v5
+	raw.handler(SubscribeMessage{
+		Topic:   "devices/default/test/set",
+		Payload: []byte(`{"switch":"true"}`),
+		Properties: &api.MQTTMessageProperties{
+			ResponseTopic:   "apps/response",
+			CorrelationData: "xyz",
+		},
+	})
+	assert.Len(t, received, 2)
+	assert.Equal(t, map[string]string{"topic": "apps/response"}, raw.published[2].Render)
+	assert.Equal(t, &api.MQTTMessageProperties{CorrelationData: "xyz"}, raw.published[2].Properties)
+}
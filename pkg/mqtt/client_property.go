@@ -0,0 +1,168 @@
+package mqtt
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+
+	"github.com/rancher/octopus/pkg/mqtt/api"
+)
+
+// propertyClient is the per property implementation of Client,
+// it publishes each changed property to the topic rendered with `:property`.
+type propertyClient struct {
+	sync.Mutex
+
+	raw      Client
+	deadband float64
+	// published records the last published value of properties.
+	published map[string]interface{}
+}
+
+func (c *propertyClient) Connect() error {
+	return c.raw.Connect()
+}
+
+func (c *propertyClient) Disconnect() {
+	c.raw.Disconnect()
+}
+
+func (c *propertyClient) RawClient() mqtt.Client {
+	return c.raw.RawClient()
+}
+
+func (c *propertyClient) Status() api.MQTTConnectionStatus {
+	return c.raw.Status()
+}
+
+func (c *propertyClient) Subscribe(topics []SubscribeTopic, handler SubscribeHandler) error {
+	return c.raw.Subscribe(topics, handler)
+}
+
+// Publish splits the payload into properties, and publishes the changed properties one by one.
+func (c *propertyClient) Publish(message PublishMessage) error {
+	if message.Payload == nil {
+		return nil
+	}
+	var props, err = toPublishProperties(message.Payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to split payload into properties")
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	for _, prop := range props {
+		if !c.changed(prop.name, prop.value) {
+			continue
+		}
+
+		var render = make(map[string]string, len(message.Render)+1)
+		for k, v := range message.Render {
+			render[k] = v
+		}
+		render["property"] = prop.name
+		var propMessage = message
+		propMessage.Render = render
+		propMessage.Payload = prop.payload
+		if err := c.raw.Publish(propMessage); err != nil {
+			return errors.Wrapf(err, "failed to publish property %s", prop.name)
+		}
+		c.published[prop.name] = prop.value
+	}
+	return nil
+}
+
+// changed returns true if the property has not been published,
+// or the value has changed more than the deadband since the last publishing.
+func (c *propertyClient) changed(name string, value interface{}) bool {
+	var last, exist = c.published[name]
+	if !exist {
+		return true
+	}
+	if c.deadband > 0 {
+		var lastNumber, lastIsNumber = toPublishNumber(last)
+		var number, isNumber = toPublishNumber(value)
+		if lastIsNumber && isNumber {
+			return math.Abs(number-lastNumber) > c.deadband
+		}
+	}
+	return !reflect.DeepEqual(last, value)
+}
+
+type publishProperty struct {
+	name    string
+	value   interface{}
+	payload interface{}
+}
+
+// toPublishProperties splits the payload into properties,
+// the items of `properties` list are split by their `name` and compared by their `value`,
+// otherwise the fields of payload are split by their keys.
+func toPublishProperties(payload interface{}) ([]publishProperty, error) {
+	var data, err = json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var decoder = json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	var obj, isObj = generic.(map[string]interface{})
+	if !isObj {
+		return []publishProperty{{value: generic, payload: generic}}, nil
+	}
+	var props []publishProperty
+	if items, ok := obj["properties"].([]interface{}); ok {
+		for _, item := range items {
+			var prop, ok = item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var name, _ = prop["name"].(string)
+			if name == "" {
+				continue
+			}
+			props = append(props, publishProperty{name: name, value: prop["value"], payload: prop})
+		}
+		return props, nil
+	}
+
+	var keys = make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		props = append(props, publishProperty{name: k, value: obj[k], payload: obj[k]})
+	}
+	return props, nil
+}
+
+// toPublishNumber converts the value into float64 if the value is numeric or a numeric string.
+func toPublishNumber(value interface{}) (float64, bool) {
+	var s string
+	switch v := value.(type) {
+	case json.Number:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return 0, false
+	}
+	var f, err = strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
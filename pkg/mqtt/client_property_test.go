@@ -0,0 +1,121 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rancher/octopus/pkg/mqtt/api"
+)
+
+func TestPropertyClient_Publish(t *testing.T) {
+	type property struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	type status struct {
+		Properties []property `json:"properties"`
+	}
+	type given struct {
+		deadband float64
+		payloads []interface{}
+	}
+
+	var testCases = []struct {
+		name     string
+		given    given
+		expected map[string][]interface{}
+	}{
+		{
+			name: "publishes the changed properties",
+			given: given{
+				payloads: []interface{}{
+					status{Properties: []property{{Name: "temperature", Value: "23.5"}, {Name: "switch", Value: "true"}}},
+					status{Properties: []property{{Name: "temperature", Value: "24"}, {Name: "switch", Value: "true"}}},
+				},
+			},
+			expected: map[string][]interface{}{
+				"temperature": {"23.5", "24"},
+				"switch":      {"true"},
+			},
+		},
+		{
+			name: "publishes the numeric properties changed more than deadband",
+			given: given{
+				deadband: 1,
+				payloads: []interface{}{
+					status{Properties: []property{{Name: "temperature", Value: "23.5"}, {Name: "switch", Value: "true"}}},
+					status{Properties: []property{{Name: "temperature", Value: "24"}, {Name: "switch", Value: "false"}}},
+					status{Properties: []property{{Name: "temperature", Value: "24.6"}, {Name: "switch", Value: "false"}}},
+				},
+			},
+			expected: map[string][]interface{}{
+				"temperature": {"23.5", "24.6"},
+				"switch":      {"true", "false"},
+			},
+		},
+		{
+			name: "publishes the changed fields",
+			given: given{
+				deadband: 0.5,
+				payloads: []interface{}{
+					map[string]interface{}{"temperature": 23.5, "model": "x"},
+					map[string]interface{}{"temperature": 23.8, "model": "y"},
+				},
+			},
+			expected: map[string][]interface{}{
+				"temperature": {json.Number("23.5")},
+				"model":       {"x", "y"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var raw = &fakeRawClient{}
+		var cli = &propertyClient{
+			raw:       raw,
+			deadband:  tc.given.deadband,
+			published: map[string]interface{}{},
+		}
+		for _, payload := range tc.given.payloads {
+			assert.NoError(t, cli.Publish(PublishMessage{Payload: payload}), "case %q", tc.name)
+		}
+
+		var actual = map[string][]interface{}{}
+		for _, msg := range raw.published {
+			var name = msg.Render["property"]
+			var value = msg.Payload
+			if prop, ok := value.(map[string]interface{}); ok {
+				value = prop["value"]
+			}
+			actual[name] = append(actual[name], value)
+		}
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
+func TestPropertyClient_Publish_KeepsMessage(t *testing.T) {
+	var raw = &fakeRawClient{}
+	var cli = &propertyClient{
+		raw:       raw,
+		published: map[string]interface{}{},
+	}
+	var qos = byte(1)
+	var retained = true
+	var message = PublishMessage{
+		Render:          map[string]string{"device": "fan"},
+		QoSPointer:      &qos,
+		RetainedPointer: &retained,
+		Payload:         map[string]interface{}{"speed": 1},
+		Properties:      &api.MQTTMessageProperties{ResponseTopic: "fan/reply", CorrelationData: "1"},
+		skipTemplate:    true,
+	}
+	assert.NoError(t, cli.Publish(message))
+
+	// publishes the property with the settings of the given message
+	var expected = message
+	expected.Render = map[string]string{"device": "fan", "property": "speed"}
+	expected.Payload = json.Number("1")
+	assert.Equal(t, []PublishMessage{expected}, raw.published)
+}
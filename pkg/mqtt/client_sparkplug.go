@@ -0,0 +1,474 @@
+package mqtt
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/rancher/octopus/pkg/mqtt/api"
+	"github.com/rancher/octopus/pkg/mqtt/sparkplug"
+)
+
+// sparkplugSession is the identity of Sparkplug B edge node.
+type sparkplugSession struct {
+	uid        types.UID
+	groupID    string
+	edgeNodeID string
+	deviceID   string
+	// bdSeq is the birth/death sequence number of current MQTT CONNECT, it must be accessed atomically.
+	bdSeq uint64
+}
+
+// renew increases the bdSeq for a new MQTT CONNECT.
+func (s *sparkplugSession) renew() {
+	atomic.StoreUint64(&s.bdSeq, nextSparkplugBDSeq(s.uid))
+}
+
+// currentBDSeq returns the bdSeq of current MQTT CONNECT,
+// the NBIRTH must carry the same bdSeq as the will message of the connection.
+func (s *sparkplugSession) currentBDSeq() uint64 {
+	return atomic.LoadUint64(&s.bdSeq)
+}
+
+// topic returns the topic template which can be rendered with "type" and "device".
+func (s *sparkplugSession) topic() string {
+	return strings.Join([]string{sparkplug.Namespace, s.groupID, ":type", s.edgeNodeID, ":device"}, "/")
+}
+
+// deathPayload returns the NDEATH payload of current bdSeq, which is used as will message.
+func (s *sparkplugSession) deathPayload() ([]byte, error) {
+	var payload = &sparkplug.Payload{
+		Timestamp: nowMilliseconds(),
+		Metrics: []sparkplug.Metric{
+			{Name: sparkplug.BDSeq, DataType: sparkplug.Int64, Value: int64(s.currentBDSeq())},
+		},
+	}
+	return payload.Marshal()
+}
+
+var sparkplugBDSeqs = struct {
+	sync.Mutex
+	seqs map[types.UID]uint64
+}{seqs: map[types.UID]uint64{}}
+
+// nextSparkplugBDSeq returns the birth/death sequence number of the given object,
+// the number increases for each MQTT CONNECT.
+func nextSparkplugBDSeq(uid types.UID) uint64 {
+	sparkplugBDSeqs.Lock()
+	defer sparkplugBDSeqs.Unlock()
+
+	var seq = sparkplugBDSeqs.seqs[uid]
+	sparkplugBDSeqs.seqs[uid] = (seq + 1) % 256
+	return seq
+}
+
+// sparkplugClient is the Sparkplug B implementation of Client,
+// it treats the device as the only device of edge node.
+type sparkplugClient struct {
+	sync.Mutex
+
+	raw     Client
+	session *sparkplugSession
+	handler SubscribeHandler
+
+	seq        uint64
+	nodeBorn   bool
+	deviceBorn bool
+	// metrics records the latest metrics of device.
+	metrics []sparkplug.Metric
+	// births records the metrics of last DBIRTH.
+	births []sparkplug.Metric
+	// published records the last published value of metrics.
+	published map[string]interface{}
+}
+
+func (c *sparkplugClient) Connect() error {
+	// the births are published in the OnConnect handler.
+	return c.raw.Connect()
+}
+
+func (c *sparkplugClient) Disconnect() {
+	c.Lock()
+	if c.nodeBorn {
+		if c.deviceBorn {
+			if err := c.publish(sparkplug.DDEATH, nil); err != nil {
+				log.Println("Failed to publish  ", "type: ", sparkplug.DDEATH, ", error: ", err)
+			}
+		}
+		// the will message is not sent if disconnected normally.
+		if data, err := c.session.deathPayload(); err == nil {
+			if err := c.raw.Publish(c.message(sparkplug.NDEATH, data)); err != nil {
+				log.Println("Failed to publish  ", "type: ", sparkplug.NDEATH, ", error: ", err)
+			}
+		}
+	}
+	c.nodeBorn, c.deviceBorn = false, false
+	c.Unlock()
+
+	c.raw.Disconnect()
+}
+
+func (c *sparkplugClient) RawClient() mqtt.Client {
+	return c.raw.RawClient()
+}
+
+func (c *sparkplugClient) Status() api.MQTTConnectionStatus {
+	return c.raw.Status()
+}
+
+// Subscribe ignores the topics, and receives the DCMD metrics in the handler,
+// the payload of SubscribeMessage is a JSON object keyed by the names of metrics.
+func (c *sparkplugClient) Subscribe(_ []SubscribeTopic, handler SubscribeHandler) error {
+	c.Lock()
+	defer c.Unlock()
+
+	c.handler = handler
+	return nil
+}
+
+// Publish converts the payload to metrics, and publishes DBIRTH if the metrics have not been born,
+// otherwise publishes DDATA with the changed metrics.
+func (c *sparkplugClient) Publish(message PublishMessage) error {
+	if message.Payload == nil {
+		return nil
+	}
+	var metrics, err = toSparkplugMetrics(message.Payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert payload to metrics")
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.metrics = metrics
+	if !c.nodeBorn {
+		// publishes in the births
+		return nil
+	}
+	if !c.deviceBorn || !sameSparkplugDefinitions(c.births, metrics) {
+		if c.deviceBorn {
+			if err := c.publish(sparkplug.DDEATH, nil); err != nil {
+				return err
+			}
+			c.deviceBorn = false
+		}
+		return c.birthDevice()
+	}
+
+	var aliases = make(map[string]uint64, len(c.births))
+	for _, m := range c.births {
+		aliases[m.Name] = *m.Alias
+	}
+	var changes []sparkplug.Metric
+	for _, m := range metrics {
+		if last, exist := c.published[m.Name]; exist && reflect.DeepEqual(last, m.Value) {
+			continue
+		}
+		var alias = aliases[m.Name]
+		changes = append(changes, sparkplug.Metric{
+			Alias:     &alias,
+			Timestamp: m.Timestamp,
+			DataType:  m.DataType,
+			IsNull:    m.IsNull,
+			Value:     m.Value,
+		})
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := c.publish(sparkplug.DDATA, changes); err != nil {
+		return err
+	}
+	for _, m := range metrics {
+		c.published[m.Name] = m.Value
+	}
+	return nil
+}
+
+// onConnect subscribes the commands and publishes the births.
+func (c *sparkplugClient) onConnect() {
+	c.Lock()
+	defer c.Unlock()
+
+	var topics = []SubscribeTopic{
+		{Index: 0, Render: map[string]string{"type": sparkplug.NCMD}, QoSPointer: &[]byte{1}[0]},
+		{Index: 1, Render: map[string]string{"type": sparkplug.DCMD, "device": c.session.deviceID}, QoSPointer: &[]byte{1}[0]},
+	}
+	if err := c.raw.Subscribe(topics, c.onCommand); err != nil {
+		log.Println("Failed to subscribe commands  ", "error: ", err)
+	}
+	if err := c.birth(); err != nil {
+		log.Println("Failed to publish births  ", "error: ", err)
+	}
+}
+
+// onConnectionLost resets the births.
+func (c *sparkplugClient) onConnectionLost() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.nodeBorn, c.deviceBorn = false, false
+}
+
+// onCommand handles NCMD and DCMD.
+func (c *sparkplugClient) onCommand(msg SubscribeMessage) {
+	var payload, err = sparkplug.Unmarshal(msg.Payload)
+	if err != nil {
+		log.Println("Failed to receive command  ", "topic: ", msg.Topic, ", error: ", err)
+		return
+	}
+
+	// NCMD
+	if msg.Index == 0 {
+		for _, m := range payload.Metrics {
+			if m.Name == sparkplug.NodeControlRebirth && m.Value == true {
+				// publishes in another goroutine to avoid blocking the receiving.
+				go func() {
+					c.Lock()
+					defer c.Unlock()
+
+					if !c.nodeBorn {
+						return
+					}
+					if err := c.birth(); err != nil {
+						log.Println("Failed to publish births  ", "error: ", err)
+					}
+				}()
+			}
+		}
+		return
+	}
+
+	// DCMD
+	c.Lock()
+	var handler = c.handler
+	var names = make(map[uint64]string, len(c.births))
+	for _, m := range c.births {
+		names[*m.Alias] = m.Name
+	}
+	c.Unlock()
+	if handler == nil {
+		return
+	}
+
+	var values = make(map[string]interface{}, len(payload.Metrics))
+	for _, m := range payload.Metrics {
+		var name = m.Name
+		if name == "" && m.Alias != nil {
+			name = names[*m.Alias]
+		}
+		if name == "" {
+			continue
+		}
+		values[name] = m.Value
+	}
+	if len(values) == 0 {
+		return
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		log.Println("Failed to receive command  ", "topic: ", msg.Topic, ", error: ", err)
+		return
+	}
+	log.Println("Receive Command  ", "topic: ", msg.Topic)
+	handler(SubscribeMessage{
+		Topic:   msg.Topic,
+		Payload: data,
+	})
+}
+
+// birth publishes NBIRTH, and publishes DBIRTH if the metrics of device are known.
+func (c *sparkplugClient) birth() error {
+	c.seq = 0
+	c.nodeBorn, c.deviceBorn = false, false
+	var err = c.publish(sparkplug.NBIRTH, []sparkplug.Metric{
+		{Name: sparkplug.BDSeq, DataType: sparkplug.Int64, Value: int64(c.session.currentBDSeq())},
+		{Name: sparkplug.NodeControlRebirth, DataType: sparkplug.Boolean, Value: false},
+	})
+	if err != nil {
+		return err
+	}
+	c.nodeBorn = true
+
+	if c.metrics == nil {
+		return nil
+	}
+	return c.birthDevice()
+}
+
+// birthDevice publishes DBIRTH with all metrics, and assigns the aliases of metrics.
+func (c *sparkplugClient) birthDevice() error {
+	var births = make([]sparkplug.Metric, 0, len(c.metrics))
+	for i, m := range c.metrics {
+		var alias = uint64(i + 1)
+		m.Alias = &alias
+		births = append(births, m)
+	}
+	if err := c.publish(sparkplug.DBIRTH, births); err != nil {
+		return err
+	}
+	c.deviceBorn = true
+	c.births = births
+	c.published = make(map[string]interface{}, len(births))
+	for _, m := range births {
+		c.published[m.Name] = m.Value
+	}
+	return nil
+}
+
+// publish publishes the metrics with the sequence number.
+func (c *sparkplugClient) publish(messageType string, metrics []sparkplug.Metric) error {
+	var seq = c.seq
+	var payload = &sparkplug.Payload{
+		Timestamp: nowMilliseconds(),
+		Metrics:   metrics,
+		Seq:       &seq,
+	}
+	var data, err = payload.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := c.raw.Publish(c.message(messageType, data)); err != nil {
+		return err
+	}
+	c.seq = (c.seq + 1) % 256
+	return nil
+}
+
+func (c *sparkplugClient) message(messageType string, data []byte) PublishMessage {
+	var render = map[string]string{"type": messageType}
+	if messageType[0] == 'D' {
+		render["device"] = c.session.deviceID
+	}
+	var qos, retained = byte(0), false
+	if messageType == sparkplug.NDEATH {
+		qos = 1
+	}
+	return PublishMessage{
+		Render:          render,
+		QoSPointer:      &qos,
+		RetainedPointer: &retained,
+		Payload:         data,
+	}
+}
+
+// sameSparkplugDefinitions returns true if the given metrics have the same names and data types as the births.
+func sameSparkplugDefinitions(births, metrics []sparkplug.Metric) bool {
+	if len(births) != len(metrics) {
+		return false
+	}
+	for i := range births {
+		if births[i].Name != metrics[i].Name || births[i].DataType != metrics[i].DataType {
+			return false
+		}
+	}
+	return true
+}
+
+// toSparkplugMetrics converts the payload to metrics,
+// the items of `properties` list are converted by their `name`, `type` and `value`,
+// otherwise the fields of payload are flattened as the metric names joined by "/".
+func toSparkplugMetrics(payload interface{}) ([]sparkplug.Metric, error) {
+	var data, err = json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var decoder = json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	var timestamp = nowMilliseconds()
+	var metrics = make([]sparkplug.Metric, 0)
+	var obj, isObj = generic.(map[string]interface{})
+	if !isObj {
+		return append(metrics, toSparkplugMetric("value", "", generic, timestamp)), nil
+	}
+	if props, ok := obj["properties"].([]interface{}); ok {
+		for _, item := range props {
+			var prop, ok = item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var name, _ = prop["name"].(string)
+			if name == "" {
+				continue
+			}
+			var typeName, _ = prop["type"].(string)
+			metrics = append(metrics, toSparkplugMetric(name, typeName, prop["value"], timestamp))
+		}
+		return metrics, nil
+	}
+
+	var flatten func(prefix string, obj map[string]interface{})
+	flatten = func(prefix string, obj map[string]interface{}) {
+		var keys = make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			var name = k
+			if prefix != "" {
+				name = prefix + "/" + k
+			}
+			if nested, ok := obj[k].(map[string]interface{}); ok {
+				flatten(name, nested)
+				continue
+			}
+			metrics = append(metrics, toSparkplugMetric(name, "", obj[k], timestamp))
+		}
+	}
+	flatten("", obj)
+	return metrics, nil
+}
+
+func toSparkplugMetric(name, typeName string, value interface{}, timestamp uint64) sparkplug.Metric {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			value = i
+		} else {
+			value, _ = v.Float64()
+		}
+	case []interface{}:
+		var data, _ = json.Marshal(v)
+		value = string(data)
+	}
+
+	var dataType = sparkplug.ParseDataType(typeName)
+	if dataType == sparkplug.Unknown {
+		dataType = sparkplug.InferDataType(value)
+	}
+	var metric = sparkplug.Metric{
+		Name:      name,
+		Timestamp: timestamp,
+		DataType:  dataType,
+	}
+	if s, ok := value.(string); value == nil || (ok && s == "" && dataType != sparkplug.String) {
+		metric.IsNull = true
+		return metric
+	}
+	var converted, err = sparkplug.ConvertValue(dataType, value)
+	if err != nil {
+		metric.IsNull = true
+		return metric
+	}
+	metric.Value = converted
+	return metric
+}
+
+func nowMilliseconds() uint64 {
+	return uint64(time.Now().UnixNano() / int64(time.Millisecond))
+}
@@ -0,0 +1,143 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"testing"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rancher/octopus/pkg/mqtt/api"
+	"github.com/rancher/octopus/pkg/mqtt/sparkplug"
+)
+
+type fakeRawClient struct {
+	published  []PublishMessage
+	subscribed []SubscribeTopic
+	handler    SubscribeHandler
+	// publishErr is returned after recording the published message.
+	publishErr error
+}
+
+func (c *fakeRawClient) Connect() error {
+	return nil
+}
+
+func (c *fakeRawClient) Disconnect() {}
+
+func (c *fakeRawClient) RawClient() mqtt.Client {
+	return nil
+}
+
+func (c *fakeRawClient) Status() api.MQTTConnectionStatus {
+	return api.MQTTConnectionStatus{}
+}
+
+func (c *fakeRawClient) Subscribe(topics []SubscribeTopic, handler SubscribeHandler) error {
+	c.subscribed = topics
+	c.handler = handler
+	return nil
+}
+
+func (c *fakeRawClient) Publish(message PublishMessage) error {
+	c.published = append(c.published, message)
+	return c.publishErr
+}
+
+func (c *fakeRawClient) popSparkplug(t *testing.T) (string, *sparkplug.Payload) {
+	if !assert.NotEmpty(t, c.published) {
+		t.FailNow()
+	}
+	var msg = c.published[0]
+	c.published = c.published[1:]
+	var payload, err = sparkplug.Unmarshal(msg.Payload.([]byte))
+	assert.NoError(t, err)
+	return msg.Render["type"], payload
+}
+
+func TestSparkplugClient(t *testing.T) {
+	type property struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+		Type  string `json:"type"`
+	}
+	type status struct {
+		Properties []property `json:"properties"`
+	}
+
+	var raw = &fakeRawClient{}
+	var cli = &sparkplugClient{
+		raw:     raw,
+		session: &sparkplugSession{groupID: "group", edgeNodeID: "node", deviceID: "device", bdSeq: 2},
+	}
+
+	// publishes before connected
+	assert.NoError(t, cli.Publish(PublishMessage{Payload: status{Properties: []property{
+		{Name: "temperature", Value: "23.5", Type: "float"},
+		{Name: "switch", Value: "true", Type: "boolean"},
+	}}}))
+	assert.Empty(t, raw.published)
+
+	// births after connected
+	cli.onConnect()
+	var messageType, payload = raw.popSparkplug(t)
+	assert.Equal(t, sparkplug.NBIRTH, messageType)
+	assert.Equal(t, uint64(0), *payload.Seq)
+	assert.Equal(t, int64(2), payload.Metrics[0].Value)
+	messageType, payload = raw.popSparkplug(t)
+	assert.Equal(t, sparkplug.DBIRTH, messageType)
+	assert.Equal(t, uint64(1), *payload.Seq)
+	assert.Len(t, payload.Metrics, 2)
+	assert.Equal(t, "temperature", payload.Metrics[0].Name)
+	assert.Equal(t, uint64(1), *payload.Metrics[0].Alias)
+	assert.Equal(t, float32(23.5), payload.Metrics[0].Value)
+	assert.Equal(t, true, payload.Metrics[1].Value)
+
+	// publishes the changed metrics only
+	assert.NoError(t, cli.Publish(PublishMessage{Payload: status{Properties: []property{
+		{Name: "temperature", Value: "24", Type: "float"},
+		{Name: "switch", Value: "true", Type: "boolean"},
+	}}}))
+	messageType, payload = raw.popSparkplug(t)
+	assert.Equal(t, sparkplug.DDATA, messageType)
+	assert.Equal(t, uint64(2), *payload.Seq)
+	assert.Len(t, payload.Metrics, 1)
+	assert.Equal(t, "", payload.Metrics[0].Name)
+	assert.Equal(t, uint64(1), *payload.Metrics[0].Alias)
+	assert.Equal(t, float32(24), payload.Metrics[0].Value)
+
+	// rebirths the device if the definitions changed
+	assert.NoError(t, cli.Publish(PublishMessage{Payload: status{Properties: []property{
+		{Name: "temperature", Value: "24", Type: "float"},
+	}}}))
+	messageType, _ = raw.popSparkplug(t)
+	assert.Equal(t, sparkplug.DDEATH, messageType)
+	messageType, payload = raw.popSparkplug(t)
+	assert.Equal(t, sparkplug.DBIRTH, messageType)
+	assert.Equal(t, uint64(4), *payload.Seq)
+
+	// receives DCMD with alias
+	var received = make(chan map[string]interface{}, 1)
+	assert.NoError(t, cli.Subscribe(nil, func(msg SubscribeMessage) {
+		var values map[string]interface{}
+		assert.NoError(t, json.Unmarshal(msg.Payload, &values))
+		received <- values
+	}))
+	var alias uint64 = 1
+	var command = &sparkplug.Payload{Metrics: []sparkplug.Metric{
+		{Alias: &alias, DataType: sparkplug.Float, Value: float32(30)},
+	}}
+	var data, err = command.Marshal()
+	assert.NoError(t, err)
+	raw.handler(SubscribeMessage{Index: 1, Topic: "spBv1.0/group/DCMD/node/device", Payload: data})
+	assert.Equal(t, map[string]interface{}{"temperature": float64(30)}, <-received)
+
+	// dies after disconnected
+	cli.Disconnect()
+	messageType, _ = raw.popSparkplug(t)
+	assert.Equal(t, sparkplug.DDEATH, messageType)
+	messageType, payload = raw.popSparkplug(t)
+	assert.Equal(t, sparkplug.NDEATH, messageType)
+	assert.Nil(t, payload.Seq)
+	assert.Equal(t, int64(2), payload.Metrics[0].Value)
+}
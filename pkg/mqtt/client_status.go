@@ -0,0 +1,83 @@
+package mqtt
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/octopus/pkg/mqtt/api"
+)
+
+// connectionTracker tracks the connection status of client via the connection handlers.
+type connectionTracker struct {
+	sync.Mutex
+
+	status api.MQTTConnectionStatus
+	// everConnected indicates the connection has been established once,
+	// so that the following connections are counted as reconnection.
+	everConnected bool
+	// server returns the server URI of the connected broker.
+	server func() string
+	// changed is invoked after the status changed by the connection handlers.
+	changed func()
+}
+
+// Status returns a copy of the connection status.
+func (t *connectionTracker) Status() api.MQTTConnectionStatus {
+	t.Lock()
+	defer t.Unlock()
+
+	return *t.status.DeepCopy()
+}
+
+func (t *connectionTracker) onConnect() {
+	t.Lock()
+	if t.everConnected {
+		t.status.ReconnectCount++
+	}
+	t.everConnected = true
+	t.status.Connected = true
+	if t.server != nil {
+		t.status.Server = t.server()
+	}
+	t.transit()
+	t.Unlock()
+
+	t.notify()
+}
+
+func (t *connectionTracker) onConnectionLost(err error) {
+	t.Lock()
+	t.status.Connected = false
+	t.status.Server = ""
+	if err != nil {
+		t.status.LastError = err.Error()
+	}
+	t.transit()
+	t.Unlock()
+
+	t.notify()
+}
+
+func (t *connectionTracker) onConnectFailed(err error) {
+	if err == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.status.LastError = err.Error()
+}
+
+func (t *connectionTracker) transit() {
+	var now = metav1.Now()
+	t.status.LastTransitionTime = &now
+}
+
+// notify pushes the changed status out of the lock, so that the handler can get the status.
+func (t *connectionTracker) notify() {
+	if t.changed != nil {
+		t.changed()
+	}
+}
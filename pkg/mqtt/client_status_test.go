@@ -0,0 +1,58 @@
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rancher/octopus/pkg/mqtt/api"
+)
+
+func TestConnectionTracker(t *testing.T) {
+	var server = "tcp://primary:1883"
+	var pushed []bool
+	var tracker *connectionTracker
+	tracker = &connectionTracker{
+		server: func() string {
+			return server
+		},
+		changed: func() {
+			pushed = append(pushed, tracker.Status().Connected)
+		},
+	}
+	var status = func() api.MQTTConnectionStatus {
+		var ret = tracker.Status()
+		assert.NotNil(t, ret.LastTransitionTime)
+		ret.LastTransitionTime = nil
+		return ret
+	}
+
+	tracker.onConnectFailed(errors.New("connection refused"))
+	assert.Equal(t, api.MQTTConnectionStatus{LastError: "connection refused"}, tracker.Status())
+
+	tracker.onConnect()
+	assert.Equal(t, api.MQTTConnectionStatus{
+		Connected: true,
+		Server:    "tcp://primary:1883",
+		LastError: "connection refused",
+	}, status())
+
+	tracker.onConnectionLost(errors.New("EOF"))
+	assert.Equal(t, api.MQTTConnectionStatus{
+		LastError: "EOF",
+	}, status())
+
+	// fails over to the backup server
+	server = "tcp://backup:1883"
+	tracker.onConnect()
+	assert.Equal(t, api.MQTTConnectionStatus{
+		Connected:      true,
+		Server:         "tcp://backup:1883",
+		LastError:      "EOF",
+		ReconnectCount: 1,
+	}, status())
+
+	// pushes the changes of connection handlers, except the failure of connecting
+	assert.Equal(t, []bool{true, false, true}, pushed)
+}
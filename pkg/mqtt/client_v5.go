@@ -0,0 +1,307 @@
+package mqtt
+
+import (
+	"context"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/octopus/pkg/mqtt/api"
+	"github.com/rancher/octopus/pkg/mqtt/buffer"
+	"github.com/rancher/octopus/pkg/mqtt/codec"
+	v5 "github.com/rancher/octopus/pkg/mqtt/v5"
+)
+
+// clientV5 is the MQTT v5 implementation of Client.
+type clientV5 struct {
+	raw               *v5.Client
+	rawV3             mqtt.Client
+	disconnectQuiesce time.Duration
+	waitDuration      time.Duration
+	topic             SegmentTopic
+	qos               byte
+	retained          bool
+	properties        *api.MQTTMessageProperties
+	sharedGroup       string
+	codec             codec.Codec
+	template          *payloadTemplate
+	outbox            *outbox
+	tracker           *connectionTracker
+
+	subscribeTopicIndexer SubscribeTopicIndex
+}
+
+func (c *clientV5) context() (context.Context, context.CancelFunc) {
+	if c.waitDuration == 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), c.waitDuration)
+}
+
+func (c *clientV5) Connect() error {
+	var err = c.raw.Connect()
+	c.tracker.onConnectFailed(err)
+	return err
+}
+
+func (c *clientV5) Disconnect() {
+	var disconnectQuiesce = c.disconnectQuiesce
+	if disconnectQuiesce == 0 {
+		disconnectQuiesce = 5 * time.Second
+	}
+	c.raw.Disconnect(disconnectQuiesce)
+	if c.outbox != nil {
+		c.outbox.Close()
+	}
+}
+
+// RawClient returns the MQTT v5 client in the interface of paho MQTT v3 client,
+// the message properties are not available through it.
+func (c *clientV5) RawClient() mqtt.Client {
+	return c.rawV3
+}
+
+func (c *clientV5) Status() api.MQTTConnectionStatus {
+	return c.tracker.Status()
+}
+
+func (c *clientV5) Subscribe(topics []SubscribeTopic, handler SubscribeHandler) error {
+	if len(topics) == 0 {
+		return nil
+	}
+
+	var ctx, cancel = c.context()
+	defer cancel()
+
+	// indexes new topics
+	var topicIndexer = make(SubscribeTopicIndex, len(topics))
+	for _, topic := range topics {
+		var topicName = c.topic.RenderForSubscribe(topic.Render)
+		topicIndexer.Index(topicName, &topic)
+	}
+
+	// unsubscribes topics
+	var unsubscribeTopics = c.subscribeTopicIndexer.DifferenceIndexes(topicIndexer)
+	if len(unsubscribeTopics) != 0 {
+		log.Println("Unsubscribe  ", "topics: ", unsubscribeTopics)
+		for i := range unsubscribeTopics {
+			unsubscribeTopics[i] = v5.SharedTopic(c.sharedGroup, unsubscribeTopics[i])
+		}
+		if err := c.raw.Unsubscribe(ctx, unsubscribeTopics...); err != nil {
+			return err
+		}
+	}
+
+	// subscribes new topics
+	c.raw.SetMessageHandler(func(msg *paho.Publish) {
+		if handler == nil {
+			return
+		}
+		var topicName = msg.Topic
+		var topic, exist = topicIndexer.Match(topicName)
+		if !exist {
+			return
+		}
+
+		log.Println("Receive Subscribe  ", "topic: ", topicName)
+
+		handler(SubscribeMessage{
+			Index:      topic.Index,
+			Topic:      topicName,
+			Payload:    msg.Payload,
+			Properties: fromV5Properties(msg.Properties),
+			codec:      c.codec,
+		})
+	})
+	var subscriptions = make(map[string]paho.SubscribeOptions, len(topicIndexer))
+	for topicName, topic := range topicIndexer {
+		var qos = c.qos
+		if topic.QoSPointer != nil {
+			qos = *topic.QoSPointer
+		}
+		subscriptions[v5.SharedTopic(c.sharedGroup, topicName)] = paho.SubscribeOptions{QoS: qos}
+	}
+	if err := c.raw.Subscribe(ctx, subscriptions); err != nil {
+		return err
+	}
+	log.Println("Subscribe  ", "topics: ", subscriptions)
+
+	c.subscribeTopicIndexer = topicIndexer
+	return nil
+}
+
+func (c *clientV5) Publish(message PublishMessage) error {
+	if message.Payload == nil {
+		return nil
+	}
+	var rendered, err = renderPayload(c.template, message)
+	if err != nil {
+		return err
+	}
+	payload, encoded, err := encodePayload(c.codec, rendered)
+	if err != nil {
+		return err
+	}
+	var qos = c.qos
+	if message.QoSPointer != nil {
+		qos = *message.QoSPointer
+	}
+	var retained = c.retained
+	if message.RetainedPointer != nil {
+		retained = *message.RetainedPointer
+	}
+	var properties = toV5Properties(c.properties, message.Properties)
+	// indicates the content type of the encoded payload if not specified.
+	if encoded && c.codec != nil {
+		if properties == nil {
+			properties = &paho.PublishProperties{}
+		}
+		if properties.ContentType == "" {
+			properties.ContentType = c.codec.ContentType()
+		}
+	}
+	var topicName = c.topic.RenderForPublish(message.Render)
+	var msg = &paho.Publish{
+		Topic:      topicName,
+		QoS:        qos,
+		Retain:     retained,
+		Properties: properties,
+		Payload:    payload,
+	}
+	if c.outbox != nil {
+		var bufferedMsg = buffer.Message{
+			Topic:      topicName,
+			QoS:        qos,
+			Retained:   retained,
+			Properties: toBufferedProperties(properties),
+			Payload:    payload,
+		}
+		return c.outbox.Publish(bufferedMsg, func() error {
+			return c.publish(msg)
+		})
+	}
+	return c.publish(msg)
+}
+
+// publishBuffered publishes the buffered message with the properties at the time of buffering,
+// the message expiry is reduced by the buffered time, and the expired message is dropped.
+func (c *clientV5) publishBuffered(msg buffer.Message) error {
+	var properties, expired = fromBufferedProperties(msg.Properties, time.Since(msg.Timestamp))
+	if expired {
+		log.Println("Drop expired  ", "topic: ", msg.Topic)
+		return nil
+	}
+	return c.publish(&paho.Publish{
+		Topic:      msg.Topic,
+		QoS:        msg.QoS,
+		Retain:     msg.Retained,
+		Properties: properties,
+		Payload:    msg.Payload,
+	})
+}
+
+func (c *clientV5) publish(msg *paho.Publish) error {
+	log.Println("Publish  ", "topic: ", msg.Topic, ", qos: ", msg.QoS, ", retained: ", msg.Retain)
+
+	var ctx, cancel = c.context()
+	defer cancel()
+	return c.raw.Publish(ctx, msg)
+}
+
+// toV5Properties merges the properties of message into the global properties,
+// the non-blank fields of message override the global ones, and the user properties are appended.
+func toV5Properties(global, message *api.MQTTMessageProperties) *paho.PublishProperties {
+	if global == nil && message == nil {
+		return nil
+	}
+
+	var ret = &paho.PublishProperties{}
+	for _, p := range []*api.MQTTMessageProperties{global, message} {
+		if p == nil {
+			continue
+		}
+		if p.ContentType != "" {
+			ret.ContentType = p.ContentType
+		}
+		if p.ResponseTopic != "" {
+			ret.ResponseTopic = p.ResponseTopic
+		}
+		if p.CorrelationData != "" {
+			ret.CorrelationData = []byte(p.CorrelationData)
+		}
+		if p.MessageExpiryInterval != nil {
+			var expiry = uint32(p.MessageExpiryInterval.Duration / time.Second)
+			ret.MessageExpiry = &expiry
+		}
+		for _, up := range p.UserProperties {
+			ret.User = append(ret.User, paho.UserProperty{Key: up.Name, Value: up.Value})
+		}
+	}
+	return ret
+}
+
+// toBufferedProperties converts the properties of publishing message for buffering.
+func toBufferedProperties(p *paho.PublishProperties) *buffer.Properties {
+	if p == nil {
+		return nil
+	}
+
+	var ret = &buffer.Properties{
+		ContentType:     p.ContentType,
+		ResponseTopic:   p.ResponseTopic,
+		CorrelationData: p.CorrelationData,
+		MessageExpiry:   p.MessageExpiry,
+	}
+	for _, up := range p.User {
+		ret.UserProperties = append(ret.UserProperties, buffer.UserProperty{Key: up.Key, Value: up.Value})
+	}
+	return ret
+}
+
+// fromBufferedProperties converts the properties of buffered message for publishing,
+// returns true if the message has expired after buffering for the given duration.
+func fromBufferedProperties(p *buffer.Properties, buffered time.Duration) (*paho.PublishProperties, bool) {
+	if p == nil {
+		return nil, false
+	}
+
+	var ret = &paho.PublishProperties{
+		ContentType:     p.ContentType,
+		ResponseTopic:   p.ResponseTopic,
+		CorrelationData: p.CorrelationData,
+	}
+	if p.MessageExpiry != nil {
+		var elapsed = uint32(buffered / time.Second)
+		if elapsed >= *p.MessageExpiry {
+			return nil, true
+		}
+		var remaining = *p.MessageExpiry - elapsed
+		ret.MessageExpiry = &remaining
+	}
+	for _, up := range p.UserProperties {
+		ret.User = append(ret.User, paho.UserProperty{Key: up.Key, Value: up.Value})
+	}
+	return ret, false
+}
+
+// fromV5Properties converts the properties of received message.
+func fromV5Properties(p *paho.PublishProperties) *api.MQTTMessageProperties {
+	if p == nil {
+		return nil
+	}
+
+	var ret = &api.MQTTMessageProperties{
+		ContentType:     p.ContentType,
+		ResponseTopic:   p.ResponseTopic,
+		CorrelationData: string(p.CorrelationData),
+	}
+	if p.MessageExpiry != nil {
+		ret.MessageExpiryInterval = &metav1.Duration{Duration: time.Duration(*p.MessageExpiry) * time.Second}
+	}
+	for _, up := range p.User {
+		ret.UserProperties = append(ret.UserProperties, api.MQTTMessageUserProperty{Name: up.Key, Value: up.Value})
+	}
+	return ret
+}
@@ -0,0 +1,177 @@
+package mqtt
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+
+	v5 "github.com/rancher/octopus/pkg/mqtt/v5"
+)
+
+// rawClientV5 adapts the MQTT v5 client to the interface of paho MQTT v3 client,
+// so that the customized connection handlers and the callers of RawClient work in MQTT v5.
+type rawClientV5 struct {
+	raw          *v5.Client
+	options      mqtt.ClientOptionsReader
+	waitDuration time.Duration
+}
+
+func (c *rawClientV5) context() (context.Context, context.CancelFunc) {
+	if c.waitDuration == 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), c.waitDuration)
+}
+
+func (c *rawClientV5) IsConnected() bool {
+	return c.raw.IsConnected()
+}
+
+func (c *rawClientV5) IsConnectionOpen() bool {
+	return c.raw.IsConnected()
+}
+
+func (c *rawClientV5) Connect() mqtt.Token {
+	return newTokenV5(c.raw.Connect)
+}
+
+func (c *rawClientV5) Disconnect(quiesce uint) {
+	c.raw.Disconnect(time.Duration(quiesce) * time.Millisecond)
+}
+
+func (c *rawClientV5) Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+	var msg = &paho.Publish{
+		Topic:  topic,
+		QoS:    qos,
+		Retain: retained,
+	}
+	switch p := payload.(type) {
+	case string:
+		msg.Payload = []byte(p)
+	case []byte:
+		msg.Payload = p
+	case bytes.Buffer:
+		msg.Payload = p.Bytes()
+	default:
+		return newTokenV5(func() error {
+			return errors.Errorf("unknown payload type %T", payload)
+		})
+	}
+	return newTokenV5(func() error {
+		var ctx, cancel = c.context()
+		defer cancel()
+		return c.raw.Publish(ctx, msg)
+	})
+}
+
+func (c *rawClientV5) Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token {
+	return c.SubscribeMultiple(map[string]byte{topic: qos}, callback)
+}
+
+func (c *rawClientV5) SubscribeMultiple(filters map[string]byte, callback mqtt.MessageHandler) mqtt.Token {
+	var subscriptions = make(map[string]paho.SubscribeOptions, len(filters))
+	for filter, qos := range filters {
+		subscriptions[filter] = paho.SubscribeOptions{QoS: qos}
+		if callback != nil {
+			c.AddRoute(filter, callback)
+		}
+	}
+	return newTokenV5(func() error {
+		var ctx, cancel = c.context()
+		defer cancel()
+		return c.raw.Subscribe(ctx, subscriptions)
+	})
+}
+
+func (c *rawClientV5) Unsubscribe(topics ...string) mqtt.Token {
+	c.raw.RemoveRoute(topics...)
+	return newTokenV5(func() error {
+		var ctx, cancel = c.context()
+		defer cancel()
+		return c.raw.Unsubscribe(ctx, topics...)
+	})
+}
+
+func (c *rawClientV5) AddRoute(topic string, callback mqtt.MessageHandler) {
+	c.raw.AddRoute(topic, func(msg *paho.Publish) {
+		callback(c, messageV5{msg})
+	})
+}
+
+func (c *rawClientV5) OptionsReader() mqtt.ClientOptionsReader {
+	return c.options
+}
+
+// messageV5 adapts the received MQTT v5 message to the interface of paho MQTT v3 message.
+type messageV5 struct {
+	*paho.Publish
+}
+
+func (m messageV5) Duplicate() bool {
+	return false
+}
+
+func (m messageV5) Qos() byte {
+	return m.QoS
+}
+
+func (m messageV5) Retained() bool {
+	return m.Retain
+}
+
+func (m messageV5) Topic() string {
+	return m.Publish.Topic
+}
+
+func (m messageV5) MessageID() uint16 {
+	return m.PacketID
+}
+
+func (m messageV5) Payload() []byte {
+	return m.Publish.Payload
+}
+
+// Ack does nothing as paho acknowledges the message after handled.
+func (m messageV5) Ack() {}
+
+// tokenV5 is completed after the action is done.
+type tokenV5 struct {
+	done chan struct{}
+	err  error
+}
+
+func newTokenV5(action func() error) *tokenV5 {
+	var t = &tokenV5{done: make(chan struct{})}
+	go func() {
+		t.err = action()
+		close(t.done)
+	}()
+	return t
+}
+
+func (t *tokenV5) Wait() bool {
+	<-t.done
+	return true
+}
+
+func (t *tokenV5) WaitTimeout(d time.Duration) bool {
+	select {
+	case <-t.done:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+func (t *tokenV5) Error() error {
+	select {
+	case <-t.done:
+		return t.err
+	default:
+		return nil
+	}
+}
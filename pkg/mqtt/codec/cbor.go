@@ -0,0 +1,73 @@
+package codec
+
+import (
+	"bytes"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/pkg/errors"
+)
+
+// CBOR is the codec of Concise Binary Object Representation, refer to https://tools.ietf.org/html/rfc8949.
+var CBOR Codec = cborCodec{
+	encMode: mustCBOREncMode(cbor.EncOptions{
+		Sort:          cbor.SortCanonical,
+		ShortestFloat: cbor.ShortestFloat16,
+	}),
+	decMode: mustCBORDecMode(cbor.DecOptions{
+		MaxNestedLevels: maxNestingDepth,
+		DefaultMapType:  reflect.TypeOf(map[string]interface{}(nil)),
+	}),
+}
+
+// maxNestingDepth limits the depth of nested arrays and maps for decoding.
+const maxNestingDepth = 64
+
+type cborCodec struct {
+	encMode cbor.EncMode
+	decMode cbor.DecMode
+}
+
+func (cborCodec) ContentType() string {
+	return "application/cbor"
+}
+
+func (c cborCodec) Encode(v interface{}) ([]byte, error) {
+	var g, err = toGeneric(v)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.encMode.Marshal(g)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode CBOR")
+	}
+	return data, nil
+}
+
+func (c cborCodec) Decode(data []byte, v interface{}) error {
+	var g interface{}
+	var decoder = c.decMode.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(&g); err != nil {
+		return errors.Wrap(err, "failed to decode CBOR")
+	}
+	if n := decoder.NumBytesRead(); n != len(data) {
+		return errors.Errorf("unexpected %d trailing bytes of CBOR", len(data)-n)
+	}
+	return fromGeneric(g, v)
+}
+
+func mustCBOREncMode(opts cbor.EncOptions) cbor.EncMode {
+	var mode, err = opts.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}
+
+func mustCBORDecMode(opts cbor.DecOptions) cbor.DecMode {
+	var mode, err = opts.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}
@@ -0,0 +1,103 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/rancher/octopus/pkg/util/converter"
+)
+
+// Codec encodes the payload for publishing and decodes the payload from subscribing.
+type Codec interface {
+	// ContentType returns the MIME type of encoded payload.
+	ContentType() string
+
+	// Encode encodes the value,
+	// the value is converted in the same way as JSON, so the JSON tags and marshalers work as usual.
+	Encode(v interface{}) ([]byte, error)
+
+	// Decode decodes the data into the value,
+	// the value is converted in the same way as JSON, so the JSON tags and unmarshalers work as usual.
+	Decode(data []byte, v interface{}) error
+}
+
+// ToJSON decodes the data with the codec and encodes it as JSON.
+func ToJSON(c Codec, data []byte) ([]byte, error) {
+	if c == nil || c == JSON {
+		return data, nil
+	}
+	var v interface{}
+	if err := c.Decode(data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// JSON is the default codec.
+var JSON Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string {
+	return "application/json"
+}
+
+func (jsonCodec) Encode(v interface{}) ([]byte, error) {
+	return converter.MarshalJSON(v)
+}
+
+func (jsonCodec) Decode(data []byte, v interface{}) error {
+	return converter.UnmarshalJSON(data, v)
+}
+
+// toGeneric converts the value into the generic value via JSON,
+// the result is one of nil, bool, int64, float64, string, []interface{} and map[string]interface{}.
+func toGeneric(v interface{}) (interface{}, error) {
+	var data, err = json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal value")
+	}
+	var decoder = json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var ret interface{}
+	if err := decoder.Decode(&ret); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal value")
+	}
+	return normalizeNumber(ret), nil
+}
+
+// normalizeNumber converts the json.Number into int64 if possible, otherwise float64.
+func normalizeNumber(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(string(t), 10, 64); err == nil {
+			return i
+		}
+		var f, _ = strconv.ParseFloat(string(t), 64)
+		return f
+	case []interface{}:
+		for i := range t {
+			t[i] = normalizeNumber(t[i])
+		}
+	case map[string]interface{}:
+		for k := range t {
+			t[k] = normalizeNumber(t[k])
+		}
+	}
+	return v
+}
+
+// fromGeneric converts the generic value into the value via JSON.
+func fromGeneric(g interface{}, v interface{}) error {
+	var data, err = json.Marshal(g)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal value")
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.Wrap(err, "failed to unmarshal value")
+	}
+	return nil
+}
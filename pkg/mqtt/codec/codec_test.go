@@ -0,0 +1,479 @@
+package codec
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+type status struct {
+	Name        string            `json:"name"`
+	Enabled     bool              `json:"enabled"`
+	Temperature float64           `json:"temperature"`
+	Count       int64             `json:"count"`
+	Tags        []string          `json:"tags,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Location    *location         `json:"location,omitempty"`
+}
+
+type location struct {
+	Level string `json:"level"`
+	Zones []int  `json:"zones"`
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	var given = status{
+		Name:        "living-room",
+		Enabled:     true,
+		Temperature: 23.625,
+		Count:       -4096,
+		Tags:        []string{"a", "b"},
+		Labels:      map[string]string{"floor": "1"},
+		Location: &location{
+			Level: "HIGH",
+			Zones: []int{1, 300, 70000},
+		},
+	}
+
+	var testCases = []struct {
+		name  string
+		given Codec
+	}{
+		{
+			name:  "cbor",
+			given: CBOR,
+		},
+		{
+			name:  "msgpack",
+			given: MessagePack,
+		},
+		{
+			name:  "protobuf",
+			given: newTestProtobuf(t),
+		},
+	}
+
+	for _, tc := range testCases {
+		var data, err = tc.given.Encode(given)
+		assert.NoError(t, err, "case %q", tc.name)
+
+		var actual status
+		err = tc.given.Decode(data, &actual)
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, given, actual, "case %q", tc.name)
+
+		jsonData, err := ToJSON(tc.given, data)
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.JSONEq(t, `{"name":"living-room","enabled":true,"temperature":23.625,"count":-4096,"tags":["a","b"],"labels":{"floor":"1"},"location":{"level":"HIGH","zones":[1,300,70000]}}`, string(jsonData), "case %q", tc.name)
+	}
+}
+
+func TestCBOR(t *testing.T) {
+	// the examples come from https://tools.ietf.org/html/rfc8949#appendix-A
+	var testCases = []struct {
+		name     string
+		given    interface{}
+		expected []byte
+	}{
+		{
+			name:     "unsigned integer",
+			given:    1000000,
+			expected: []byte{0x1a, 0x00, 0x0f, 0x42, 0x40},
+		},
+		{
+			name:     "negative integer",
+			given:    -1000,
+			expected: []byte{0x39, 0x03, 0xe7},
+		},
+		{
+			name:     "half precision float",
+			given:    1.5,
+			expected: []byte{0xf9, 0x3e, 0x00},
+		},
+		{
+			name:     "double precision float",
+			given:    1.1,
+			expected: []byte{0xfb, 0x3f, 0xf1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9a},
+		},
+		{
+			name:     "text string",
+			given:    "IETF",
+			expected: []byte{0x64, 0x49, 0x45, 0x54, 0x46},
+		},
+		{
+			name:     "nested array",
+			given:    []interface{}{1, []interface{}{2, 3}, []interface{}{4, 5}},
+			expected: []byte{0x83, 0x01, 0x82, 0x02, 0x03, 0x82, 0x04, 0x05},
+		},
+		{
+			name:     "map",
+			given:    map[string]interface{}{"b": []interface{}{2, 3}, "a": 1},
+			expected: []byte{0xa2, 0x61, 0x61, 0x01, 0x61, 0x62, 0x82, 0x02, 0x03},
+		},
+		{
+			name:     "simple values",
+			given:    []interface{}{false, true, nil},
+			expected: []byte{0x83, 0xf4, 0xf5, 0xf6},
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual, err = CBOR.Encode(tc.given)
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+
+		// decodes the reference bytes back to the same value
+		var expected, decoded interface{}
+		_ = fromGeneric(tc.given, &expected)
+		err = CBOR.Decode(tc.expected, &decoded)
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, expected, decoded, "case %q", tc.name)
+	}
+}
+
+func TestCBOR_Decode(t *testing.T) {
+	// the examples come from https://tools.ietf.org/html/rfc8949#appendix-A
+	var testCases = []struct {
+		name     string
+		given    []byte
+		expected interface{}
+		err      bool
+	}{
+		{
+			name:     "half precision float",
+			given:    []byte{0xf9, 0x3c, 0x00},
+			expected: float64(1),
+		},
+		{
+			name:     "indefinite array",
+			given:    []byte{0x9f, 0x01, 0x82, 0x02, 0x03, 0x9f, 0x04, 0x05, 0xff, 0xff},
+			expected: []interface{}{float64(1), []interface{}{float64(2), float64(3)}, []interface{}{float64(4), float64(5)}},
+		},
+		{
+			name:     "indefinite text string",
+			given:    []byte{0x7f, 0x65, 0x73, 0x74, 0x72, 0x65, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x67, 0xff},
+			expected: "streaming",
+		},
+		{
+			name:     "epoch-based date/time",
+			given:    []byte{0xc1, 0x1a, 0x51, 0x4b, 0x67, 0xb0},
+			expected: "2013-03-21T20:04:00Z",
+		},
+		{
+			name:  "truncated",
+			given: []byte{0x62, 0x61},
+			err:   true,
+		},
+		{
+			name:  "trailing bytes",
+			given: []byte{0x01, 0x01},
+			err:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual interface{}
+		var err = CBOR.Decode(tc.given, &actual)
+		if tc.err {
+			assert.Error(t, err, "case %q", tc.name)
+			continue
+		}
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
+func TestMessagePack(t *testing.T) {
+	// the examples follow the formats of https://github.com/msgpack/msgpack/blob/master/spec.md
+	var testCases = []struct {
+		name     string
+		given    interface{}
+		expected []byte
+	}{
+		{
+			name:     "positive fixint",
+			given:    1,
+			expected: []byte{0x01},
+		},
+		{
+			name:     "negative fixint",
+			given:    -1,
+			expected: []byte{0xff},
+		},
+		{
+			name:     "uint16",
+			given:    300,
+			expected: []byte{0xcd, 0x01, 0x2c},
+		},
+		{
+			name:     "int16",
+			given:    -300,
+			expected: []byte{0xd1, 0xfe, 0xd4},
+		},
+		{
+			name:     "float64",
+			given:    0.5,
+			expected: []byte{0xcb, 0x3f, 0xe0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name:     "fixstr",
+			given:    "abc",
+			expected: []byte{0xa3, 'a', 'b', 'c'},
+		},
+		{
+			name:     "fixarray",
+			given:    []interface{}{true, false},
+			expected: []byte{0x92, 0xc3, 0xc2},
+		},
+		{
+			name:     "fixmap with sorted keys",
+			given:    map[string]interface{}{"b": nil, "a": true},
+			expected: []byte{0x82, 0xa1, 'a', 0xc3, 0xa1, 'b', 0xc0},
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual, err = MessagePack.Encode(tc.given)
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+
+		// decodes the reference bytes back to the same value
+		var expected, decoded interface{}
+		_ = fromGeneric(tc.given, &expected)
+		err = MessagePack.Decode(tc.expected, &decoded)
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, expected, decoded, "case %q", tc.name)
+	}
+}
+
+func TestMessagePack_Decode(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		given    []byte
+		expected interface{}
+		err      bool
+	}{
+		{
+			name:     "float32",
+			given:    []byte{0xca, 0x3f, 0x00, 0x00, 0x00},
+			expected: float64(0.5),
+		},
+		{
+			name:     "str8",
+			given:    []byte{0xd9, 0x01, 'a'},
+			expected: "a",
+		},
+		{
+			name:  "truncated",
+			given: []byte{0xa2, 'a'},
+			err:   true,
+		},
+		{
+			name:  "trailing bytes",
+			given: []byte{0x01, 0x01},
+			err:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual interface{}
+		var err = MessagePack.Decode(tc.given, &actual)
+		if tc.err {
+			assert.Error(t, err, "case %q", tc.name)
+			continue
+		}
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
+func TestNewProtobuf(t *testing.T) {
+	var set = newTestDescriptorSet()
+	var data, err = proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var testCases = []struct {
+		name        string
+		given       []byte
+		messageType string
+		err         bool
+	}{
+		{
+			name:        "raw descriptor set",
+			given:       data,
+			messageType: "test.v1.Status",
+		},
+		{
+			name:        "base64 descriptor set",
+			given:       []byte(base64.StdEncoding.EncodeToString(data)),
+			messageType: ".test.v1.Status",
+		},
+		{
+			name:        "unknown message type",
+			given:       data,
+			messageType: "test.v1.Unknown",
+			err:         true,
+		},
+		{
+			name:        "invalid descriptor set",
+			given:       []byte("invalid"),
+			messageType: "test.v1.Status",
+			err:         true,
+		},
+	}
+
+	for _, tc := range testCases {
+		var _, err = NewProtobuf(tc.given, tc.messageType)
+		if tc.err {
+			assert.Error(t, err, "case %q", tc.name)
+		} else {
+			assert.NoError(t, err, "case %q", tc.name)
+		}
+	}
+}
+
+func TestProtobuf(t *testing.T) {
+	var c = newTestProtobuf(t)
+
+	// the wire format follows https://developers.google.com/protocol-buffers/docs/encoding
+	var testCases = []struct {
+		name     string
+		given    map[string]interface{}
+		expected []byte
+	}{
+		{
+			name:     "varint",
+			given:    map[string]interface{}{"count": 150},
+			expected: []byte{0x20, 0x96, 0x01},
+		},
+		{
+			name:     "negative varint",
+			given:    map[string]interface{}{"count": -1},
+			expected: []byte{0x20, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+		},
+		{
+			name:     "string",
+			given:    map[string]interface{}{"name": "testing"},
+			expected: []byte{0x0a, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g'},
+		},
+		{
+			name:     "double",
+			given:    map[string]interface{}{"temperature": 1.5},
+			expected: []byte{0x19, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf8, 0x3f},
+		},
+		{
+			name:     "map",
+			given:    map[string]interface{}{"labels": map[string]interface{}{"a": "b"}},
+			expected: []byte{0x32, 0x06, 0x0a, 0x01, 'a', 0x12, 0x01, 'b'},
+		},
+		{
+			name: "embedded message with enum and packed zigzag",
+			given: map[string]interface{}{
+				"location": map[string]interface{}{"level": "HIGH", "zones": []interface{}{1, -2}},
+			},
+			expected: []byte{0x3a, 0x06, 0x08, 0x01, 0x12, 0x02, 0x02, 0x03},
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual, err = c.Encode(tc.given)
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+
+		// decodes the reference bytes back to the same value
+		var expected, decoded interface{}
+		_ = fromGeneric(tc.given, &expected)
+		err = c.Decode(tc.expected, &decoded)
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, expected, decoded, "case %q", tc.name)
+	}
+
+	var _, err = c.Encode(map[string]interface{}{"location": map[string]interface{}{"level": "UNKNOWN"}})
+	assert.Error(t, err)
+	err = c.Decode([]byte{0x0a, 0x07, 't'}, &map[string]interface{}{})
+	assert.Error(t, err)
+}
+
+func newTestProtobuf(t *testing.T) Codec {
+	var data, err = proto.Marshal(newTestDescriptorSet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewProtobuf(data, "test.v1.Status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func newTestDescriptorSet() *descriptorpb.FileDescriptorSet {
+	var field = func(name string, number int32, label descriptorpb.FieldDescriptorProto_Label, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		var f = &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    label.Enum(),
+			Type:     typ.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	var optional, repeated = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL, descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("status.proto"),
+				Package: proto.String("test.v1"),
+				Syntax:  proto.String("proto3"),
+				EnumType: []*descriptorpb.EnumDescriptorProto{
+					{
+						Name: proto.String("Level"),
+						Value: []*descriptorpb.EnumValueDescriptorProto{
+							{Name: proto.String("LOW"), Number: proto.Int32(0)},
+							{Name: proto.String("HIGH"), Number: proto.Int32(1)},
+						},
+					},
+				},
+				MessageType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("Status"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							field("name", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+							field("enabled", 2, optional, descriptorpb.FieldDescriptorProto_TYPE_BOOL, ""),
+							field("temperature", 3, optional, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, ""),
+							field("count", 4, optional, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+							field("tags", 5, repeated, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+							field("labels", 6, repeated, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".test.v1.Status.LabelsEntry"),
+							field("location", 7, optional, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".test.v1.Location"),
+						},
+						NestedType: []*descriptorpb.DescriptorProto{
+							{
+								Name: proto.String("LabelsEntry"),
+								Field: []*descriptorpb.FieldDescriptorProto{
+									field("key", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+									field("value", 2, optional, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+								},
+								Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+							},
+						},
+					},
+					{
+						Name: proto.String("Location"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							field("level", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".test.v1.Level"),
+							field("zones", 2, repeated, descriptorpb.FieldDescriptorProto_TYPE_SINT32, ""),
+						},
+					},
+				},
+			},
+		},
+	}
+}
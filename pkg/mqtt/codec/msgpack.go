@@ -0,0 +1,46 @@
+package codec
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MessagePack is the codec of MessagePack, refer to https://github.com/msgpack/msgpack/blob/master/spec.md.
+var MessagePack Codec = msgpackCodec{}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) ContentType() string {
+	return "application/msgpack"
+}
+
+func (msgpackCodec) Encode(v interface{}) ([]byte, error) {
+	var g, err = toGeneric(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	var encoder = msgpack.NewEncoder(&buf)
+	encoder.SetSortMapKeys(true)
+	encoder.UseCompactInts(true)
+	if err := encoder.Encode(g); err != nil {
+		return nil, errors.Wrap(err, "failed to encode MessagePack")
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Decode(data []byte, v interface{}) error {
+	var reader = bytes.NewReader(data)
+	var decoder = msgpack.NewDecoder(reader)
+	decoder.UseLooseInterfaceDecoding(true)
+	var g, err = decoder.DecodeInterface()
+	if err != nil {
+		return errors.Wrap(err, "failed to decode MessagePack")
+	}
+	if reader.Len() != 0 {
+		return errors.Errorf("unexpected %d trailing bytes of MessagePack", reader.Len())
+	}
+	return fromGeneric(g, v)
+}
@@ -0,0 +1,123 @@
+package codec
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+type protobufCodec struct {
+	descriptor protoreflect.MessageDescriptor
+}
+
+// NewProtobuf creates the codec of Protocol Buffers,
+// the descriptorSet is the serialized (or base64 encoded) FileDescriptorSet generated by `protoc --descriptor_set_out`,
+// and the messageType is the full name of message type, e.g. "example.v1.Status".
+func NewProtobuf(descriptorSet []byte, messageType string) (Codec, error) {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(descriptorSet, &set); err != nil || len(set.GetFile()) == 0 {
+		var decoded, decodeErr = base64.StdEncoding.DecodeString(strings.TrimSpace(string(descriptorSet)))
+		if decodeErr != nil {
+			return nil, errors.New("failed to parse descriptor set")
+		}
+		set.Reset()
+		if err := proto.Unmarshal(decoded, &set); err != nil {
+			return nil, errors.Wrap(err, "failed to parse descriptor set")
+		}
+	}
+
+	var files, err = protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve descriptor set")
+	}
+	var name = protoreflect.FullName(strings.TrimPrefix(messageType, "."))
+	desc, err := files.FindDescriptorByName(name)
+	if err != nil {
+		return nil, errors.Errorf("cannot find message type %s in descriptor set", messageType)
+	}
+	var md, ok = desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, errors.Errorf("%s is not a message type", messageType)
+	}
+	return &protobufCodec{descriptor: md}, nil
+}
+
+func (c *protobufCodec) ContentType() string {
+	return "application/x-protobuf"
+}
+
+func (c *protobufCodec) Encode(v interface{}) ([]byte, error) {
+	var data, err = json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal value")
+	}
+	var msg = dynamicpb.NewMessage(c.descriptor)
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert value to message %s", c.descriptor.FullName())
+	}
+	ret, err := (proto.MarshalOptions{Deterministic: true}).Marshal(msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode Protocol Buffers")
+	}
+	return ret, nil
+}
+
+func (c *protobufCodec) Decode(data []byte, v interface{}) error {
+	var msg = dynamicpb.NewMessage(c.descriptor)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return errors.Wrap(err, "failed to decode Protocol Buffers")
+	}
+	return fromGeneric(messageToGeneric(msg), v)
+}
+
+// messageToGeneric converts the populated fields of message into the generic value,
+// the 64-bit integers are kept as JSON numbers rather than strings which protojson produces.
+func messageToGeneric(msg protoreflect.Message) map[string]interface{} {
+	var ret = make(map[string]interface{})
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			var obj = make(map[string]interface{}, v.Map().Len())
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				obj[k.String()] = valueToGeneric(fd.MapValue(), mv)
+				return true
+			})
+			ret[fd.JSONName()] = obj
+		case fd.IsList():
+			var list = v.List()
+			var items = make([]interface{}, 0, list.Len())
+			for i := 0; i < list.Len(); i++ {
+				items = append(items, valueToGeneric(fd, list.Get(i)))
+			}
+			ret[fd.JSONName()] = items
+		default:
+			ret[fd.JSONName()] = valueToGeneric(fd, v)
+		}
+		return true
+	})
+	return ret
+}
+
+func valueToGeneric(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int64(v.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageToGeneric(v.Message())
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	default:
+		return v.Interface()
+	}
+}
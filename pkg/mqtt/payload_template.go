@@ -0,0 +1,133 @@
+package mqtt
+
+import (
+	"bytes"
+	"encoding/json"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/rancher/octopus/pkg/mqtt/api"
+	"github.com/rancher/octopus/pkg/util/collection"
+)
+
+// PayloadTemplateData is the data for rendering the payload template.
+type PayloadTemplateData struct {
+	// Namespace is the namespace of DeviceLink.
+	Namespace string
+
+	// Name is the name of DeviceLink.
+	Name string
+
+	// UID is the UID of DeviceLink.
+	UID string
+
+	// Values are the key-value pairs for rendering topic, e.g. "path", "operator" and "property".
+	Values map[string]string
+
+	// Status is the publishing payload in generic form, which is the status of device,
+	// or the property of device in per property mode.
+	Status interface{}
+
+	// Timestamp is the time of publishing.
+	Timestamp time.Time
+}
+
+// payloadTemplate renders the publishing payload via Go text/template.
+type payloadTemplate struct {
+	tmpl   *template.Template
+	ref    corev1.ObjectReference
+	values map[string]string
+}
+
+// Render renders the payload with the rendering values of topic,
+// and returns the rendered bytes.
+func (t *payloadTemplate) Render(payload interface{}, renders map[string]string) ([]byte, error) {
+	var status, err = toGenericPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var values = make(map[string]string, len(t.values)+len(renders))
+	collection.StringMapCopyInto(t.values, values)
+	collection.StringMapCopyInto(renders, values)
+	var data = PayloadTemplateData{
+		Namespace: t.ref.Namespace,
+		Name:      t.ref.Name,
+		UID:       string(t.ref.UID),
+		Values:    values,
+		Status:    status,
+		Timestamp: time.Now(),
+	}
+
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return nil, errors.Wrap(err, "failed to render payload template")
+	}
+	return buf.Bytes(), nil
+}
+
+// toGenericPayload converts the payload to the generic form of JSON,
+// the numbers are kept as json.Number to avoid losing precision.
+func toGenericPayload(payload interface{}) (interface{}, error) {
+	var data, err = json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+	var decoder = json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var ret interface{}
+	if err := decoder.Decode(&ret); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal payload")
+	}
+	return ret, nil
+}
+
+// renderPayload renders the payload of message with the template,
+// the payload is returned as it is if the template is nil or the payload is bytes.
+func renderPayload(t *payloadTemplate, message PublishMessage) (interface{}, error) {
+	if t == nil || message.skipTemplate {
+		return message.Payload, nil
+	}
+	switch message.Payload.(type) {
+	case []byte, bytes.Buffer:
+		return message.Payload, nil
+	}
+	return t.Render(message.Payload, message.Render)
+}
+
+// payloadTemplateFuncs are the functions can be used in the payload template.
+var payloadTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		var data, err = json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	},
+}
+
+// newPayloadTemplate parses the payload template, which can access the rendering values of topic.
+func newPayloadTemplate(text string, operation api.MQTTMessageTopicOperation, ref corev1.ObjectReference) (*payloadTemplate, error) {
+	var tmpl, err = template.New("payload").Funcs(payloadTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse payload template")
+	}
+
+	var values = map[string]string{
+		"namespace": ref.Namespace,
+		"name":      ref.Name,
+		"uid":       string(ref.UID),
+		"path":      operation.Path,
+	}
+	if operation.Operator != nil && operation.Operator.Write != "null" {
+		values["operator"] = operation.Operator.Write
+	}
+	return &payloadTemplate{
+		tmpl:   tmpl,
+		ref:    ref,
+		values: values,
+	}, nil
+}
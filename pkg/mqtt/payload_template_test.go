@@ -0,0 +1,116 @@
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/rancher/octopus/pkg/mqtt/api"
+)
+
+func TestPayloadTemplate_Render(t *testing.T) {
+	type property struct {
+		Name  string  `json:"name"`
+		Value float64 `json:"value"`
+	}
+	type status struct {
+		Properties []property `json:"properties"`
+	}
+	type given struct {
+		template  string
+		operation api.MQTTMessageTopicOperation
+		message   PublishMessage
+	}
+	type expected struct {
+		payload interface{}
+		err     bool
+	}
+
+	var ref = corev1.ObjectReference{Namespace: "default", Name: "thermometer", UID: "835aea2e-5f80-4d14-88f5-40c4bda41aa3"}
+	var payload = status{Properties: []property{{Name: "temperature", Value: 23.5}, {Name: "humidity", Value: 60}}}
+
+	var testCases = []struct {
+		name     string
+		given    given
+		expected expected
+	}{
+		{
+			name: "flat key/values",
+			given: given{
+				template: `{{ range .Status.properties }}{{ .name }}={{ .value }};{{ end }}`,
+				message:  PublishMessage{Payload: payload},
+			},
+			expected: expected{
+				payload: []byte("temperature=23.5;humidity=60;"),
+			},
+		},
+		{
+			name: "InfluxDB line protocol",
+			given: given{
+				template: `{{ .Name }},namespace={{ .Namespace }}{{ range $i, $p := .Status.properties }}{{ if $i }},{{ else }} {{ end }}{{ $p.name }}={{ $p.value }}{{ end }}`,
+				message:  PublishMessage{Payload: payload},
+			},
+			expected: expected{
+				payload: []byte("thermometer,namespace=default temperature=23.5,humidity=60"),
+			},
+		},
+		{
+			name: "custom JSON envelope with site ID",
+			given: given{
+				template:  `{"site":"{{ .Values.path }}","device":"{{ .UID }}","property":"{{ .Values.property }}","data":{{ json .Status }}}`,
+				operation: api.MQTTMessageTopicOperation{Path: "site-1"},
+				message: PublishMessage{
+					Render:  map[string]string{"property": "temperature"},
+					Payload: property{Name: "temperature", Value: 23.5},
+				},
+			},
+			expected: expected{
+				payload: []byte(`{"site":"site-1","device":"835aea2e-5f80-4d14-88f5-40c4bda41aa3","property":"temperature","data":{"name":"temperature","value":23.5}}`),
+			},
+		},
+		{
+			name: "publishes bytes as it is",
+			given: given{
+				template: `{{ .Name }}`,
+				message:  PublishMessage{Payload: []byte("raw")},
+			},
+			expected: expected{
+				payload: []byte("raw"),
+			},
+		},
+		{
+			name: "skips template",
+			given: given{
+				template: `{{ .Name }}`,
+				message:  PublishMessage{Payload: payload, skipTemplate: true},
+			},
+			expected: expected{
+				payload: payload,
+			},
+		},
+		{
+			name: "fails to execute template",
+			given: given{
+				template: `{{ .Status.properties.name }}`,
+				message:  PublishMessage{Payload: payload},
+			},
+			expected: expected{
+				err: true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var tmpl, err = newPayloadTemplate(tc.given.template, tc.given.operation, ref)
+		assert.NoError(t, err, "case %q", tc.name)
+
+		actual, err := renderPayload(tmpl, tc.given.message)
+		if tc.expected.err {
+			assert.Error(t, err, "case %q", tc.name)
+			continue
+		}
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, tc.expected.payload, actual, "case %q", tc.name)
+	}
+}
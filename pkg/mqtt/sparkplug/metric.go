@@ -0,0 +1,175 @@
+package sparkplug
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DataType is the data type of metric.
+type DataType uint32
+
+const (
+	Unknown  DataType = 0
+	Int8     DataType = 1
+	Int16    DataType = 2
+	Int32    DataType = 3
+	Int64    DataType = 4
+	UInt8    DataType = 5
+	UInt16   DataType = 6
+	UInt32   DataType = 7
+	UInt64   DataType = 8
+	Float    DataType = 9
+	Double   DataType = 10
+	Boolean  DataType = 11
+	String   DataType = 12
+	DateTime DataType = 13
+	Text     DataType = 14
+	UUID     DataType = 15
+	Bytes    DataType = 17
+)
+
+// ParseDataType returns the data type of the given type name of device property,
+// it returns Unknown if the type name cannot be recognized.
+func ParseDataType(typeName string) DataType {
+	switch strings.ToLower(typeName) {
+	case "int8":
+		return Int8
+	case "int16":
+		return Int16
+	case "int", "int32":
+		return Int32
+	case "int64":
+		return Int64
+	case "uint8", "byte":
+		return UInt8
+	case "uint16":
+		return UInt16
+	case "uint", "uint32":
+		return UInt32
+	case "uint64":
+		return UInt64
+	case "float", "float32":
+		return Float
+	case "double", "float64":
+		return Double
+	case "boolean", "bool":
+		return Boolean
+	case "string", "hexstring":
+		return String
+	case "datetime":
+		return DateTime
+	case "bytes", "bytestring":
+		return Bytes
+	default:
+		return Unknown
+	}
+}
+
+// InferDataType returns the data type of the given generic value.
+func InferDataType(value interface{}) DataType {
+	switch value.(type) {
+	case bool:
+		return Boolean
+	case int, int8, int16, int32, int64:
+		return Int64
+	case uint, uint8, uint16, uint32, uint64:
+		return UInt64
+	case float32:
+		return Float
+	case float64:
+		return Double
+	case []byte:
+		return Bytes
+	default:
+		return String
+	}
+}
+
+// ConvertValue converts the given generic value into the value of metric with the data type,
+// the string value is parsed according to the data type.
+func ConvertValue(dataType DataType, value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok {
+		return parseValue(dataType, s)
+	}
+
+	switch dataType {
+	case Int8, Int16, Int32, Int64:
+		var n, err = toFloat64(value)
+		return int64(n), err
+	case UInt8, UInt16, UInt32, UInt64:
+		var n, err = toFloat64(value)
+		return uint64(n), err
+	case Float:
+		var n, err = toFloat64(value)
+		return float32(n), err
+	case Double:
+		return toFloat64(value)
+	case Boolean:
+		var b, ok = value.(bool)
+		if !ok {
+			return nil, errors.Errorf("cannot convert %T to boolean", value)
+		}
+		return b, nil
+	case Bytes:
+		var b, ok = value.([]byte)
+		if !ok {
+			return nil, errors.Errorf("cannot convert %T to bytes", value)
+		}
+		return b, nil
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
+
+func parseValue(dataType DataType, s string) (interface{}, error) {
+	switch dataType {
+	case Int8, Int16, Int32, Int64:
+		return strconv.ParseInt(s, 0, 64)
+	case UInt8, UInt16, UInt32, UInt64:
+		return strconv.ParseUint(s, 0, 64)
+	case Float:
+		var f, err = strconv.ParseFloat(s, 32)
+		return float32(f), err
+	case Double:
+		return strconv.ParseFloat(s, 64)
+	case Boolean:
+		return strconv.ParseBool(s)
+	case DateTime:
+		var t, err = time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return strconv.ParseUint(s, 10, 64)
+		}
+		return uint64(t.UnixNano() / int64(time.Millisecond)), nil
+	case Bytes:
+		return base64.StdEncoding.DecodeString(s)
+	default:
+		return s, nil
+	}
+}
+
+func toFloat64(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		return 0, errors.Errorf("cannot convert %T to number", value)
+	}
+}
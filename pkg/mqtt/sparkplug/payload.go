@@ -0,0 +1,312 @@
+package sparkplug
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// Payload is the Sparkplug B payload, refer to https://github.com/eclipse/tahu/blob/master/sparkplug_b/sparkplug_b.proto.
+// The data set, template, metadata and property set of metric are not supported.
+type Payload struct {
+	// Timestamp is the milliseconds since epoch.
+	Timestamp uint64
+
+	Metrics []Metric
+
+	// Seq is the sequence number, it is not set in NDEATH.
+	Seq *uint64
+
+	UUID string
+
+	Body []byte
+}
+
+// Metric is the metric of Sparkplug B payload.
+type Metric struct {
+	Name string
+
+	Alias *uint64
+
+	// Timestamp is the milliseconds since epoch.
+	Timestamp uint64
+
+	DataType DataType
+
+	IsHistorical bool
+
+	IsTransient bool
+
+	IsNull bool
+
+	// Value is one of int64(Int8, Int16, Int32, Int64), uint64(UInt8, UInt16, UInt32, UInt64, DateTime),
+	// float32(Float), float64(Double), bool(Boolean), string(String, Text, UUID) and []byte(Bytes, File).
+	Value interface{}
+}
+
+// the field numbers of Payload.
+const (
+	payloadTimestamp = 1
+	payloadMetrics   = 2
+	payloadSeq       = 3
+	payloadUUID      = 4
+	payloadBody      = 5
+)
+
+// the field numbers of Metric.
+const (
+	metricName         = 1
+	metricAlias        = 2
+	metricTimestamp    = 3
+	metricDataType     = 4
+	metricIsHistorical = 5
+	metricIsTransient  = 6
+	metricIsNull       = 7
+	metricIntValue     = 10
+	metricLongValue    = 11
+	metricFloatValue   = 12
+	metricDoubleValue  = 13
+	metricBooleanValue = 14
+	metricStringValue  = 15
+	metricBytesValue   = 16
+)
+
+// the wire types of Protocol Buffers.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// Marshal encodes the payload in Protocol Buffers.
+func (p *Payload) Marshal() ([]byte, error) {
+	var b []byte
+	b = appendVarintField(b, payloadTimestamp, p.Timestamp)
+	for i := range p.Metrics {
+		var m, err = p.Metrics[i].marshal()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal metric %q", p.Metrics[i].Name)
+		}
+		b = appendBytesField(b, payloadMetrics, m)
+	}
+	if p.Seq != nil {
+		b = appendVarintField(b, payloadSeq, *p.Seq)
+	}
+	if p.UUID != "" {
+		b = appendBytesField(b, payloadUUID, []byte(p.UUID))
+	}
+	if p.Body != nil {
+		b = appendBytesField(b, payloadBody, p.Body)
+	}
+	return b, nil
+}
+
+func (m *Metric) marshal() ([]byte, error) {
+	var b []byte
+	if m.Name != "" {
+		b = appendBytesField(b, metricName, []byte(m.Name))
+	}
+	if m.Alias != nil {
+		b = appendVarintField(b, metricAlias, *m.Alias)
+	}
+	if m.Timestamp != 0 {
+		b = appendVarintField(b, metricTimestamp, m.Timestamp)
+	}
+	if m.DataType != Unknown {
+		b = appendVarintField(b, metricDataType, uint64(m.DataType))
+	}
+	if m.IsHistorical {
+		b = appendVarintField(b, metricIsHistorical, 1)
+	}
+	if m.IsTransient {
+		b = appendVarintField(b, metricIsTransient, 1)
+	}
+	if m.IsNull || m.Value == nil {
+		return appendVarintField(b, metricIsNull, 1), nil
+	}
+
+	switch v := m.Value.(type) {
+	case int64:
+		if m.DataType == Int64 {
+			return appendVarintField(b, metricLongValue, uint64(v)), nil
+		}
+		return appendVarintField(b, metricIntValue, uint64(uint32(v))), nil
+	case uint64:
+		switch m.DataType {
+		case UInt64, DateTime:
+			return appendVarintField(b, metricLongValue, v), nil
+		}
+		return appendVarintField(b, metricIntValue, uint64(uint32(v))), nil
+	case float32:
+		b = appendTag(b, metricFloatValue, wireFixed32)
+		var buf [4]byte
+		binary.LittleEndian.PutUint32(buf[:], math.Float32bits(v))
+		return append(b, buf[:]...), nil
+	case float64:
+		b = appendTag(b, metricDoubleValue, wireFixed64)
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+		return append(b, buf[:]...), nil
+	case bool:
+		if v {
+			return appendVarintField(b, metricBooleanValue, 1), nil
+		}
+		return appendVarintField(b, metricBooleanValue, 0), nil
+	case string:
+		return appendBytesField(b, metricStringValue, []byte(v)), nil
+	case []byte:
+		return appendBytesField(b, metricBytesValue, v), nil
+	default:
+		return nil, errors.Errorf("unsupported value type %T", m.Value)
+	}
+}
+
+// Unmarshal decodes the payload from Protocol Buffers.
+func Unmarshal(data []byte) (*Payload, error) {
+	var p = &Payload{}
+	var err = consumeFields(data, func(num int, wire int, value uint64, raw []byte) error {
+		switch num {
+		case payloadTimestamp:
+			p.Timestamp = value
+		case payloadMetrics:
+			var m, err = unmarshalMetric(raw)
+			if err != nil {
+				return err
+			}
+			p.Metrics = append(p.Metrics, *m)
+		case payloadSeq:
+			var seq = value
+			p.Seq = &seq
+		case payloadUUID:
+			p.UUID = string(raw)
+		case payloadBody:
+			p.Body = append([]byte(nil), raw...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal Sparkplug B payload")
+	}
+	return p, nil
+}
+
+func unmarshalMetric(data []byte) (*Metric, error) {
+	var m = &Metric{}
+	var err = consumeFields(data, func(num int, wire int, value uint64, raw []byte) error {
+		switch num {
+		case metricName:
+			m.Name = string(raw)
+		case metricAlias:
+			var alias = value
+			m.Alias = &alias
+		case metricTimestamp:
+			m.Timestamp = value
+		case metricDataType:
+			m.DataType = DataType(value)
+		case metricIsHistorical:
+			m.IsHistorical = value != 0
+		case metricIsTransient:
+			m.IsTransient = value != 0
+		case metricIsNull:
+			m.IsNull = value != 0
+		case metricIntValue:
+			m.Value = uint64(uint32(value))
+		case metricLongValue:
+			m.Value = value
+		case metricFloatValue:
+			m.Value = math.Float32frombits(uint32(value))
+		case metricDoubleValue:
+			m.Value = math.Float64frombits(value)
+		case metricBooleanValue:
+			m.Value = value != 0
+		case metricStringValue:
+			m.Value = string(raw)
+		case metricBytesValue:
+			m.Value = append([]byte(nil), raw...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if m.IsNull {
+		m.Value = nil
+	}
+	// restores the signed integer with the data type.
+	if v, ok := m.Value.(uint64); ok {
+		switch m.DataType {
+		case Int8, Int16, Int32:
+			m.Value = int64(int32(uint32(v)))
+		case Int64:
+			m.Value = int64(v)
+		}
+	}
+	return m, nil
+}
+
+func consumeFields(data []byte, fn func(num int, wire int, value uint64, raw []byte) error) error {
+	for len(data) > 0 {
+		var tag, n = binary.Uvarint(data)
+		if n <= 0 {
+			return errors.New("invalid tag")
+		}
+		data = data[n:]
+
+		var num, wire = int(tag >> 3), int(tag & 0x7)
+		var value uint64
+		var raw []byte
+		switch wire {
+		case wireVarint:
+			if value, n = binary.Uvarint(data); n <= 0 {
+				return errors.New("invalid varint")
+			}
+		case wireFixed64:
+			if n = 8; len(data) < n {
+				return errors.New("unexpected end of fixed64")
+			}
+			value = binary.LittleEndian.Uint64(data)
+		case wireFixed32:
+			if n = 4; len(data) < n {
+				return errors.New("unexpected end of fixed32")
+			}
+			value = uint64(binary.LittleEndian.Uint32(data))
+		case wireBytes:
+			var size uint64
+			if size, n = binary.Uvarint(data); n <= 0 || size > uint64(len(data)-n) {
+				return errors.New("invalid length")
+			}
+			raw = data[n : n+int(size)]
+			n += int(size)
+		default:
+			return errors.Errorf("unsupported wire type %d", wire)
+		}
+		data = data[n:]
+
+		if err := fn(num, wire, value, raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendTag(b []byte, num int, wire int) []byte {
+	return appendVarint(b, uint64(num)<<3|uint64(wire))
+}
+
+func appendVarint(b []byte, n uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], n)]...)
+}
+
+func appendVarintField(b []byte, num int, n uint64) []byte {
+	b = appendTag(b, num, wireVarint)
+	return appendVarint(b, n)
+}
+
+func appendBytesField(b []byte, num int, data []byte) []byte {
+	b = appendTag(b, num, wireBytes)
+	b = appendVarint(b, uint64(len(data)))
+	return append(b, data...)
+}
@@ -0,0 +1,122 @@
+package sparkplug
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayload_Marshal(t *testing.T) {
+	var seq uint64 = 3
+	var alias uint64 = 1
+
+	var testCases = []struct {
+		name  string
+		given *Payload
+	}{
+		{
+			name: "birth",
+			given: &Payload{
+				Timestamp: 1600000000000,
+				Seq:       &seq,
+				Metrics: []Metric{
+					{Name: BDSeq, DataType: Int64, Value: int64(-1)},
+					{Name: NodeControlRebirth, DataType: Boolean, Value: false},
+					{Name: "temperature", Alias: &alias, Timestamp: 1600000000000, DataType: Float, Value: float32(23.5)},
+					{Name: "humidity", DataType: Double, Value: float64(0.45)},
+					{Name: "offset", DataType: Int16, Value: int64(-12)},
+					{Name: "counter", DataType: UInt32, Value: uint64(42)},
+					{Name: "model", DataType: String, Value: "x"},
+					{Name: "raw", DataType: Bytes, Value: []byte{0x01, 0x02}},
+					{Name: "missing", DataType: Int32, IsNull: true},
+				},
+			},
+		},
+		{
+			name: "death",
+			given: &Payload{
+				Timestamp: 1600000000000,
+				Metrics: []Metric{
+					{Name: BDSeq, DataType: Int64, Value: int64(0)},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var data, err = tc.given.Marshal()
+		assert.NoError(t, err, "case %q", tc.name)
+		actual, err := Unmarshal(data)
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, tc.given, actual, "case %q", tc.name)
+	}
+}
+
+func TestTopic(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		given    []string
+		expected string
+	}{
+		{
+			name:     "node message",
+			given:    []string{"group", NBIRTH, "node", "device"},
+			expected: "spBv1.0/group/NBIRTH/node",
+		},
+		{
+			name:     "device message",
+			given:    []string{"group", DDATA, "node", "device"},
+			expected: "spBv1.0/group/DDATA/node/device",
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = Topic(tc.given[0], tc.given[1], tc.given[2], tc.given[3])
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	type given struct {
+		dataType DataType
+		value    interface{}
+	}
+
+	var testCases = []struct {
+		name     string
+		given    given
+		expected interface{}
+	}{
+		{
+			name:     "parse int string",
+			given:    given{dataType: ParseDataType("int"), value: "-5"},
+			expected: int64(-5),
+		},
+		{
+			name:     "parse float string",
+			given:    given{dataType: ParseDataType("float"), value: "1.5"},
+			expected: float32(1.5),
+		},
+		{
+			name:     "parse boolean string",
+			given:    given{dataType: ParseDataType("boolean"), value: "true"},
+			expected: true,
+		},
+		{
+			name:     "convert number to unsigned integer",
+			given:    given{dataType: UInt16, value: float64(3)},
+			expected: uint64(3),
+		},
+		{
+			name:     "convert number to string",
+			given:    given{dataType: String, value: int64(3)},
+			expected: "3",
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual, err = ConvertValue(tc.given.dataType, tc.given.value)
+		assert.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
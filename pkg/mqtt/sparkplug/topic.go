@@ -0,0 +1,41 @@
+package sparkplug
+
+import (
+	"strings"
+)
+
+// Namespace is the namespace element of Sparkplug B topic.
+const Namespace = "spBv1.0"
+
+// the message types of Sparkplug B.
+const (
+	NBIRTH = "NBIRTH"
+	NDEATH = "NDEATH"
+	DBIRTH = "DBIRTH"
+	DDEATH = "DDEATH"
+	NDATA  = "NDATA"
+	DDATA  = "DDATA"
+	NCMD   = "NCMD"
+	DCMD   = "DCMD"
+)
+
+// NodeControlRebirth is the name of the metric to request the edge node to rebirth.
+const NodeControlRebirth = "Node Control/Rebirth"
+
+// BDSeq is the name of the metric to identify the session of edge node.
+const BDSeq = "bdSeq"
+
+// Topic returns the topic of the given message type,
+// the device ID is ignored for the message types of edge node.
+func Topic(groupID, messageType, edgeNodeID, deviceID string) string {
+	var segments = []string{Namespace, groupID, messageType, edgeNodeID}
+	if messageType[0] == 'D' && deviceID != "" {
+		segments = append(segments, deviceID)
+	}
+	return strings.Join(segments, "/")
+}
+
+// ValidID returns true if the ID can be used as the group ID, edge node ID or device ID.
+func ValidID(id string) bool {
+	return id != "" && !strings.ContainsAny(id, "/+#")
+}
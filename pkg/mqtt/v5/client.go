@@ -0,0 +1,520 @@
+package v5
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/pkg/errors"
+)
+
+// ErrNotConnected is returned if the client is not connected to the broker.
+var ErrNotConnected = errors.New("not connected")
+
+// Logger is the logging interface of client, which is compatible with the logger of paho.
+type Logger interface {
+	Println(v ...interface{})
+	Printf(format string, v ...interface{})
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Println(_ ...interface{}) {}
+
+func (noopLogger) Printf(_ string, _ ...interface{}) {}
+
+// MessageHandler is called in order upon the arrival of messages.
+type MessageHandler func(msg *paho.Publish)
+
+// Options holds the options of client.
+type Options struct {
+	// Servers is the list of broker URI, tries in order until one is connected,
+	// the supported schemas are "tcp", "mqtt", "ssl", "tls", "tcps", "mqtts" and "unix".
+	Servers []*url.URL
+
+	ClientID   string
+	Username   string
+	Password   string
+	CleanStart bool
+	TLSConfig  *tls.Config
+
+	// Will returns the will message of each connection, the will message is disabled if nil.
+	Will func() *paho.WillMessage
+
+	// SessionExpiryInterval specifies how long the broker keeps the session after disconnecting,
+	// the session ends when the connection closed if nil.
+	SessionExpiryInterval *uint32
+
+	// TopicAliasMaximum specifies the number of topic aliases that the client accepts from broker,
+	// and limits the number of topic aliases the client uses toward broker.
+	// 0 disables the topic alias.
+	TopicAliasMaximum uint16
+
+	KeepAlive      time.Duration
+	ConnectTimeout time.Duration
+	// WriteTimeout specifies how long to wait for the acknowledgement of publishing/subscribing.
+	WriteTimeout time.Duration
+
+	// AutoReconnect reconnects with backoff if the connection is lost,
+	// the first reconnect interval is 1 second,
+	// and then the interval is incremented by *2 until MaxReconnectInterval is reached.
+	AutoReconnect        bool
+	MaxReconnectInterval time.Duration
+
+	OnConnect        func()
+	OnConnectionLost func(err error)
+
+	Logger Logger
+}
+
+// session holds a connected paho client, which is dropped along with the connection.
+type session struct {
+	server *url.URL
+	cli    *paho.Client
+	lost   bool
+
+	// publishing is serialized if using the topic alias,
+	// so that the alias is always sent after the one carrying the topic name.
+	aliased   bool
+	publishMu sync.Mutex
+}
+
+// Client is a MQTT v5 client, which manages the connections of paho client,
+// it connects to the servers in order, and reconnects with backoff if the connection is lost.
+type Client struct {
+	opts Options
+	log  Logger
+
+	mu            sync.Mutex
+	current       *session
+	stopped       bool
+	stop          chan struct{}
+	subscriptions map[string]paho.SubscribeOptions
+	routes        map[string]MessageHandler
+	handler       MessageHandler
+
+	inflight sync.WaitGroup
+}
+
+// NewClient creates the MQTT v5 client.
+func NewClient(opts Options) *Client {
+	var c = &Client{
+		opts:          opts,
+		log:           opts.Logger,
+		stop:          make(chan struct{}),
+		subscriptions: make(map[string]paho.SubscribeOptions),
+		routes:        make(map[string]MessageHandler),
+	}
+	if c.log == nil {
+		c.log = noopLogger{}
+	}
+	return c
+}
+
+// SetMessageHandler sets the default handler for the arrival messages,
+// which is called if the topic of message is not matched by any route.
+func (c *Client) SetMessageHandler(handler MessageHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handler = handler
+}
+
+// AddRoute adds the handler for the arrival messages matched the topic filter.
+func (c *Client) AddRoute(filter string, handler MessageHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.routes[filter] = handler
+}
+
+// RemoveRoute removes the handlers of the topic filters.
+func (c *Client) RemoveRoute(filters ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, filter := range filters {
+		delete(c.routes, filter)
+	}
+}
+
+// IsConnected returns true if the client is connected to the broker.
+func (c *Client) IsConnected() bool {
+	return c.session() != nil
+}
+
+// Server returns the server URI of the connected broker, or nil if disconnected.
+func (c *Client) Server() *url.URL {
+	var s = c.session()
+	if s == nil {
+		return nil
+	}
+	return s.server
+}
+
+// Connect connects to the broker.
+func (c *Client) Connect() error {
+	c.mu.Lock()
+	var stopped, connected = c.stopped, c.current != nil
+	c.mu.Unlock()
+	if stopped {
+		return errors.New("client has been disconnected")
+	}
+	if connected {
+		return nil
+	}
+	return c.connect()
+}
+
+// Disconnect waits the in-flight messages at most quiesce, and then disconnects from the broker,
+// the client cannot be connected again.
+func (c *Client) Disconnect(quiesce time.Duration) {
+	c.mu.Lock()
+	if c.stopped {
+		c.mu.Unlock()
+		return
+	}
+	c.stopped = true
+	close(c.stop)
+	var s = c.current
+	c.current = nil
+	c.mu.Unlock()
+
+	if s == nil {
+		return
+	}
+	var done = make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(quiesce):
+	}
+	_ = s.cli.Disconnect(&paho.Disconnect{ReasonCode: 0})
+}
+
+// Publish publishes the message, it waits for the acknowledgement if the QoS is greater than 0.
+func (c *Client) Publish(ctx context.Context, msg *paho.Publish) error {
+	var s = c.session()
+	if s == nil {
+		return ErrNotConnected
+	}
+	c.inflight.Add(1)
+	defer c.inflight.Done()
+
+	// copies the message as the topic alias modifies the topic and properties
+	var p = *msg
+	if msg.Properties != nil {
+		var props = *msg.Properties
+		p.Properties = &props
+	}
+	if s.aliased {
+		s.publishMu.Lock()
+		defer s.publishMu.Unlock()
+	}
+	var resp, err = s.cli.Publish(ctx, &p)
+	if err != nil {
+		return err
+	}
+	// paho doesn't return an error if the PUBREC of QoS 2 indicates a failure.
+	if resp != nil && resp.ReasonCode >= 0x80 {
+		var reason string
+		if resp.Properties != nil {
+			reason = resp.Properties.ReasonString
+		}
+		return errors.Errorf("failed to publish as reason code 0x%02X: %s", resp.ReasonCode, reason)
+	}
+	return nil
+}
+
+// Subscribe subscribes the topic filters, and resubscribes them if reconnected without session.
+func (c *Client) Subscribe(ctx context.Context, subscriptions map[string]paho.SubscribeOptions) error {
+	if len(subscriptions) == 0 {
+		return nil
+	}
+	var s = c.session()
+	if s == nil {
+		return ErrNotConnected
+	}
+
+	var _, err = s.cli.Subscribe(ctx, &paho.Subscribe{Subscriptions: subscriptions})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for topic, opts := range subscriptions {
+		c.subscriptions[topic] = opts
+	}
+	return nil
+}
+
+// Unsubscribe unsubscribes the topic filters.
+func (c *Client) Unsubscribe(ctx context.Context, topics ...string) error {
+	if len(topics) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	for _, t := range topics {
+		delete(c.subscriptions, t)
+	}
+	c.mu.Unlock()
+
+	var s = c.session()
+	if s == nil {
+		return ErrNotConnected
+	}
+	var _, err = s.cli.Unsubscribe(ctx, &paho.Unsubscribe{Topics: topics})
+	return err
+}
+
+func (c *Client) session() *session {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.current
+}
+
+func (c *Client) connect() error {
+	if len(c.opts.Servers) == 0 {
+		return errors.New("no server to connect")
+	}
+
+	var lastErr error
+	for _, server := range c.opts.Servers {
+		var err = c.connectTo(server)
+		if err == nil {
+			return nil
+		}
+		c.log.Println("Failed to connect", "server: ", server.String(), ", error: ", err)
+		lastErr = errors.Wrapf(err, "failed to connect %s", server.String())
+	}
+	return lastErr
+}
+
+func (c *Client) connectTo(server *url.URL) error {
+	var connectTimeout = c.opts.ConnectTimeout
+	if connectTimeout == 0 {
+		connectTimeout = 30 * time.Second
+	}
+	var ctx, cancel = context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
+	var conn, err = c.dial(server, connectTimeout)
+	if err != nil {
+		return err
+	}
+
+	var s = &session{server: server}
+	s.cli = paho.NewClient(paho.ClientConfig{
+		ClientID: c.opts.ClientID,
+		Conn:     conn,
+		Router:   paho.NewSingleHandlerRouter(c.route),
+		PingHandler: keepAlivePinger{
+			PingHandler: paho.DefaultPingerWithCustomFailHandler(func(err error) {
+				// closing the connection makes paho report the client error
+				_ = conn.Close()
+			}),
+		},
+		PacketTimeout: c.opts.WriteTimeout,
+		OnClientError: func(err error) {
+			c.lost(s, err)
+		},
+		OnServerDisconnect: func(d *paho.Disconnect) {
+			var err = errors.Errorf("disconnected by server as reason code 0x%02X", d.ReasonCode)
+			if d.Properties != nil && d.Properties.ReasonString != "" {
+				err = errors.Wrap(err, d.Properties.ReasonString)
+			}
+			c.lost(s, err)
+		},
+	})
+	connack, err := s.cli.Connect(ctx, c.connectPacket())
+	if err != nil {
+		return err
+	}
+
+	var aliasMaximum uint16
+	if props := connack.Properties; props != nil && props.TopicAliasMaximum != nil {
+		aliasMaximum = *props.TopicAliasMaximum
+	}
+	if aliasMaximum > c.opts.TopicAliasMaximum {
+		aliasMaximum = c.opts.TopicAliasMaximum
+	}
+	if aliasMaximum > 0 {
+		s.aliased = true
+		s.cli.PublishHook = (&topicAliases{maximum: aliasMaximum, aliases: map[string]uint16{}}).assign
+	}
+
+	c.mu.Lock()
+	if c.stopped || s.lost {
+		var stopped = c.stopped
+		c.mu.Unlock()
+		_ = s.cli.Disconnect(&paho.Disconnect{ReasonCode: 0})
+		if stopped {
+			return errors.New("client has been disconnected")
+		}
+		return errors.New("connection lost")
+	}
+	c.current = s
+	var resubscriptions map[string]paho.SubscribeOptions
+	if !connack.SessionPresent && len(c.subscriptions) != 0 {
+		resubscriptions = make(map[string]paho.SubscribeOptions, len(c.subscriptions))
+		for topic, opts := range c.subscriptions {
+			resubscriptions[topic] = opts
+		}
+	}
+	c.mu.Unlock()
+
+	if len(resubscriptions) != 0 {
+		go func() {
+			if err := c.Subscribe(context.Background(), resubscriptions); err != nil {
+				c.log.Println("Failed to resubscribe", "error: ", err)
+			}
+		}()
+	}
+	c.log.Println("Connected", "server: ", server.String(), ", session present: ", connack.SessionPresent)
+	if c.opts.OnConnect != nil {
+		go c.opts.OnConnect()
+	}
+	return nil
+}
+
+func (c *Client) connectPacket() *paho.Connect {
+	var cp = &paho.Connect{
+		ClientID:   c.opts.ClientID,
+		KeepAlive:  uint16(c.opts.KeepAlive / time.Second),
+		CleanStart: c.opts.CleanStart,
+		Properties: &paho.ConnectProperties{
+			SessionExpiryInterval: c.opts.SessionExpiryInterval,
+		},
+	}
+	if c.opts.Username != "" {
+		cp.Username = c.opts.Username
+		cp.UsernameFlag = true
+	}
+	if c.opts.Password != "" {
+		cp.Password = []byte(c.opts.Password)
+		cp.PasswordFlag = true
+	}
+	if c.opts.TopicAliasMaximum > 0 {
+		var aliasMaximum = c.opts.TopicAliasMaximum
+		cp.Properties.TopicAliasMaximum = &aliasMaximum
+	}
+	if c.opts.Will != nil {
+		cp.WillMessage = c.opts.Will()
+	}
+	return cp
+}
+
+func (c *Client) dial(server *url.URL, timeout time.Duration) (net.Conn, error) {
+	var dialer = &net.Dialer{Timeout: timeout}
+	switch server.Scheme {
+	case "tcp", "mqtt":
+		return dialer.Dial("tcp", server.Host)
+	case "ssl", "tls", "tcps", "mqtts":
+		return tls.DialWithDialer(dialer, "tcp", server.Host, c.opts.TLSConfig)
+	case "unix":
+		return dialer.Dial("unix", server.Path)
+	default:
+		return nil, errors.Errorf("unsupported schema %s of MQTT v5", server.Scheme)
+	}
+}
+
+func (c *Client) route(msg *paho.Publish) {
+	c.mu.Lock()
+	var handlers []MessageHandler
+	for filter, handler := range c.routes {
+		if MatchTopic(filter, msg.Topic) {
+			handlers = append(handlers, handler)
+		}
+	}
+	if len(handlers) == 0 && c.handler != nil {
+		handlers = append(handlers, c.handler)
+	}
+	c.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(msg)
+	}
+}
+
+// lost drops the lost session, and reconnects if needed.
+func (c *Client) lost(s *session, err error) {
+	c.mu.Lock()
+	s.lost = true
+	if c.current != s || c.stopped {
+		c.mu.Unlock()
+		return
+	}
+	c.current = nil
+	c.mu.Unlock()
+
+	c.log.Println("Connection lost", "server: ", s.server.String(), ", error: ", err)
+	if c.opts.OnConnectionLost != nil {
+		go c.opts.OnConnectionLost(err)
+	}
+	if c.opts.AutoReconnect {
+		go c.reconnect()
+	}
+}
+
+func (c *Client) reconnect() {
+	var maxInterval = c.opts.MaxReconnectInterval
+	if maxInterval == 0 {
+		maxInterval = 10 * time.Minute
+	}
+	var interval = time.Second
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(interval):
+		}
+		if err := c.Connect(); err == nil {
+			return
+		}
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+// keepAlivePinger doesn't ping if the keep alive is 0, which is not allowed by the pinger of paho.
+type keepAlivePinger struct {
+	*paho.PingHandler
+}
+
+func (p keepAlivePinger) Start(conn net.Conn, keepAlive time.Duration) {
+	if keepAlive <= 0 {
+		return
+	}
+	p.PingHandler.Start(conn, keepAlive)
+}
+
+// topicAliases assigns the topic aliases of publishing within a connection,
+// the first publishing of a topic carries both topic name and alias, and then only the alias.
+type topicAliases struct {
+	maximum uint16
+	aliases map[string]uint16
+}
+
+func (t *topicAliases) assign(p *paho.Publish) {
+	if p.Properties == nil {
+		p.Properties = &paho.PublishProperties{}
+	}
+	if alias, exist := t.aliases[p.Topic]; exist {
+		p.Properties.TopicAlias = &alias
+		p.Topic = ""
+		return
+	}
+	if uint16(len(t.aliases)) < t.maximum {
+		var alias = uint16(len(t.aliases)) + 1
+		t.aliases[p.Topic] = alias
+		p.Properties.TopicAlias = &alias
+	}
+}
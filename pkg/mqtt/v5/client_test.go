@@ -0,0 +1,255 @@
+package v5
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBroker accepts the connections, acknowledges all packets and records the received packets,
+// it sends the given messages back after subscribing.
+type fakeBroker struct {
+	sync.Mutex
+
+	listener  net.Listener
+	connects  []*packets.Connect
+	published []*packets.Publish
+	replies   []*packets.Publish
+	conns     []net.Conn
+}
+
+func (b *fakeBroker) serve(t *testing.T) {
+	for {
+		var conn, err = b.listener.Accept()
+		if err != nil {
+			return
+		}
+		b.Lock()
+		b.conns = append(b.conns, conn)
+		b.Unlock()
+		go b.handle(t, conn)
+	}
+}
+
+// drop closes the accepted connections without DISCONNECT.
+func (b *fakeBroker) drop() {
+	b.Lock()
+	defer b.Unlock()
+	for _, conn := range b.conns {
+		_ = conn.Close()
+	}
+	b.conns = nil
+}
+
+func (b *fakeBroker) handle(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		var cp, err = packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+
+		var resp []packets.Packet
+		switch p := cp.Content.(type) {
+		case *packets.Connect:
+			b.Lock()
+			b.connects = append(b.connects, p)
+			b.Unlock()
+			var aliasMaximum uint16 = 10
+			resp = append(resp, &packets.Connack{Properties: &packets.Properties{TopicAliasMaximum: &aliasMaximum}})
+		case *packets.Publish:
+			b.Lock()
+			b.published = append(b.published, p)
+			b.Unlock()
+			switch p.QoS {
+			case 1:
+				resp = append(resp, &packets.Puback{PacketID: p.PacketID, Properties: &packets.Properties{}})
+			case 2:
+				resp = append(resp, &packets.Pubrec{PacketID: p.PacketID, Properties: &packets.Properties{}})
+			}
+		case *packets.Pubrel:
+			resp = append(resp, &packets.Pubcomp{PacketID: p.PacketID, Properties: &packets.Properties{}})
+		case *packets.Subscribe:
+			var codes = make([]byte, 0, len(p.Subscriptions))
+			for _, s := range p.Subscriptions {
+				codes = append(codes, s.QoS)
+			}
+			resp = append(resp, &packets.Suback{PacketID: p.PacketID, Reasons: codes, Properties: &packets.Properties{}})
+			for _, r := range b.replies {
+				resp = append(resp, r)
+			}
+		case *packets.Pingreq:
+			resp = append(resp, &packets.Pingresp{})
+		case *packets.Disconnect:
+			return
+		}
+		for _, r := range resp {
+			if _, err := r.WriteTo(conn); err != nil {
+				t.Logf("failed to write packet: %v", err)
+				return
+			}
+		}
+	}
+}
+
+func newFakeBroker(t *testing.T, replies ...*packets.Publish) *fakeBroker {
+	var listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var broker = &fakeBroker{
+		listener: listener,
+		replies:  replies,
+	}
+	go broker.serve(t)
+	return broker
+}
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func TestClient(t *testing.T) {
+	var broker = newFakeBroker(t,
+		&packets.Publish{
+			Topic:   "a/b",
+			Payload: []byte("first"),
+			Properties: &packets.Properties{
+				TopicAlias:      uint16Ptr(1),
+				ResponseTopic:   "a/b/response",
+				CorrelationData: []byte("1"),
+			},
+		},
+		&packets.Publish{
+			QoS:      1,
+			PacketID: 1,
+			Payload:  []byte("second"),
+			Properties: &packets.Properties{
+				TopicAlias: uint16Ptr(1),
+			},
+		},
+	)
+	defer broker.listener.Close()
+
+	var received = make(chan *paho.Publish, 2)
+	var cli = NewClient(Options{
+		Servers:           []*url.URL{{Scheme: "tcp", Host: broker.listener.Addr().String()}},
+		ClientID:          "octopus",
+		CleanStart:        true,
+		TopicAliasMaximum: 5,
+		ConnectTimeout:    5 * time.Second,
+		Will: func() *paho.WillMessage {
+			return &paho.WillMessage{Topic: "a/will", Payload: []byte("will")}
+		},
+	})
+	cli.SetMessageHandler(func(msg *paho.Publish) {
+		received <- msg
+	})
+	assert.NoError(t, cli.Connect())
+	defer cli.Disconnect(time.Second)
+
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// subscribes and receives the messages with topic alias
+	assert.NoError(t, cli.Subscribe(ctx, map[string]paho.SubscribeOptions{"a/+": {QoS: 1}}))
+	for _, expected := range []string{"first", "second"} {
+		select {
+		case msg := <-received:
+			assert.Equal(t, "a/b", msg.Topic)
+			assert.Equal(t, expected, string(msg.Payload))
+		case <-ctx.Done():
+			t.Fatal("timeout to receive message")
+		}
+	}
+
+	// publishes with different QoS, the repeated topic is sent as topic alias
+	for _, qos := range []byte{0, 1, 2} {
+		assert.NoError(t, cli.Publish(ctx, &paho.Publish{
+			Topic:   "c/d",
+			QoS:     qos,
+			Payload: []byte{qos},
+			Properties: &paho.PublishProperties{
+				ContentType: "application/octet-stream",
+			},
+		}))
+	}
+	// waits for the QoS 0 message which doesn't have acknowledgement
+	assert.NoError(t, cli.Publish(ctx, &paho.Publish{Topic: "c/d", QoS: 1}))
+
+	broker.Lock()
+	defer broker.Unlock()
+	assert.Len(t, broker.connects, 1)
+	assert.Equal(t, uint16(5), *broker.connects[0].Properties.TopicAliasMaximum)
+	assert.Equal(t, "a/will", broker.connects[0].WillTopic)
+	assert.Len(t, broker.published, 4)
+	for i, p := range broker.published {
+		if i == 0 {
+			assert.Equal(t, "c/d", p.Topic)
+		} else {
+			assert.Equal(t, "", p.Topic)
+		}
+		assert.Equal(t, uint16(1), *p.Properties.TopicAlias)
+	}
+	assert.Equal(t, "application/octet-stream", broker.published[2].Properties.ContentType)
+}
+
+func TestClient_Reconnect(t *testing.T) {
+	var broker = newFakeBroker(t)
+	defer broker.listener.Close()
+
+	var connected = make(chan struct{}, 2)
+	var lost = make(chan error, 1)
+	var cli = NewClient(Options{
+		Servers:              []*url.URL{{Scheme: "tcp", Host: broker.listener.Addr().String()}},
+		ClientID:             "octopus",
+		ConnectTimeout:       5 * time.Second,
+		AutoReconnect:        true,
+		MaxReconnectInterval: time.Second,
+		OnConnect: func() {
+			connected <- struct{}{}
+		},
+		OnConnectionLost: func(err error) {
+			lost <- err
+		},
+	})
+	assert.NoError(t, cli.Connect())
+	defer cli.Disconnect(time.Second)
+
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, cli.Subscribe(ctx, map[string]paho.SubscribeOptions{"a/+": {QoS: 1}}))
+
+	for _, event := range []string{"connected", "lost", "reconnected"} {
+		select {
+		case <-connected:
+			assert.NotEqual(t, "lost", event)
+		case err := <-lost:
+			assert.Equal(t, "lost", event)
+			assert.Error(t, err)
+			assert.False(t, cli.IsConnected())
+		case <-ctx.Done():
+			t.Fatalf("timeout to wait for %s", event)
+		}
+		if event == "connected" {
+			broker.drop()
+		}
+	}
+	assert.True(t, cli.IsConnected())
+}
+
+func TestClient_NotConnected(t *testing.T) {
+	var cli = NewClient(Options{})
+	defer cli.Disconnect(0)
+
+	assert.Equal(t, ErrNotConnected, cli.Publish(context.Background(), &paho.Publish{Topic: "a", QoS: 1}))
+	assert.Error(t, cli.Connect())
+}
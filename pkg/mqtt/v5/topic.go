@@ -0,0 +1,52 @@
+package v5
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SharedSubscriptionPrefix is the prefix of shared subscription topic filter,
+// the format is `$share/{group}/{filter}`.
+const SharedSubscriptionPrefix = "$share/"
+
+// SharedTopic returns the shared subscription topic filter of the given group,
+// it returns the filter directly if the group is blank.
+func SharedTopic(group, filter string) string {
+	if group == "" {
+		return filter
+	}
+	return fmt.Sprintf("%s%s/%s", SharedSubscriptionPrefix, group, filter)
+}
+
+// MatchTopic returns true if the topic name matches the topic filter,
+// the shared subscription prefix of the filter is ignored.
+func MatchTopic(filter, topic string) bool {
+	if strings.HasPrefix(filter, SharedSubscriptionPrefix) {
+		var segments = strings.SplitN(filter, "/", 3)
+		if len(segments) != 3 {
+			return false
+		}
+		filter = segments[2]
+	}
+
+	var filterSegments = strings.Split(filter, "/")
+	var topicSegments = strings.Split(topic, "/")
+	// the topics started with "$" cannot be matched by the wildcard at the first level.
+	if len(filterSegments) != 0 && len(topicSegments) != 0 &&
+		strings.HasPrefix(topicSegments[0], "$") && filterSegments[0] != topicSegments[0] {
+		return false
+	}
+
+	for i, fs := range filterSegments {
+		if fs == "#" {
+			return true
+		}
+		if i >= len(topicSegments) {
+			return false
+		}
+		if fs != "+" && fs != topicSegments[i] {
+			return false
+		}
+	}
+	return len(filterSegments) == len(topicSegments)
+}
@@ -0,0 +1,31 @@
+package v5
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchTopic(t *testing.T) {
+	var testCases = []struct {
+		filter   string
+		topic    string
+		expected bool
+	}{
+		{filter: "a/b", topic: "a/b", expected: true},
+		{filter: "a/b", topic: "a/c", expected: false},
+		{filter: "a/+", topic: "a/b", expected: true},
+		{filter: "a/+", topic: "a/b/c", expected: false},
+		{filter: "a/#", topic: "a/b/c", expected: true},
+		{filter: "a/#", topic: "a", expected: true},
+		{filter: "#", topic: "$SYS/broker", expected: false},
+		{filter: "$SYS/#", topic: "$SYS/broker", expected: true},
+		{filter: "$share/group/a/+", topic: "a/b", expected: true},
+		{filter: "$share/group", topic: "group", expected: false},
+	}
+
+	for _, tc := range testCases {
+		var actual = MatchTopic(tc.filter, tc.topic)
+		assert.Equal(t, tc.expected, actual, "case %q matches %q", tc.filter, tc.topic)
+	}
+}
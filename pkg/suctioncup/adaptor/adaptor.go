@@ -3,17 +3,22 @@
 import (
 	"context"
 	"net"
+	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/pkg/errors"
+	"go.uber.org/atomic"
 	"google.golang.org/grpc"
 	grpccodes "google.golang.org/grpc/codes"
 	grpcstatus "google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	api "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
 	"github.com/rancher/octopus/pkg/suctioncup/connection"
 	"github.com/rancher/octopus/pkg/suctioncup/event"
+	"github.com/rancher/octopus/pkg/util/collection"
 )
 
 type Adaptor interface {
@@ -23,6 +28,28 @@ type Adaptor interface {
 	// GetEndpoint returns the endpoint of adaptor
 	GetEndpoint() string
 
+	// GetVersion returns the API version of adaptor
+	GetVersion() string
+
+	// GetModels returns the models declared by adaptor
+	GetModels() []metav1.TypeMeta
+
+	// GetFeatures returns the features declared by adaptor
+	GetFeatures() []string
+
+	// IsModelSupported returns true if the adaptor serves the model,
+	// an adaptor which doesn't declare any models is treated as serving all models.
+	IsModelSupported(model metav1.TypeMeta) bool
+
+	// IsFeatureSupported returns true if the adaptor declares the feature
+	IsFeatureSupported(feature string) bool
+
+	// GetRegistrationTime returns the time when the adaptor was registered
+	GetRegistrationTime() time.Time
+
+	// GetConnectionCount returns the number of active connections
+	GetConnectionCount() int
+
 	// Stop stops the adaptor and deletes all connections
 	Stop() error
 
@@ -31,9 +58,21 @@ type Adaptor interface {
 
 	// DeleteConnection deletes the connection of name
 	DeleteConnection(name types.NamespacedName) (exist bool)
+
+	// GetConnection returns the active connection of name
+	GetConnection(name types.NamespacedName) connection.Connection
+
+	// Leave marks the adaptor as leaving, the leaving adaptor is draining:
+	// it refuses new connections, and doesn't notify the errors or passive closing of its connections.
+	Leave()
+
+	// IsLeaving returns true if the adaptor is leaving or its socket has been removed
+	IsLeaving() bool
 }
 
-func NewAdaptor(dir, name, endpoint string, notifier event.ConnectionNotifier) (Adaptor, error) {
+func NewAdaptor(dir string, req *api.RegisterRequest, notifier event.ConnectionNotifier) (Adaptor, error) {
+	var name = req.GetName()
+	var endpoint = req.GetEndpoint()
 	var socketPath = filepath.Join(dir, endpoint)
 
 	var cliOptions = []grpc.DialOption{
@@ -53,9 +92,21 @@ func NewAdaptor(dir, name, endpoint string, notifier event.ConnectionNotifier) (
 		return nil, errors.Wrapf(err, "failed to dial adaptor: %s", socketPath)
 	}
 
+	var models = make([]metav1.TypeMeta, 0, len(req.GetModels()))
+	for _, model := range req.GetModels() {
+		if model != nil {
+			models = append(models, *model)
+		}
+	}
+
 	return &adaptor{
 		name:       name,
 		endpoint:   endpoint,
+		version:    req.GetVersion(),
+		models:     models,
+		features:   req.GetFeatures(),
+		registered: time.Now(),
+		socketPath: socketPath,
 		clientConn: conn,
 		conns:      connection.NewConnections(),
 		notifier:   notifier,
@@ -63,8 +114,14 @@ func NewAdaptor(dir, name, endpoint string, notifier event.ConnectionNotifier) (
 }
 
 type adaptor struct {
+	leaving    atomic.Bool
 	name       string
 	endpoint   string
+	version    string
+	models     []metav1.TypeMeta
+	features   []string
+	registered time.Time
+	socketPath string
 	clientConn *grpc.ClientConn
 	conns      connection.Connections
 	notifier   event.ConnectionNotifier
@@ -78,6 +135,42 @@ func (a *adaptor) GetEndpoint() string {
 	return a.endpoint
 }
 
+func (a *adaptor) GetVersion() string {
+	return a.version
+}
+
+func (a *adaptor) GetModels() []metav1.TypeMeta {
+	return a.models
+}
+
+func (a *adaptor) GetFeatures() []string {
+	return a.features
+}
+
+func (a *adaptor) IsModelSupported(model metav1.TypeMeta) bool {
+	if len(a.models) == 0 {
+		return true
+	}
+	for _, m := range a.models {
+		if m.APIVersion == model.APIVersion && m.Kind == model.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *adaptor) IsFeatureSupported(feature string) bool {
+	return collection.StringSliceContain(a.features, feature)
+}
+
+func (a *adaptor) GetRegistrationTime() time.Time {
+	return a.registered
+}
+
+func (a *adaptor) GetConnectionCount() int {
+	return a.conns.Count()
+}
+
 func (a *adaptor) Stop() error {
 	a.conns.Cleanup()
 
@@ -93,13 +186,17 @@ func (a *adaptor) Stop() error {
 }
 
 func (a *adaptor) CreateConnection(name types.NamespacedName) (overwritten bool, conn connection.Connection, err error) {
+	if a.IsLeaving() {
+		return false, nil, errors.Errorf("adaptor %s is leaving", a.name)
+	}
+
 	conn = a.conns.Get(name)
 	if conn != nil {
 		if !conn.IsStop() {
 			return true, conn, nil
 		}
 	}
-	conn, err = connection.NewConnection(a.name, name, a.clientConn, a.notifier)
+	conn, err = connection.NewConnection(a.name, name, a.clientConn, a)
 	if err != nil {
 		return false, nil, err
 	}
@@ -109,3 +206,50 @@ func (a *adaptor) CreateConnection(name types.NamespacedName) (overwritten bool,
 func (a *adaptor) DeleteConnection(name types.NamespacedName) bool {
 	return a.conns.Delete(name)
 }
+
+func (a *adaptor) GetConnection(name types.NamespacedName) connection.Connection {
+	var conn = a.conns.Get(name)
+	if conn == nil || conn.IsStop() {
+		return nil
+	}
+	return conn
+}
+
+func (a *adaptor) Leave() {
+	a.leaving.Store(true)
+}
+
+func (a *adaptor) IsLeaving() bool {
+	if a.leaving.Load() {
+		return true
+	}
+	// the adaptor removes its socket before closing the connections when shutting down,
+	// so we can treat the connection closing as leaving even the socket watcher hasn't noticed yet.
+	if _, err := os.Stat(a.socketPath); os.IsNotExist(err) {
+		return true
+	}
+	return false
+}
+
+// implement the ConnectionNotifier interface as a proxy
+func (a *adaptor) NoticeConnectionReceivedData(adaptorName string, name types.NamespacedName, data []byte) {
+	a.notifier.NoticeConnectionReceivedData(adaptorName, name, data)
+}
+
+// implement the ConnectionNotifier interface as a proxy
+func (a *adaptor) NoticeConnectionReceivedError(adaptorName string, name types.NamespacedName, err error) {
+	if a.IsLeaving() {
+		log.V(4).Info("Ignore the error of connection as the adaptor is leaving", "adaptor", adaptorName, "connection", name, "error", err.Error())
+		return
+	}
+	a.notifier.NoticeConnectionReceivedError(adaptorName, name, err)
+}
+
+// implement the ConnectionNotifier interface as a proxy
+func (a *adaptor) NoticeConnectionClosed(adaptorName string, name types.NamespacedName) {
+	if a.IsLeaving() {
+		log.V(4).Info("Ignore the closing of connection as the adaptor is leaving", "adaptor", adaptorName, "connection", name)
+		return
+	}
+	a.notifier.NoticeConnectionClosed(adaptorName, name)
+}
@@ -0,0 +1,23 @@
+package adaptor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/rancher/octopus/pkg/suctioncup/connection"
+)
+
+func TestAdaptor_CreateConnection(t *testing.T) {
+	var adp = &adaptor{
+		name:  "dummy",
+		conns: connection.NewConnections(),
+	}
+	adp.Leave()
+
+	var overwritten, conn, err = adp.CreateConnection(types.NamespacedName{Namespace: "default", Name: "living-room-fan"})
+	assert.False(t, overwritten)
+	assert.Nil(t, conn)
+	assert.EqualError(t, err, "adaptor dummy is leaving", "a leaving adaptor refuses new connections")
+}
@@ -36,17 +36,50 @@ func (c Adaptors) Delete(nameOrEndpoint string) {
 	}
 }
 
+// DeleteIfStale deletes the given adaptor only if it hasn't been replaced by another one with the same name,
+// the return value represents whether the adaptor is deleted.
+func (c Adaptors) DeleteIfStale(adaptor Adaptor) bool {
+	if aa, exist := c.index.Load(adaptor.GetName()); !exist || aa.(Adaptor) != adaptor {
+		return false
+	}
+	if err := adaptor.Stop(); err != nil {
+		log.Error(err, "Failed to stop adaptor", "adaptor", adaptor.GetName())
+	}
+	c.index.Delete(adaptor.GetName())
+	if aa, exist := c.index.Load(adaptor.GetEndpoint()); exist && aa.(Adaptor) == adaptor {
+		c.index.Delete(adaptor.GetEndpoint())
+	}
+	return true
+}
+
 func (c Adaptors) Put(adaptor Adaptor) {
 	if aa, exist := c.index.LoadOrStore(adaptor.GetName(), adaptor); exist {
 		var staleAdaptor = aa.(Adaptor)
 		if err := staleAdaptor.Stop(); err != nil {
 			log.Error(err, "Failed to stop stale adaptor", "adaptor", staleAdaptor.GetName())
 		}
+		if staleAdaptor.GetEndpoint() != adaptor.GetEndpoint() {
+			c.index.Delete(staleAdaptor.GetEndpoint())
+		}
 		c.index.Store(adaptor.GetName(), adaptor)
 	}
 	c.index.Store(adaptor.GetEndpoint(), adaptor)
 }
 
+// List returns all adaptors.
+func (c Adaptors) List() []Adaptor {
+	var ret []Adaptor
+	c.index.Range(func(nameOrEndpoint, aa interface{}) bool {
+		// each adaptor is indexed by both name and endpoint.
+		var adaptor = aa.(Adaptor)
+		if adaptor.GetName() == nameOrEndpoint {
+			ret = append(ret, adaptor)
+		}
+		return true
+	})
+	return ret
+}
+
 func (c Adaptors) Cleanup() {
 	c.index.Range(func(nameOrEndpoint, aa interface{}) bool {
 		// delete
@@ -53,6 +53,18 @@ func (c Connections) Put(conn Connection) (overwrite bool) {
 	return false
 }
 
+// Count returns the number of active connections
+func (c Connections) Count() int {
+	var count int
+	c.index.Range(func(_, cc interface{}) bool {
+		if !cc.(Connection).IsStop() {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
 // Cleanup cleans all connections of index
 func (c Connections) Cleanup() {
 	c.index.Range(func(name, cc interface{}) bool {
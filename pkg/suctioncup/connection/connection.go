@@ -4,6 +4,7 @@
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"go.uber.org/atomic"
@@ -22,8 +23,13 @@ type Connection interface {
 	// GetName returns the name of connection
 	GetName() types.NamespacedName
 
-	// Send sends the device model, desired data and references to connection
-	Send(model *metav1.TypeMeta, device []byte, references map[string]*api.ConnectRequestReferenceEntry) error
+	// Send sends the device model, desired data and references to connection,
+	// and waits for the response of adaptor within the given timeout.
+	Send(model *metav1.TypeMeta, device []byte, references map[string]*api.ConnectRequestReferenceEntry, timeout time.Duration) error
+
+	// Act sends the action to connection,
+	// and waits for the result of adaptor within the given timeout.
+	Act(model *metav1.TypeMeta, action *api.ConnectRequestAction, timeout time.Duration) (*api.ConnectResponseAction, error)
 
 	// Stop stops the connection
 	Stop() error
@@ -45,6 +51,7 @@ func NewConnection(adaptorName string, name types.NamespacedName, clientConn *gr
 		notifier:        notifier,
 		interruptSignal: make(chan struct{}),
 		interruptError:  make(chan error),
+		stopSignal:      make(chan struct{}),
 	}
 	go c.receive()
 	return c, nil
@@ -56,9 +63,12 @@ type connection struct {
 	name        types.NamespacedName
 	conn        api.Connection_ConnectClient
 	notifier    event.ConnectionNotifier
+	sendLock    sync.Mutex
+	actions     sync.Map
 
 	interruptSignal chan struct{}
 	interruptError  chan error
+	stopSignal      chan struct{}
 }
 
 func (c *connection) GetAdaptorName() string {
@@ -77,7 +87,7 @@ func (c *connection) IsStop() bool {
 	return c.stopped.Load()
 }
 
-func (c *connection) Send(model *metav1.TypeMeta, device []byte, references map[string]*api.ConnectRequestReferenceEntry) (err error) {
+func (c *connection) Send(model *metav1.TypeMeta, device []byte, references map[string]*api.ConnectRequestReferenceEntry, timeoutDuration time.Duration) (err error) {
 	defer func() {
 		if err != nil {
 			_ = c.stop()
@@ -92,7 +102,7 @@ func (c *connection) Send(model *metav1.TypeMeta, device []byte, references map[
 		}()
 		c.interruptSignal <- struct{}{}
 	}()
-	if err = c.conn.Send(&api.ConnectRequest{
+	if err = c.send(&api.ConnectRequest{
 		Model:      model,
 		Device:     device,
 		References: references,
@@ -100,8 +110,6 @@ func (c *connection) Send(model *metav1.TypeMeta, device []byte, references map[
 		return
 	}
 
-	// TODO should we parameterize the sending timeout?
-	var timeoutDuration = 90 * time.Second
 	var timeout = time.NewTimer(timeoutDuration)
 	defer timeout.Stop()
 	select {
@@ -112,12 +120,52 @@ func (c *connection) Send(model *metav1.TypeMeta, device []byte, references map[
 	}
 }
 
+func (c *connection) Act(model *metav1.TypeMeta, action *api.ConnectRequestAction, timeoutDuration time.Duration) (*api.ConnectResponseAction, error) {
+	if c.IsStop() {
+		return nil, errors.New("connection has been stopped")
+	}
+
+	// the result of action is dispatched by the ID,
+	// so it doesn't interrupt the sending of desired device.
+	var resultC = make(chan *api.ConnectResponseAction, 1)
+	c.actions.Store(action.GetId(), resultC)
+	defer c.actions.Delete(action.GetId())
+
+	if err := c.send(&api.ConnectRequest{
+		Model:  model,
+		Action: action,
+	}); err != nil {
+		return nil, err
+	}
+
+	var timeout = time.NewTimer(timeoutDuration)
+	defer timeout.Stop()
+	select {
+	case result := <-resultC:
+		return result, nil
+	case <-c.stopSignal:
+		return nil, errors.New("connection has been stopped")
+	case <-timeout.C:
+		return nil, fmt.Errorf("timeout to act in %v", timeoutDuration)
+	}
+}
+
+func (c *connection) send(req *api.ConnectRequest) error {
+	// it is not safe to call Send on the same stream in different goroutines.
+	c.sendLock.Lock()
+	defer c.sendLock.Unlock()
+	return c.conn.Send(req)
+}
+
 func (c *connection) stop() error {
 	var err error
 	if c.stopped.CAS(false, true) {
+		c.sendLock.Lock()
 		err = c.conn.CloseSend()
+		c.sendLock.Unlock()
 		close(c.interruptSignal)
 		close(c.interruptError)
+		close(c.stopSignal)
 	}
 	return err
 }
@@ -127,6 +175,16 @@ func (c *connection) receive() {
 
 	for {
 		var resp, err = c.conn.Recv()
+		if action := resp.GetAction(); err == nil && action != nil {
+			if resultC, exist := c.actions.Load(action.GetId()); exist {
+				select {
+				case resultC.(chan *api.ConnectResponseAction) <- action:
+				default:
+				}
+			}
+			continue
+		}
+
 		select {
 		case _, active := <-c.interruptSignal:
 			if !active {
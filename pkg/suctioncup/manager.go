@@ -17,14 +17,16 @@
 
 var log = ctrl.Log.WithName("suctioncup").WithName("manager")
 
-func NewManager() (Manager, error) {
+// NewManager creates the suction cup manager,
+// the gracePeriod specifies how long to wait for an adaptor to register again after its socket is removed.
+func NewManager(gracePeriod time.Duration) (Manager, error) {
 	var adaptors = adaptor.NewAdaptors()
 	var queue = event.NewQueue()
-	return NewManagerWith(adaptors, queue)
+	return NewManagerWith(gracePeriod, adaptors, queue)
 }
 
-func NewManagerWith(adaptors adaptor.Adaptors, queue event.Queue) (Manager, error) {
-	var regSrv, err = registration.NewServer(api.LimbSocket, adaptors, queue)
+func NewManagerWith(gracePeriod time.Duration, adaptors adaptor.Adaptors, queue event.Queue) (Manager, error) {
+	var regSrv, err = registration.NewServer(api.LimbSocket, gracePeriod, adaptors, queue)
 	if err != nil {
 		return nil, err
 	}
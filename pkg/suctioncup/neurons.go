@@ -1,15 +1,18 @@
 package suctioncup
 
 import (
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 
 	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
 	api "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
 	"github.com/rancher/octopus/pkg/metrics"
+	"github.com/rancher/octopus/pkg/suctioncup/adaptor"
 	"github.com/rancher/octopus/pkg/suctioncup/connection"
 	"github.com/rancher/octopus/pkg/util/object"
 )
@@ -18,7 +21,35 @@ func (m *manager) ExistAdaptor(name string) bool {
 	return m.adaptors.Get(name) != nil
 }
 
-func (m *manager) Connect(referencesData map[string]map[string][]byte, device *unstructured.Unstructured, by *edgev1alpha1.DeviceLink) error {
+func (m *manager) SupportModel(name string, model metav1.TypeMeta) bool {
+	var adaptor = m.adaptors.Get(name)
+	if adaptor == nil {
+		return false
+	}
+	return adaptor.IsModelSupported(model)
+}
+
+func (m *manager) GetAdaptors() []AdaptorInfo {
+	var adaptors = m.adaptors.List()
+	var ret = make([]AdaptorInfo, 0, len(adaptors))
+	for _, adaptor := range adaptors {
+		ret = append(ret, AdaptorInfo{
+			Name:             adaptor.GetName(),
+			Version:          adaptor.GetVersion(),
+			Endpoint:         adaptor.GetEndpoint(),
+			Models:           adaptor.GetModels(),
+			Features:         adaptor.GetFeatures(),
+			Connections:      adaptor.GetConnectionCount(),
+			RegistrationTime: adaptor.GetRegistrationTime(),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+func (m *manager) Connect(references map[string]*api.ConnectRequestReferenceEntry, device *unstructured.Unstructured, by *edgev1alpha1.DeviceLink) error {
 	var adaptorName = by.Status.AdaptorName
 	if adaptorName == "" {
 		return errors.New("adaptor name is empty")
@@ -28,6 +59,21 @@ func (m *manager) Connect(referencesData map[string]map[string][]byte, device *u
 		return errors.Errorf("cannot find adaptor %s", adaptorName)
 	}
 
+	var deviceName = object.GetNamespacedName(by)
+	var sendModel = by.Status.Model
+	var sendDevice, err = cleanupDevice(device).MarshalJSON()
+	if err != nil {
+		return errors.Wrapf(err, "cannot marshal device %s as JSON", deviceName)
+	}
+	// a failed sending stops the connection, and the next sending creates a new one,
+	// the retries are scheduled by the caller according to the send policy.
+	return m.send(adaptor, deviceName, sendModel, sendDevice, references, by.Spec.SendPolicy.GetTimeout())
+}
+
+// send creates a connection of the adaptor if needed, and then sends the data via the connection.
+func (m *manager) send(adp adaptor.Adaptor, deviceName types.NamespacedName, model *metav1.TypeMeta, device []byte, references map[string]*api.ConnectRequestReferenceEntry, timeout time.Duration) error {
+	var adaptorName = adp.GetName()
+
 	// records metrics
 	var (
 		overwritten  bool
@@ -41,9 +87,8 @@ func (m *manager) Connect(referencesData map[string]map[string][]byte, device *u
 		}
 	}()
 
-	var deviceName = object.GetNamespacedName(by)
 	var conn connection.Connection
-	overwritten, conn, connectedErr = adaptor.CreateConnection(deviceName)
+	overwritten, conn, connectedErr = adp.CreateConnection(deviceName)
 	if connectedErr != nil {
 		return errors.Wrapf(connectedErr, "cannot to link device %s via adaptor", deviceName)
 	}
@@ -60,26 +105,7 @@ func (m *manager) Connect(referencesData map[string]map[string][]byte, device *u
 		}
 	}()
 
-	var sendModel = by.Status.Model
-	var sendDevice []byte
-	sendDevice, sentErr = cleanupDevice(device).MarshalJSON()
-	if sentErr != nil {
-		return errors.Wrapf(sentErr, "cannot marshal device %s as JSON", deviceName)
-	}
-	var sendReferences map[string]*api.ConnectRequestReferenceEntry
-	if len(referencesData) != 0 {
-		sendReferences = make(map[string]*api.ConnectRequestReferenceEntry, len(referencesData))
-		for rpName, rp := range referencesData {
-			var reference = &api.ConnectRequestReferenceEntry{
-				Items: make(map[string][]byte, len(rp)),
-			}
-			for ripName, rip := range rp {
-				reference.Items[ripName] = rip
-			}
-			sendReferences[rpName] = reference
-		}
-	}
-	sentErr = conn.Send(sendModel, sendDevice, sendReferences)
+	sentErr = conn.Send(model, device, references, timeout)
 	if sentErr != nil {
 		return errors.Wrapf(sentErr, "cannot send data to device %s via adaptor", deviceName)
 	}
@@ -106,6 +132,32 @@ func (m *manager) Disconnect(by *edgev1alpha1.DeviceLink) {
 	exist = adaptor.DeleteConnection(object.GetNamespacedName(by))
 }
 
+func (m *manager) Act(action *api.ConnectRequestAction, timeout time.Duration, by *edgev1alpha1.DeviceLink) (*api.ConnectResponseAction, error) {
+	var adaptorName = by.Status.AdaptorName
+	if adaptorName == "" {
+		return nil, errors.New("adaptor name is empty")
+	}
+	var adaptor = m.adaptors.Get(adaptorName)
+	if adaptor == nil {
+		return nil, errors.Errorf("cannot find adaptor %s", adaptorName)
+	}
+	if !adaptor.IsFeatureSupported(api.FeatureAction) {
+		return nil, errors.Errorf("adaptor %s doesn't support actions", adaptorName)
+	}
+
+	var deviceName = object.GetNamespacedName(by)
+	var conn = adaptor.GetConnection(deviceName)
+	if conn == nil {
+		return nil, errors.Errorf("cannot find the connection of device %s", deviceName)
+	}
+
+	var result, err = conn.Act(by.Status.Model, action, timeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot act %s on device %s via adaptor", action.GetName(), deviceName)
+	}
+	return result, nil
+}
+
 func cleanupDevice(device *unstructured.Unstructured) *unstructured.Unstructured {
 	device.SetGenerateName("")
 	device.SetSelfLink("")
@@ -28,7 +28,7 @@ type Server interface {
 	Start(<-chan struct{}) error
 }
 
-func NewServer(path string, adaptors adaptor.Adaptors, queue event.Queue) (Server, error) {
+func NewServer(path string, gracePeriod time.Duration, adaptors adaptor.Adaptors, queue event.Queue) (Server, error) {
 	var dir = filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, errors.Wrapf(err, "failed to create socket directory")
@@ -38,7 +38,7 @@ func NewServer(path string, adaptors adaptor.Adaptors, queue event.Queue) (Serve
 		return nil, errors.Wrapf(err, "failed to cleanup socket directory")
 	}
 
-	var socketWatcher, err = newSocketWatcher(log.WithName("watcher"), dir, adaptors, queue.GetAdaptorNotifier())
+	var socketWatcher, err = newSocketWatcher(log.WithName("watcher"), dir, gracePeriod, adaptors, queue.GetAdaptorNotifier())
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create socket watcher")
 	}
@@ -103,7 +103,7 @@ func (s *server) Register(_ context.Context, req *api.RegisterRequest) (*api.Emp
 		return &api.Empty{}, grpcstatus.Error(grpccodes.InvalidArgument, err.Error())
 	}
 
-	var adp, err = adaptor.NewAdaptor(api.AdaptorPath, req.Name, req.Endpoint, s.connNotifier)
+	var adp, err = adaptor.NewAdaptor(api.AdaptorPath, req, s.connNotifier)
 	if err != nil {
 		log.Error(err, "Unable to connect adaptor")
 		return &api.Empty{}, grpcstatus.Errorf(grpcstatus.Code(err), "could not connect the registering adaptor %s", req.Name)
@@ -165,5 +165,10 @@ func validate(req *api.RegisterRequest) error {
 	if !validation.IsQualifiedName(req.Name) {
 		return errors.Errorf("the requested name %s is not qualified", req.Name)
 	}
+	for _, model := range req.Models {
+		if model == nil || model.APIVersion == "" || model.Kind == "" {
+			return errors.Errorf("the requested model %v is not qualified", model)
+		}
+	}
 	return nil
 }
@@ -2,6 +2,7 @@
 
 import (
 	"path/filepath"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/go-logr/logr"
@@ -11,26 +12,28 @@
 	"github.com/rancher/octopus/pkg/suctioncup/event"
 )
 
-func newSocketWatcher(log logr.Logger, dir string, adaptors adaptor.Adaptors, adaptorNotifier event.AdaptorNotifier) (*socketWatcher, error) {
+func newSocketWatcher(log logr.Logger, dir string, gracePeriod time.Duration, adaptors adaptor.Adaptors, adaptorNotifier event.AdaptorNotifier) (*socketWatcher, error) {
 	var fsWatcher, err = fsnotify.NewWatcher()
 	if err != nil {
 		return nil, err
 	}
 
 	return &socketWatcher{
-		log:       log,
-		dir:       dir,
-		set:       adaptors,
-		notifier:  adaptorNotifier,
-		fsWatcher: fsWatcher,
+		log:         log,
+		dir:         dir,
+		gracePeriod: gracePeriod,
+		set:         adaptors,
+		notifier:    adaptorNotifier,
+		fsWatcher:   fsWatcher,
 	}, nil
 }
 
 type socketWatcher struct {
-	log      logr.Logger
-	dir      string
-	set      adaptor.Adaptors
-	notifier event.AdaptorNotifier
+	log         logr.Logger
+	dir         string
+	gracePeriod time.Duration
+	set         adaptor.Adaptors
+	notifier    event.AdaptorNotifier
 
 	fsWatcher *fsnotify.Watcher
 }
@@ -62,10 +65,9 @@ func (w *socketWatcher) Start(stop <-chan struct{}) {
 				var path = ent.Name
 				var _, endpoint = filepath.Split(path)
 				if adp := w.set.Get(endpoint); adp != nil {
-					w.notifier.NoticeAdaptorUnregistered(adp.GetName())
-					w.set.Delete(adp.GetEndpoint())
 					_ = w.fsWatcher.Remove(path)
 					w.log.V(2).Info("Unwatching path", "path", path)
+					w.leave(adp)
 				}
 			}
 		case err, ok := <-w.fsWatcher.Errors:
@@ -81,3 +83,25 @@ func (w *socketWatcher) Start(stop <-chan struct{}) {
 		}
 	}
 }
+
+// leave unregisters the adaptor after the grace period,
+// if the same name adaptor registers again within the grace period (e.g. rolling upgrade),
+// the stale adaptor is replaced silently and the unregistration is skipped.
+func (w *socketWatcher) leave(adp adaptor.Adaptor) {
+	adp.Leave()
+
+	var unregister = func() {
+		if w.set.DeleteIfStale(adp) {
+			w.notifier.NoticeAdaptorUnregistered(adp.GetName())
+			w.log.V(2).Info("Unregistered adaptor", "adaptor", adp.GetName())
+			return
+		}
+		w.log.V(2).Info("Adaptor has registered again within the grace period", "adaptor", adp.GetName())
+	}
+	if w.gracePeriod <= 0 {
+		unregister()
+		return
+	}
+	w.log.V(2).Info("Waiting for adaptor to register again", "adaptor", adp.GetName(), "gracePeriod", w.gracePeriod)
+	time.AfterFunc(w.gracePeriod, unregister)
+}
@@ -0,0 +1,151 @@
+package registration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+
+	"github.com/rancher/octopus/pkg/suctioncup/adaptor"
+	"github.com/rancher/octopus/pkg/util/log/zap"
+)
+
+type fakeAdaptor struct {
+	adaptor.Adaptor
+
+	name     string
+	endpoint string
+	leaving  atomic.Bool
+	stopped  atomic.Bool
+}
+
+func (a *fakeAdaptor) GetName() string {
+	return a.name
+}
+
+func (a *fakeAdaptor) GetEndpoint() string {
+	return a.endpoint
+}
+
+func (a *fakeAdaptor) Stop() error {
+	a.stopped.Store(true)
+	return nil
+}
+
+func (a *fakeAdaptor) Leave() {
+	a.leaving.Store(true)
+}
+
+func (a *fakeAdaptor) IsLeaving() bool {
+	return a.leaving.Load()
+}
+
+type fakeAdaptorNotifier struct {
+	sync.Mutex
+	registered   []string
+	unregistered []string
+}
+
+func (n *fakeAdaptorNotifier) NoticeAdaptorRegistered(name string) {
+	n.Lock()
+	defer n.Unlock()
+	n.registered = append(n.registered, name)
+}
+
+func (n *fakeAdaptorNotifier) NoticeAdaptorUnregistered(name string) {
+	n.Lock()
+	defer n.Unlock()
+	n.unregistered = append(n.unregistered, name)
+}
+
+func (n *fakeAdaptorNotifier) getUnregistered() []string {
+	n.Lock()
+	defer n.Unlock()
+	return n.unregistered
+}
+
+func newTestSocketWatcher(t *testing.T, gracePeriod time.Duration) (*socketWatcher, *fakeAdaptorNotifier, func()) {
+	var dir, err = ioutil.TempDir("", "socket_watcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notifier = &fakeAdaptorNotifier{}
+	watcher, err := newSocketWatcher(zap.NewNullLogger(), dir, gracePeriod, adaptor.NewAdaptors(), notifier)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return watcher, notifier, func() {
+		_ = watcher.fsWatcher.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func watchTestAdaptor(t *testing.T, watcher *socketWatcher, adp *fakeAdaptor) {
+	if err := ioutil.WriteFile(filepath.Join(watcher.dir, adp.GetEndpoint()), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := watcher.Watch(adp); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSocketWatcher_leave(t *testing.T) {
+	var gracePeriod = 100 * time.Millisecond
+
+	// unregisters the adaptor once the grace period expires
+	t.Run("expired", func(t *testing.T) {
+		var watcher, notifier, cleanup = newTestSocketWatcher(t, gracePeriod)
+		defer cleanup()
+
+		var adp = &fakeAdaptor{name: "dummy", endpoint: "dummy.sock"}
+		watchTestAdaptor(t, watcher, adp)
+
+		watcher.leave(adp)
+		assert.True(t, adp.IsLeaving(), "the adaptor is draining within the grace period")
+		assert.Equal(t, adp, watcher.set.Get("dummy"), "the adaptor is kept within the grace period")
+		assert.Empty(t, notifier.getUnregistered())
+
+		time.Sleep(2 * gracePeriod)
+		assert.Nil(t, watcher.set.Get("dummy"))
+		assert.True(t, adp.stopped.Load())
+		assert.Equal(t, []string{"dummy"}, notifier.getUnregistered())
+	})
+
+	// skips the unregistration if the adaptor registers again within the grace period
+	t.Run("registered again", func(t *testing.T) {
+		var watcher, notifier, cleanup = newTestSocketWatcher(t, gracePeriod)
+		defer cleanup()
+
+		var adp = &fakeAdaptor{name: "dummy", endpoint: "dummy.sock"}
+		watchTestAdaptor(t, watcher, adp)
+
+		watcher.leave(adp)
+		var newAdp = &fakeAdaptor{name: "dummy", endpoint: "dummy-new.sock"}
+		watchTestAdaptor(t, watcher, newAdp)
+		assert.True(t, adp.stopped.Load(), "the stale adaptor is stopped")
+
+		time.Sleep(2 * gracePeriod)
+		assert.Equal(t, newAdp, watcher.set.Get("dummy"))
+		assert.False(t, newAdp.IsLeaving())
+		assert.Empty(t, notifier.getUnregistered())
+	})
+
+	// unregisters the adaptor immediately without the grace period
+	t.Run("without grace period", func(t *testing.T) {
+		var watcher, notifier, cleanup = newTestSocketWatcher(t, 0)
+		defer cleanup()
+
+		var adp = &fakeAdaptor{name: "dummy", endpoint: "dummy.sock"}
+		watchTestAdaptor(t, watcher, adp)
+
+		watcher.leave(adp)
+		assert.Nil(t, watcher.set.Get("dummy"))
+		assert.Equal(t, []string{"dummy"}, notifier.getUnregistered())
+	})
+}
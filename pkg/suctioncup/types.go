@@ -1,9 +1,13 @@
 package suctioncup
 
 import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+	api "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
 	"github.com/rancher/octopus/pkg/suctioncup/event"
 )
 
@@ -25,9 +29,36 @@ type Neurons interface {
 	// ExistAdaptor judges whether the adaptor of target exist.
 	ExistAdaptor(name string) bool
 
+	// SupportModel judges whether the adaptor of target serves the model.
+	SupportModel(name string, model metav1.TypeMeta) bool
+
+	// GetAdaptors returns the information of all registered adaptors, which are sorted by name.
+	GetAdaptors() []AdaptorInfo
+
 	// Connect starts a connection by link, the return "overwrite" represents whether to overwrite an existing connection.
-	Connect(referencesData map[string]map[string][]byte, device *unstructured.Unstructured, by *edgev1alpha1.DeviceLink) error
+	Connect(references map[string]*api.ConnectRequestReferenceEntry, device *unstructured.Unstructured, by *edgev1alpha1.DeviceLink) error
 
 	// Disconnect stops a connection by link
 	Disconnect(by *edgev1alpha1.DeviceLink)
+
+	// Act calls an action on the connection of link and returns the result of adaptor.
+	Act(action *api.ConnectRequestAction, timeout time.Duration, by *edgev1alpha1.DeviceLink) (*api.ConnectResponseAction, error)
+}
+
+// AdaptorInfo describes a registered adaptor.
+type AdaptorInfo struct {
+	// Name of the adaptor.
+	Name string `json:"name"`
+	// API version of the adaptor.
+	Version string `json:"version"`
+	// Socket endpoint of the adaptor.
+	Endpoint string `json:"endpoint"`
+	// Models declared by the adaptor.
+	Models []metav1.TypeMeta `json:"models,omitempty"`
+	// Features declared by the adaptor.
+	Features []string `json:"features,omitempty"`
+	// Number of active connections of the adaptor.
+	Connections int `json:"connections"`
+	// Time when the adaptor was registered.
+	RegistrationTime time.Time `json:"registrationTime"`
 }
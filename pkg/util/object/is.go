@@ -1,6 +1,7 @@
 package object
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -21,3 +22,15 @@ func IsDeleted(obj metav1.Object) bool {
 	}
 	return !obj.GetDeletionTimestamp().IsZero()
 }
+
+func IsNodeReady(node *corev1.Node) bool {
+	if node == nil || !IsActivating(node) {
+		return false
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
@@ -113,3 +114,74 @@ func TestIsActivating(t *testing.T) {
 		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
 	}
 }
+
+func TestIsNodeReady(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		given    *corev1.Node
+		expected bool
+	}{
+		{
+			name:     "nil instance",
+			given:    nil,
+			expected: false,
+		},
+		{
+			name: "ready instance",
+			given: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "edge-worker",
+				},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{
+						{
+							Type:   corev1.NodeReady,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "not ready instance",
+			given: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "edge-worker",
+				},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{
+						{
+							Type:   corev1.NodeReady,
+							Status: corev1.ConditionUnknown,
+						},
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "deleted instance",
+			given: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "edge-worker",
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{
+						{
+							Type:   corev1.NodeReady,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = IsNodeReady(tc.given)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
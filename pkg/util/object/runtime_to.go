@@ -17,6 +17,24 @@ func ToDeviceLinkObject(obj runtime.Object) *edgev1alpha1.DeviceLink {
 	return nil
 }
 
+func ToDeviceActionObject(obj runtime.Object) *edgev1alpha1.DeviceAction {
+	if obj != nil {
+		if r, ok := obj.(*edgev1alpha1.DeviceAction); ok {
+			return r
+		}
+	}
+	return nil
+}
+
+func ToNodeAdaptorObject(obj runtime.Object) *edgev1alpha1.NodeAdaptor {
+	if obj != nil {
+		if r, ok := obj.(*edgev1alpha1.NodeAdaptor); ok {
+			return r
+		}
+	}
+	return nil
+}
+
 func ToNodeObject(obj runtime.Object) *corev1.Node {
 	if obj != nil {
 		if r, ok := obj.(*corev1.Node); ok {
@@ -50,6 +50,45 @@ func TestToDeviceLinkObject(t *testing.T) {
 	}
 }
 
+func TestToDeviceActionObject(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		given    runtime.Object
+		expected *edgev1alpha1.DeviceAction
+	}{
+		{
+			name: "DeviceAction instance",
+			given: &edgev1alpha1.DeviceAction{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test",
+				},
+			},
+			expected: &edgev1alpha1.DeviceAction{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test",
+				},
+			},
+		},
+		{
+			name: "non-DeviceAction instance",
+			given: &edgev1alpha1.DeviceLink{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test",
+				},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		var actual = ToDeviceActionObject(tc.given)
+		assert.Equal(t, tc.expected, actual, "case %q", tc.name)
+	}
+}
+
 func TestToNodeObject(t *testing.T) {
 	var testCases = []struct {
 		name     string
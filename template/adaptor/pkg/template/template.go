@@ -2,6 +2,7 @@
 
 import (
 	"golang.org/x/sync/errgroup"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	api "github.com/rancher/octopus/pkg/adaptor/api/v1alpha1"
@@ -9,6 +10,7 @@
 	"github.com/rancher/octopus/pkg/adaptor/log"
 	"github.com/rancher/octopus/pkg/adaptor/registration"
 	"github.com/rancher/octopus/pkg/util/critical"
+	"github.com/rancher/octopus/template/adaptor/api/v1alpha1"
 	"github.com/rancher/octopus/template/adaptor/pkg/adaptor"
 )
 
@@ -38,6 +40,9 @@ func Run() error {
 			Name:     Name,
 			Version:  Version,
 			Endpoint: Endpoint,
+			Models: []*metav1.TypeMeta{
+				{APIVersion: v1alpha1.GroupVersion.String(), Kind: "TemplateDevice"},
+			},
 		})
 	})
 	return eg.Wait()
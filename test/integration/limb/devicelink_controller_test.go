@@ -2,6 +2,7 @@
 
 import (
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -336,6 +337,34 @@ func (a fakeAdaptor) GetEndpoint() string {
 	return "fake.sock"
 }
 
+func (a fakeAdaptor) GetVersion() string {
+	return "v1alpha1"
+}
+
+func (a fakeAdaptor) GetModels() []metav1.TypeMeta {
+	return nil
+}
+
+func (a fakeAdaptor) GetFeatures() []string {
+	return nil
+}
+
+func (a fakeAdaptor) IsModelSupported(model metav1.TypeMeta) bool {
+	return true
+}
+
+func (a fakeAdaptor) IsFeatureSupported(feature string) bool {
+	return true
+}
+
+func (a fakeAdaptor) GetRegistrationTime() time.Time {
+	return time.Time{}
+}
+
+func (a fakeAdaptor) GetConnectionCount() int {
+	return 0
+}
+
 func (a fakeAdaptor) Stop() error {
 	return nil
 }
@@ -348,6 +377,16 @@ func (a fakeAdaptor) DeleteConnection(name types.NamespacedName) bool {
 	return false
 }
 
+func (a fakeAdaptor) GetConnection(name types.NamespacedName) connection.Connection {
+	return fakeConnection(name)
+}
+
+func (a fakeAdaptor) Leave() {}
+
+func (a fakeAdaptor) IsLeaving() bool {
+	return false
+}
+
 type fakeConnection types.NamespacedName
 
 func (c fakeConnection) GetAdaptorName() string {
@@ -366,6 +405,10 @@ func (c fakeConnection) IsStop() bool {
 	return false
 }
 
-func (c fakeConnection) Send(*metav1.TypeMeta, []byte, map[string]*api.ConnectRequestReferenceEntry) error {
+func (c fakeConnection) Send(*metav1.TypeMeta, []byte, map[string]*api.ConnectRequestReferenceEntry, time.Duration) error {
 	return nil
 }
+
+func (c fakeConnection) Act(_ *metav1.TypeMeta, action *api.ConnectRequestAction, _ time.Duration) (*api.ConnectResponseAction, error) {
+	return &api.ConnectResponseAction{Id: action.GetId()}, nil
+}
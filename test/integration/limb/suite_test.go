@@ -103,7 +103,7 @@ func TestLimb(t *testing.T) {
 	By("starting suctioncup manager")
 	testAdaptors = adaptor.NewAdaptors()
 	testEventQueue = event.NewQueue()
-	suctionCupMgr, err := suctioncup.NewManagerWith(testAdaptors, testEventQueue)
+	suctionCupMgr, err := suctioncup.NewManagerWith(0, testAdaptors, testEventQueue)
 	Expect(err).ToNot(HaveOccurred())
 
 	By("creating controllers")